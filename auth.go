@@ -0,0 +1,83 @@
+package neogo
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
+)
+
+// WithAuraProfile is an option for [New] that tunes the driver for Neo4J Aura.
+// Connections require TLS 1.2, are checked before reuse once idle for a
+// minute, and are recycled every 30 minutes, before the Aura load balancers
+// drop them. The pool is limited to 50 connections, which Aura instances
+// accept comfortably. Options given after it take precedence.
+//
+//	neogo.New("neo4j+s://xxxxxxxx.databases.neo4j.io", auth, neogo.WithAuraProfile())
+func WithAuraProfile() Configurer {
+	return func(c *Config) {
+		c.TlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		c.MaxConnectionPoolSize = 50
+		c.MaxConnectionLifetime = 30 * time.Minute
+		c.ConnectionLivenessCheckTimeout = time.Minute
+		c.ConnectionAcquisitionTimeout = 30 * time.Second
+		c.SocketConnectTimeout = 10 * time.Second
+		c.SocketKeepalive = true
+	}
+}
+
+// AuthProvider provides the credentials a driver authenticates with, i.e.
+// Kerberos tickets or credentials issued by a vault. See [ProviderAuth].
+type AuthProvider interface {
+	// Token returns the credentials used for new connections. It must not use
+	// the driver, and must always return credentials of the same identity.
+	Token(ctx context.Context) (neo4j.AuthToken, error)
+}
+
+// AuthProviderFunc is a function implementing [AuthProvider].
+type AuthProviderFunc func(ctx context.Context) (neo4j.AuthToken, error)
+
+func (f AuthProviderFunc) Token(ctx context.Context) (neo4j.AuthToken, error) {
+	return f(ctx)
+}
+
+// ProviderAuth returns the auth of a driver authenticating with the
+// credentials of provider. The provider is asked for credentials for the first
+// connection, and again whenever the server reports them expired or rejects
+// them, so they are rotated without recreating the driver.
+//
+//	neogo.New(target, neogo.ProviderAuth(neogo.AuthProviderFunc(
+//		func(ctx context.Context) (neo4j.AuthToken, error) {
+//			ticket, err := kerberosTicket(ctx)
+//			return neo4j.KerberosAuth(ticket), err
+//		},
+//	)))
+func ProviderAuth(provider AuthProvider) auth.TokenManager {
+	return auth.BearerTokenManager(func(ctx context.Context) (neo4j.AuthToken, *time.Time, error) {
+		token, err := provider.Token(ctx)
+		return token, nil, err
+	})
+}
+
+// BearerAuth returns the auth of a driver authenticating with bearer tokens,
+// i.e. issued by an OIDC provider for SSO. refresh is called for the first
+// connection, before the expiry it returns, and whenever the token is
+// rejected, so tokens are rotated without recreating the driver. A zero
+// expiresAt means the token is used until it is rejected.
+//
+// refresh must not use the driver, and must always return tokens of the same
+// identity.
+func BearerAuth(refresh func(ctx context.Context) (token string, expiresAt time.Time, err error)) auth.TokenManager {
+	return auth.BearerTokenManager(func(ctx context.Context) (neo4j.AuthToken, *time.Time, error) {
+		token, expiresAt, err := refresh(ctx)
+		if err != nil {
+			return neo4j.AuthToken{}, nil, err
+		}
+		if expiresAt.IsZero() {
+			return neo4j.BearerAuth(token), nil, nil
+		}
+		return neo4j.BearerAuth(token), &expiresAt, nil
+	})
+}
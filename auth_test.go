@@ -0,0 +1,73 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAuraProfile(t *testing.T) {
+	cfg := &Config{Config: *defaultConfig()}
+	WithAuraProfile()(cfg)
+	require.NotNil(t, cfg.TlsConfig)
+	assert.Equal(t, 50, cfg.MaxConnectionPoolSize)
+	assert.Equal(t, 30*time.Minute, cfg.MaxConnectionLifetime)
+	assert.Equal(t, time.Minute, cfg.ConnectionLivenessCheckTimeout)
+}
+
+func TestBearerAuth(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	manager := BearerAuth(func(context.Context) (string, time.Time, error) {
+		calls++
+		if calls > 2 {
+			return "", time.Time{}, errors.New("provider unavailable")
+		}
+		return "token", time.Now().Add(-time.Second), nil
+	})
+
+	token, err := manager.GetAuthToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "bearer", token.Tokens["scheme"])
+	assert.Equal(t, "token", token.Tokens["credentials"])
+
+	// The token has expired, so it is refreshed.
+	_, err = manager.GetAuthToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	_, err = manager.GetAuthToken(ctx)
+	assert.ErrorContains(t, err, "provider unavailable")
+}
+
+func TestProviderAuth(t *testing.T) {
+	ctx := context.Background()
+	tickets := []string{"ticket1", "ticket2"}
+	manager := ProviderAuth(AuthProviderFunc(func(context.Context) (neo4j.AuthToken, error) {
+		ticket := tickets[0]
+		tickets = tickets[1:]
+		return neo4j.KerberosAuth(ticket), nil
+	}))
+
+	token, err := manager.GetAuthToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ticket1", token.Tokens["credentials"])
+
+	// Credentials are cached until the server reports them expired.
+	token, err = manager.GetAuthToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ticket1", token.Tokens["credentials"])
+
+	handled, err := manager.HandleSecurityException(ctx, token, &db.Neo4jError{Code: "Neo.ClientError.Security.TokenExpired"})
+	require.NoError(t, err)
+	assert.True(t, handled)
+	token, err = manager.GetAuthToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ticket2", token.Tokens["credentials"])
+}
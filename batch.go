@@ -0,0 +1,150 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/rlch/neogo/query"
+)
+
+// Statement builds one statement of a batch run with [RunBatch] or
+// [RunBatchEach] from q.
+//
+//	func(q neogo.Query) query.Runner {
+//		return q.Create(db.Node(&person))
+//	}
+type Statement func(q Query) query.Runner
+
+// StatementError is the failure of a statement in a batch.
+type StatementError struct {
+	// Index is the index of the statement in the batch. It is -1 if the
+	// transaction of [RunBatch] failed to commit, rather than a statement.
+	Index int
+	// Cypher is the compiled Cypher of the statement, unless its transaction
+	// failed to commit.
+	Cypher string
+	// Code is the Neo4J status code of the error, i.e.
+	// Neo.ClientError.Schema.ConstraintValidationFailed, if it was raised by
+	// Neo4J.
+	Code string
+	// RolledBack reports whether the transaction of the statement was rolled
+	// back, so none of its writes were committed. It is false if the outcome
+	// of a failed commit is unknown.
+	RolledBack bool
+	Err        error
+}
+
+func (e *StatementError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("cannot commit batch: %v", e.Err)
+	}
+	if e.Cypher == "" {
+		return fmt.Sprintf("cannot commit statement %d: %v", e.Index, e.Err)
+	}
+	return fmt.Sprintf("statement %d: %v", e.Index, e.Err)
+}
+
+func (e *StatementError) Unwrap() error { return e.Err }
+
+// BatchError is returned when statements of a batch fail, reporting each
+// failed statement so they can be retried.
+type BatchError struct {
+	Statements []*StatementError
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Statements))
+	for i, s := range e.Statements {
+		msgs[i] = s.Error()
+	}
+	return fmt.Sprintf("batch failed: %s", strings.Join(msgs, "; "))
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Statements))
+	for i, s := range e.Statements {
+		errs[i] = s
+	}
+	return errs
+}
+
+// RunBatch runs the statements in order, in a single write transaction of
+// session. If a statement fails the transaction is rolled back, and a
+// [*BatchError] reporting the statement is returned.
+func RunBatch(ctx context.Context, session WriteSession, statements ...Statement) error {
+	var failed *StatementError
+	err := session.WriteTransaction(ctx, func(start func() Query) error {
+		failed = nil
+		for i, statement := range statements {
+			if failed = runStatement(ctx, start, i, statement); failed != nil {
+				return failed
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+	if failed == nil {
+		failed = &StatementError{Index: -1, Code: neo4jCode(err), Err: err}
+	} else {
+		failed.RolledBack = true
+	}
+	return &BatchError{Statements: []*StatementError{failed}}
+}
+
+// RunBatchEach runs each of the statements in order, in its own write
+// transaction of session, continuing past those which fail. A [*BatchError]
+// reporting every failed statement is returned, while the others are
+// committed.
+func RunBatchEach(ctx context.Context, session WriteSession, statements ...Statement) error {
+	var errs []*StatementError
+	for i, statement := range statements {
+		var failed *StatementError
+		err := session.WriteTransaction(ctx, func(start func() Query) error {
+			if failed = runStatement(ctx, start, i, statement); failed != nil {
+				return failed
+			}
+			return nil
+		})
+		if err == nil {
+			continue
+		}
+		if failed == nil {
+			failed = &StatementError{Index: i, Code: neo4jCode(err), Err: err}
+		} else {
+			failed.RolledBack = true
+		}
+		errs = append(errs, failed)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BatchError{Statements: errs}
+}
+
+func runStatement(ctx context.Context, start func() Query, i int, statement Statement) *StatementError {
+	runner := statement(start())
+	if err := runner.Run(ctx); err != nil {
+		return &StatementError{
+			Index:  i,
+			Cypher: runner.String(),
+			Code:   neo4jCode(err),
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// neo4jCode returns the Neo4J status code of err, if it was raised by Neo4J.
+func neo4jCode(err error) string {
+	var neo4jErr *neo4j.Neo4jError
+	if errors.As(err, &neo4jErr) {
+		return neo4jErr.Code
+	}
+	return ""
+}
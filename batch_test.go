@@ -0,0 +1,83 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+	"github.com/rlch/neogo/query"
+)
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	newDriver := func() mockDriver {
+		m := NewMock()
+		m.(*mockDriverImpl).paramTransformers = []ParamTransformer{
+			ParamTransformerFunc(func(ctx context.Context, params map[string]any) (map[string]any, error) {
+				for _, v := range params {
+					if v == "fail" {
+						return nil, &neo4j.Neo4jError{Code: "Neo.ClientError.Schema.ConstraintValidationFailed"}
+					}
+				}
+				return params, nil
+			}),
+		}
+		return m
+	}
+	create := func(name string) Statement {
+		return func(q Query) query.Runner {
+			p := tests.Person{Name: name}
+			return q.Create(db.Node(db.Qual(&p, "p")))
+		}
+	}
+
+	t.Run("runs statements in a single transaction", func(t *testing.T) {
+		d := newDriver()
+		d.Bind(map[string]any{})
+		d.Bind(map[string]any{})
+		session := d.WriteSession(ctx)
+		defer session.Close(ctx)
+		require.NoError(t, RunBatch(ctx, session, create("a"), create("b")))
+	})
+
+	t.Run("reports the statement which failed a batch", func(t *testing.T) {
+		d := newDriver()
+		d.Bind(map[string]any{})
+		session := d.WriteSession(ctx)
+		defer session.Close(ctx)
+		err := RunBatch(ctx, session, create("a"), create("fail"), create("c"))
+
+		var batchErr *BatchError
+		require.ErrorAs(t, err, &batchErr)
+		require.Len(t, batchErr.Statements, 1)
+		failed := batchErr.Statements[0]
+		require.Equal(t, 1, failed.Index)
+		require.Equal(t, "CREATE (p:Person {name: $p_name})", failed.Cypher)
+		require.Equal(t, "Neo.ClientError.Schema.ConstraintValidationFailed", failed.Code)
+		require.True(t, failed.RolledBack)
+
+		var neo4jErr *neo4j.Neo4jError
+		require.True(t, errors.As(err, &neo4jErr))
+	})
+
+	t.Run("reports each failed statement of a batch run separately", func(t *testing.T) {
+		d := newDriver()
+		d.Bind(map[string]any{})
+		d.Bind(map[string]any{})
+		session := d.WriteSession(ctx)
+		defer session.Close(ctx)
+		err := RunBatchEach(ctx, session, create("fail"), create("b"), create("fail"), create("d"))
+
+		var batchErr *BatchError
+		require.ErrorAs(t, err, &batchErr)
+		require.Len(t, batchErr.Statements, 2)
+		require.Equal(t, 0, batchErr.Statements[0].Index)
+		require.Equal(t, 2, batchErr.Statements[1].Index)
+		require.True(t, batchErr.Statements[1].RolledBack)
+	})
+}
@@ -0,0 +1,129 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// ErrNoBlobStore is returned when reading a property offloaded to a blob
+// store without a [BlobStore].
+var ErrNoBlobStore = errors.New("external property read without a BlobStore")
+
+// blobRefPrefix prefixes the references to blobs stored in place of the
+// properties offloaded to a [BlobStore].
+const blobRefPrefix = "neogo-blob:"
+
+// BlobStore stores the values of the string and []byte fields tagged with
+// `neogo:"external"` which are too large to be stored as properties, i.e. in
+// object storage. Both methods are called with the context of the query
+// writing or reading the values.
+type BlobStore interface {
+	// Put stores data, returning the reference it can be retrieved with. It is
+	// called once each time a query is run, before its transaction begins.
+	Put(ctx context.Context, data []byte) (ref string, err error)
+	// Get returns the data stored with ref.
+	Get(ctx context.Context, ref string) ([]byte, error)
+}
+
+// WithBlobStore is an option for [New] that offloads the values of the string
+// and []byte fields tagged with `neogo:"external"` which are longer than
+// threshold bytes to store, writing a reference to them in their place. The
+// values are retrieved from store when they are read:
+//
+//	type Figure struct {
+//		neogo.Node `neo4j:"Figure"`
+//
+//		Caption string `json:"caption"`
+//		Content []byte `json:"content" neogo:"external"`
+//	}
+//
+// Values which are not longer than threshold are stored as properties, unless
+// they could be mistaken for a reference. Offloaded properties cannot be
+// compared in queries, and their blobs are not deleted with their entities.
+//
+// Blobs are stored before the transaction of the query writing them begins,
+// and are shared by its retries, but are not deleted if it fails, so a failed
+// query orphans the blobs it stored. Stores should expire, or periodically
+// collect, blobs which are no longer referenced. Queries run with Explain
+// don't store blobs.
+func WithBlobStore(store BlobStore, threshold int) Configurer {
+	return func(c *Config) {
+		c.BlobStore = store
+		c.BlobThreshold = threshold
+	}
+}
+
+var externalType = reflect.TypeOf(internal.External{})
+
+// externalize offloads value, the value of an external field, to the blob
+// store if it is too large to be stored as a property, returning the value to
+// store in its place.
+func (r *registry) externalize(ctx context.Context, value any) (any, error) {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		if r.blobStore == nil || (len(v) <= r.blobThreshold && !strings.HasPrefix(v, blobRefPrefix)) {
+			return v, nil
+		}
+		data = []byte(v)
+	case []byte:
+		if r.blobStore == nil || len(v) <= r.blobThreshold {
+			return v, nil
+		}
+		data = v
+	default:
+		return value, nil
+	}
+	ref, err := r.blobStore.Put(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot store blob: %w", err)
+	}
+	return blobRefPrefix + ref, nil
+}
+
+// resolveExternalProps returns props with the references of the external
+// fields of the struct to points to replaced by the values they refer to.
+func (r *registry) resolveExternalProps(ctx context.Context, props map[string]any, to reflect.Value) (map[string]any, error) {
+	t := unwindType(to.Type())
+	if t.Kind() != reflect.Struct {
+		return props, nil
+	}
+	var resolved map[string]any
+	for _, f := range internal.UpsertFields(t) {
+		field := t.FieldByIndex(f.Index)
+		if !internal.IsExternal(field) {
+			continue
+		}
+		ref, ok := props[f.Property].(string)
+		if !ok || !strings.HasPrefix(ref, blobRefPrefix) {
+			continue
+		}
+		if r.blobStore == nil {
+			return nil, fmt.Errorf("cannot resolve %s: %w", f.Property, ErrNoBlobStore)
+		}
+		data, err := r.blobStore.Get(ctx, strings.TrimPrefix(ref, blobRefPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %s: %w", f.Property, err)
+		}
+		if resolved == nil {
+			resolved = make(map[string]any, len(props))
+			for k, v := range props {
+				resolved[k] = v
+			}
+		}
+		if unwindType(field.Type).Kind() == reflect.String {
+			resolved[f.Property] = string(data)
+		} else {
+			resolved[f.Property] = data
+		}
+	}
+	if resolved == nil {
+		return props, nil
+	}
+	return resolved, nil
+}
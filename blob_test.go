@@ -0,0 +1,146 @@
+package neogo
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+)
+
+type memoryBlobStore map[string][]byte
+
+func (s memoryBlobStore) Put(ctx context.Context, data []byte) (string, error) {
+	ref := strconv.Itoa(len(s))
+	s[ref] = data
+	return ref, nil
+}
+
+func (s memoryBlobStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	return s[ref], nil
+}
+
+// contextBlobStore records the context it was last called with.
+type contextBlobStore struct {
+	memoryBlobStore
+	ctx context.Context
+}
+
+func (s *contextBlobStore) Put(ctx context.Context, data []byte) (string, error) {
+	s.ctx = ctx
+	return s.memoryBlobStore.Put(ctx, data)
+}
+
+func (s *contextBlobStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	s.ctx = ctx
+	return s.memoryBlobStore.Get(ctx, ref)
+}
+
+type figure struct {
+	Node `neo4j:"Figure"`
+
+	Caption string `json:"caption" neogo:"external"`
+	Content []byte `json:"content" neogo:"external"`
+}
+
+func TestBlobStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("offloads large fields written through patterns", func(t *testing.T) {
+		store := memoryBlobStore{}
+		m, params := newParamsMock(func(d *driver) {
+			d.blobStore = store
+			d.blobThreshold = 4
+		})
+		f := figure{Caption: "Fig", Content: []byte("a large figure")}
+		require.NoError(t, m.Exec().Create(db.Node(db.Qual(&f, "f"))).Run(ctx))
+		require.Equal(t, "Fig", params()["f_caption"])
+		require.Equal(t, "neogo-blob:0", params()["f_content"])
+		require.Equal(t, []byte("a large figure"), store["0"])
+	})
+
+	t.Run("passes the context of the query to the store", func(t *testing.T) {
+		type key struct{}
+		store := &contextBlobStore{memoryBlobStore: memoryBlobStore{}}
+		m, _ := newParamsMock(func(d *driver) {
+			d.blobStore = store
+			d.blobThreshold = 4
+		})
+		f := figure{Content: []byte("a large figure")}
+		ctx := context.WithValue(ctx, key{}, "query")
+		require.NoError(t, m.Exec().Create(db.Node(db.Qual(&f, "f"))).Run(ctx))
+		require.Equal(t, "query", store.ctx.Value(key{}))
+
+		store.ctx = nil
+		r := &registry{blobStore: store}
+		var read figure
+		require.NoError(t, r.bindValue(ctx, neo4j.Node{
+			Props: map[string]any{"content": "neogo-blob:0"},
+		}, reflect.ValueOf(&read)))
+		require.Equal(t, "query", store.ctx.Value(key{}))
+	})
+
+	t.Run("doesn't store fields of explained queries", func(t *testing.T) {
+		store := memoryBlobStore{}
+		m, _ := newParamsMock(func(d *driver) {
+			d.blobStore = store
+			d.blobThreshold = 4
+		})
+		f := figure{Caption: "Fig", Content: []byte("a large figure")}
+		_, err := m.Exec().Create(db.Node(db.Qual(&f, "f"))).Explain(ctx)
+		require.NoError(t, err)
+		require.Empty(t, store)
+	})
+
+	t.Run("stores small fields as properties", func(t *testing.T) {
+		for name, store := range map[string]BlobStore{"below threshold": memoryBlobStore{}, "without store": nil} {
+			t.Run(name, func(t *testing.T) {
+				m, params := newParamsMock(func(d *driver) {
+					d.blobStore = store
+					d.blobThreshold = 100
+				})
+				f := figure{Caption: "Fig", Content: []byte("a figure")}
+				require.NoError(t, m.Exec().Create(db.Node(db.Qual(&f, "f"))).Run(ctx))
+				require.Equal(t, "Fig", params()["f_caption"])
+				require.Equal(t, []byte("a figure"), params()["f_content"])
+			})
+		}
+	})
+
+	t.Run("offloads fields which could be mistaken for references", func(t *testing.T) {
+		store := memoryBlobStore{}
+		r := &registry{blobStore: store, blobThreshold: 100}
+		params, err := r.convertParams(ctx, map[string]any{
+			"f": figure{Caption: "neogo-blob:x"},
+		}, false)
+		require.NoError(t, err)
+		props := params["f"].(map[string]any)
+		require.Equal(t, "neogo-blob:0", props["caption"])
+		require.Equal(t, []byte("neogo-blob:x"), store["0"])
+	})
+
+	t.Run("resolves references when reading", func(t *testing.T) {
+		store := memoryBlobStore{"0": []byte("a large caption"), "1": []byte("a large figure")}
+		r := &registry{blobStore: store}
+		var f figure
+		err := r.bindValue(ctx, neo4j.Node{
+			Labels: []string{"Figure"},
+			Props: map[string]any{
+				"caption": "neogo-blob:0",
+				"content": "neogo-blob:1",
+			},
+		}, reflect.ValueOf(&f))
+		require.NoError(t, err)
+		require.Equal(t, "a large caption", f.Caption)
+		require.Equal(t, []byte("a large figure"), f.Content)
+
+		err = (&registry{}).bindValue(ctx, neo4j.Node{
+			Props: map[string]any{"caption": "neogo-blob:0"},
+		}, reflect.ValueOf(&f))
+		require.ErrorIs(t, err, ErrNoBlobStore)
+	})
+}
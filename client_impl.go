@@ -9,6 +9,7 @@ import (
 
 	"github.com/goccy/go-json"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/spf13/cast"
 
 	"github.com/rlch/neogo/internal"
 	"github.com/rlch/neogo/query"
@@ -50,6 +51,9 @@ type (
 		*session
 		neo4j.ResultWithContext
 		compiled *internal.CompiledCypher
+		rows     int
+		// ctx is the context of the query, passed to processors.
+		ctx context.Context
 	}
 
 	baseRunner interface {
@@ -58,6 +62,33 @@ type (
 )
 
 func (s *session) newClient(cy *internal.CypherClient) *clientImpl {
+	if s.paramNamer != nil {
+		cy.SetParamNamer(s.paramNamer)
+	}
+	if s.clock != nil {
+		cy.SetClock(s.clock)
+	}
+	if s.features != nil {
+		cy.SetFeatureFlags(s.features)
+	}
+	if s.actor != nil {
+		cy.SetActorFields(true)
+	}
+	if s.defaultFuncs != nil {
+		cy.SetDefaultFuncs(s.defaultFuncs)
+	}
+	if s.includeZero {
+		cy.SetIncludeZero(true)
+	}
+	if s.tagChecker != nil || s.registeredOnly {
+		cy.SetTypeCheck(s.checkType)
+	}
+	if s.namespace != "" {
+		cy.SetNamespace(s.namespace)
+	}
+	if len(s.propertyAliases) > 0 {
+		cy.SetPropertyAliases(s.propertyAliases)
+	}
 	return &clientImpl{
 		session: s,
 		cy:      cy,
@@ -173,6 +204,10 @@ func (c *readerImpl) Call(procedure string) query.Yielder {
 	return c.newYielder(c.cy.Call(procedure))
 }
 
+func (c *readerImpl) CallProcedure(procedure *internal.Procedure) query.Querier {
+	return c.newQuerier(c.cy.CallProcedure(procedure))
+}
+
 func (c *readerImpl) Show(command string) query.Yielder {
 	return c.newYielder(c.cy.Show(command))
 }
@@ -193,10 +228,18 @@ func (c *readerImpl) Eval(expression query.Expression) query.Querier {
 	return c.newQuerier(q)
 }
 
+func (c *readerImpl) Apply(fragment query.Fragment) query.Querier {
+	return fragment(c)
+}
+
 func (c *querierImpl) Where(opts ...internal.WhereOption) query.Querier {
 	return c.newQuerier(c.cy.Where(opts...))
 }
 
+func (c *querierImpl) Using(hints ...internal.Hint) query.Querier {
+	return c.newQuerier(c.cy.Using(hints...))
+}
+
 func (c *updaterImpl[To, ToCypher]) Create(pattern internal.Patterns) To {
 	return c.to(c.cy.Create(pattern))
 }
@@ -240,6 +283,13 @@ func (c *yielderImpl) GetRunner() *internal.CypherRunner {
 	return c.cy.CypherRunner
 }
 
+func (c *querierImpl) When(cond bool, then func(c query.Querier) query.Querier) query.Querier {
+	if !cond {
+		return c
+	}
+	return then(c)
+}
+
 func (c *querierImpl) GetRunner() *internal.CypherRunner {
 	return c.cy.CypherRunner
 }
@@ -253,31 +303,82 @@ func (c *runnerImpl) Print() query.Runner {
 	return c
 }
 
+func (c *runnerImpl) String() string {
+	return strings.TrimRight(c.cy.String(), "\n")
+}
+
+func (c *runnerImpl) DebugString(redact ...string) string {
+	cy, err := c.cy.Compile()
+	if err != nil {
+		return fmt.Sprintf("cannot compile cypher: %v", err)
+	}
+	return cy.DebugString(redact...)
+}
+
 func (c *runnerImpl) run(
 	ctx context.Context,
 	params map[string]any,
 	mapResult func(r neo4j.ResultWithContext) (any, error),
 ) (out any, err error) {
+	return c.runPrefixed(ctx, "", params, mapResult)
+}
+
+// runPrefixed is the same as run, but prefixes the query with a keyword such
+// as EXPLAIN or PROFILE.
+func (c *runnerImpl) runPrefixed(
+	ctx context.Context,
+	prefix string,
+	params map[string]any,
+	mapResult func(r neo4j.ResultWithContext) (any, error),
+) (out any, err error) {
+	defer c.cy.CloseChannels()
 	cy, err := c.cy.CompileWithParams(params)
 	if err != nil {
 		return nil, fmt.Errorf("cannot compile cypher: %w", err)
 	}
-	canonicalizedParams, err := canonicalizeParams(cy.Parameters)
-	if err != nil {
-		return nil, fmt.Errorf("cannot serialize parameters: %w", err)
+	ctx, done := c.watch(ctx, cy.Cypher)
+	defer func() { err = done(err) }()
+	if prefix != "" {
+		cy.Cypher = prefix + " " + cy.Cypher
+	}
+	cy.Cypher = c.traceCypher(ctx, cy.Cypher)
+	c.reportDeprecatedWrites(cy.DeprecatedWrites)
+	// EXPLAIN only plans the query, so nothing is written: entities aren't
+	// prepared or stamped with the actor, and encrypted and external values
+	// aren't encrypted or stored.
+	explain := prefix == "EXPLAIN"
+	if prefix == "" {
+		// Guarded queries are explained before their entities are prepared,
+		// so they are left unchanged if the query is not run.
+		if err := c.guardRows(ctx, cy); err != nil {
+			return nil, err
+		}
 	}
-	if canonicalizedParams != nil {
-		canonicalizedParams["__isWrite"] = cy.IsWrite
+	if explain {
+		cy.SetActor("")
+	} else {
+		if err := cy.RunHooks(ctx); err != nil {
+			return nil, err
+		}
+		c.setActor(ctx, cy)
+	}
+	canonicalizedParams, err := c.sendParams(ctx, cy, explain)
+	if err != nil {
+		return nil, err
 	}
 	return c.executeTransaction(
 		ctx, cy,
-		func(tx neo4j.ManagedTransaction) (any, error) {
+		func(ctx context.Context, tx neo4j.ManagedTransaction) (any, error) {
 			var result neo4j.ResultWithContext
 			result, err = tx.Run(ctx, cy.Cypher, canonicalizedParams)
 			if err != nil {
 				return nil, fmt.Errorf("cannot run cypher: %w", err)
 			}
-			err = c.unmarshalResult(ctx, cy, result)
+			if len(cy.Channels) > 0 {
+				err = c.sendResult(ctx, cy, result)
+			} else {
+				err = c.unmarshalResult(ctx, cy, result)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -288,6 +389,30 @@ func (c *runnerImpl) run(
 		})
 }
 
+// sendParams returns the parameters of cy as they are sent: converted,
+// serialized, aliased and transformed. If explain is set, they are only
+// planned with, so encrypted and external values aren't encrypted or stored.
+func (c *runnerImpl) sendParams(ctx context.Context, cy *internal.CompiledCypher, explain bool) (map[string]any, error) {
+	convertedParams, err := c.convertParams(ctx, cy.Parameters, explain)
+	if err != nil {
+		return nil, err
+	}
+	canonicalizedParams, err := canonicalizeParams(convertedParams)
+	if err != nil {
+		return nil, fmt.Errorf("cannot serialize parameters: %w", err)
+	}
+	c.aliasParams(cy.Parameters, canonicalizedParams)
+	c.normalizeTimeParams(canonicalizedParams)
+	canonicalizedParams, err = c.transformParams(ctx, canonicalizedParams)
+	if err != nil {
+		return nil, err
+	}
+	if canonicalizedParams != nil {
+		canonicalizedParams["__isWrite"] = cy.IsWrite
+	}
+	return canonicalizedParams, nil
+}
+
 func (c *runnerImpl) RunWithParams(ctx context.Context, params map[string]any) (err error) {
 	_, err = c.run(ctx, params, nil)
 	return
@@ -312,16 +437,106 @@ func (c *runnerImpl) RunSummaryWithParams(ctx context.Context, params map[string
 	return summary.(neo4j.ResultSummary), nil
 }
 
+func (c *runnerImpl) Explain(ctx context.Context) (*query.Plan, error) {
+	return c.ExplainWithParams(ctx, nil)
+}
+
+func (c *runnerImpl) ExplainWithParams(ctx context.Context, params map[string]any) (*query.Plan, error) {
+	plan, err := c.runPrefixed(ctx, "EXPLAIN", params, func(r neo4j.ResultWithContext) (any, error) {
+		summary, err := r.Consume(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return newPlan(summary.Plan()), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan.(*query.Plan), nil
+}
+
+func (c *runnerImpl) Profile(ctx context.Context) (*query.Plan, error) {
+	return c.ProfileWithParams(ctx, nil)
+}
+
+func (c *runnerImpl) ProfileWithParams(ctx context.Context, params map[string]any) (*query.Plan, error) {
+	plan, err := c.runPrefixed(ctx, "PROFILE", params, func(r neo4j.ResultWithContext) (any, error) {
+		summary, err := r.Consume(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return newProfiledPlan(summary.Profile()), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan.(*query.Plan), nil
+}
+
+func newPlan(p neo4j.Plan) *query.Plan {
+	if p == nil {
+		return nil
+	}
+	plan := &query.Plan{
+		Operator:      p.Operator(),
+		Arguments:     p.Arguments(),
+		Identifiers:   p.Identifiers(),
+		EstimatedRows: estimatedRows(p.Arguments()),
+	}
+	for _, child := range p.Children() {
+		plan.Children = append(plan.Children, newPlan(child))
+	}
+	return plan
+}
+
+func newProfiledPlan(p neo4j.ProfiledPlan) *query.Plan {
+	if p == nil {
+		return nil
+	}
+	plan := &query.Plan{
+		Operator:        p.Operator(),
+		Arguments:       p.Arguments(),
+		Identifiers:     p.Identifiers(),
+		EstimatedRows:   estimatedRows(p.Arguments()),
+		DbHits:          p.DbHits(),
+		Rows:            p.Records(),
+		PageCacheHits:   p.PageCacheHits(),
+		PageCacheMisses: p.PageCacheMisses(),
+		Time:            p.Time(),
+	}
+	for _, child := range p.Children() {
+		plan.Children = append(plan.Children, newProfiledPlan(child))
+	}
+	return plan
+}
+
+func estimatedRows(args map[string]any) float64 {
+	rows, _ := cast.ToFloat64E(args["EstimatedRows"])
+	return rows
+}
+
 func (c *runnerImpl) StreamWithParams(ctx context.Context, params map[string]any, sink func(r query.Result) error) (err error) {
+	defer c.cy.CloseChannels()
 	cy, err := c.cy.CompileWithParams(params)
 	if err != nil {
 		return fmt.Errorf("cannot compile cypher: %w", err)
 	}
-	canonicalizedParams, err := canonicalizeParams(cy.Parameters)
+	ctx, done := c.watch(ctx, cy.Cypher)
+	defer func() { err = done(err) }()
+	cy.Cypher = c.traceCypher(ctx, cy.Cypher)
+	c.reportDeprecatedWrites(cy.DeprecatedWrites)
+	if err := c.guardRows(ctx, cy); err != nil {
+		return err
+	}
+	if err := cy.RunHooks(ctx); err != nil {
+		return err
+	}
+	c.setActor(ctx, cy)
+	canonicalizedParams, err := c.sendParams(ctx, cy, false)
 	if err != nil {
-		return fmt.Errorf("cannot serialize parameters: %w", err)
+		return err
 	}
-	_, err = c.executeTransaction(ctx, cy, func(tx neo4j.ManagedTransaction) (any, error) {
+	_, err = c.executeTransaction(ctx, cy, func(ctx context.Context, tx neo4j.ManagedTransaction) (any, error) {
 		var result neo4j.ResultWithContext
 		result, err = tx.Run(ctx, cy.Cypher, canonicalizedParams)
 		if err != nil {
@@ -331,6 +546,7 @@ func (c *runnerImpl) StreamWithParams(ctx context.Context, params map[string]any
 			session:           c.session,
 			ResultWithContext: result,
 			compiled:          cy,
+			ctx:               ctx,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("cannot sink result: %w", err)
@@ -361,10 +577,16 @@ func (c *resultImpl) Read() error {
 	if record == nil {
 		return nil
 	}
-	if err := c.unmarshalRecord(c.compiled, record); err != nil {
+	if err := c.unmarshalRecord(c.ctx, c.compiled, record); err != nil {
 		return fmt.Errorf("cannot unmarshal record: %w", err)
 	}
-	return nil
+	if err := c.process(c.ctx, c.compiled); err != nil {
+		return err
+	}
+	c.txStats.recordRows(record)
+	index := c.rows
+	c.rows++
+	return c.onRow(index, record)
 }
 
 func (s *session) unmarshalResult(
@@ -376,14 +598,14 @@ func (s *session) unmarshalResult(
 		return nil
 	}
 	first := result.Record()
+	var records []*neo4j.Record
 	if result.Peek(ctx) {
-		var records []*neo4j.Record
 		records, err = result.Collect(ctx)
 		if err != nil {
 			return fmt.Errorf("cannot collect records: %w", err)
 		}
 		records = append([]*neo4j.Record{first}, records...)
-		if err = s.unmarshalRecords(cy, records); err != nil {
+		if err = s.unmarshalRecords(ctx, cy, records); err != nil {
 			return fmt.Errorf("cannot unmarshal records: %w", err)
 		}
 	} else {
@@ -391,14 +613,58 @@ func (s *session) unmarshalResult(
 		if single == nil {
 			return nil
 		}
-		if err = s.unmarshalRecord(cy, single); err != nil {
+		if err = s.unmarshalRecord(ctx, cy, single); err != nil {
 			return fmt.Errorf("cannot unmarshal record: %w", err)
 		}
+		records = []*neo4j.Record{single}
 	}
-	return nil
+	s.txStats.recordRows(records...)
+	if err := s.process(ctx, cy); err != nil {
+		return err
+	}
+	return s.onRow(0, records...)
+}
+
+// sendResult binds each record of result as it is streamed, sending the values
+// bound to cy.Channels to their channels. Sending blocks until the row is
+// received or ctx is done.
+func (s *session) sendResult(
+	ctx context.Context,
+	cy *internal.CompiledCypher,
+	result neo4j.ResultWithContext,
+) error {
+	done := reflect.ValueOf(ctx.Done())
+	for i := 0; result.Next(ctx); i++ {
+		record := result.Record()
+		for name := range cy.Channels {
+			binding := cy.Bindings[name].Elem()
+			binding.Set(reflect.Zero(binding.Type()))
+		}
+		if err := s.unmarshalRecord(ctx, cy, record); err != nil {
+			return fmt.Errorf("cannot unmarshal record: %w", err)
+		}
+		s.txStats.recordRows(record)
+		if err := s.process(ctx, cy); err != nil {
+			return err
+		}
+		if err := s.onRow(i, record); err != nil {
+			return err
+		}
+		for name, ch := range cy.Channels {
+			chosen, _, _ := reflect.Select([]reflect.SelectCase{
+				{Dir: reflect.SelectSend, Chan: ch, Send: cy.Bindings[name].Elem()},
+				{Dir: reflect.SelectRecv, Chan: done},
+			})
+			if chosen == 1 {
+				return ctx.Err()
+			}
+		}
+	}
+	return result.Err()
 }
 
 func (s *session) unmarshalRecords(
+	ctx context.Context,
 	cy *internal.CompiledCypher,
 	records []*neo4j.Record,
 ) error {
@@ -432,7 +698,7 @@ func (s *session) unmarshalRecords(
 			if to.CanAddr() {
 				to = to.Addr()
 			}
-			if err := s.bindValue(value, to); err != nil {
+			if err := s.bindValue(ctx, value, to); err != nil {
 				return fmt.Errorf(
 					"error binding key %s to type %T: %w",
 					key, binding.Interface(), err,
@@ -444,6 +710,7 @@ func (s *session) unmarshalRecords(
 }
 
 func (s *session) unmarshalRecord(
+	ctx context.Context,
 	cy *internal.CompiledCypher,
 	record *neo4j.Record,
 ) error {
@@ -452,7 +719,7 @@ func (s *session) unmarshalRecord(
 		if !ok {
 			return fmt.Errorf("no value associated with key %q", key)
 		}
-		if err := s.bindValue(value, binding); err != nil {
+		if err := s.bindValue(ctx, value, binding); err != nil {
 			return fmt.Errorf(
 				"error binding key %q to type %T: %w",
 				key, binding.Interface(), err,
@@ -462,24 +729,38 @@ func (s *session) unmarshalRecord(
 	return nil
 }
 
+// txWork is the work of a transaction, run with the context bounded by the
+// timeout of the query.
+type txWork func(ctx context.Context, tx neo4j.ManagedTransaction) (any, error)
+
 func (c *runnerImpl) executeTransaction(
 	ctx context.Context,
 	cy *internal.CompiledCypher,
-	exec neo4j.ManagedTransactionWork,
+	work txWork,
 ) (out any, err error) {
 	if c.currentTx == nil {
 		sess := c.Session()
+		write := cy.IsWrite
+		if conf := c.execConfig.SessionConfig; sess == nil && conf != nil && conf.AccessMode == neo4j.AccessModeWrite {
+			write = true
+		}
+		ctx, cancel := c.queryContext(ctx, write)
+		defer cancel()
+		exec := func(tx neo4j.ManagedTransaction) (any, error) {
+			return work(ctx, tx)
+		}
 		sessConfig := neo4j.SessionConfig{
 			// We default to read mode and overwrite if:
 			//  - the user explicitly requested write mode
 			//  - the query is a write query
 			AccessMode: neo4j.AccessModeRead,
 		}
-		c.ensureCausalConsistency(ctx, &sessConfig)
 		if sess == nil {
 			if conf := c.execConfig.SessionConfig; conf != nil {
 				sessConfig = *conf
 			}
+			c.ensureCausalConsistency(ctx, &sessConfig)
+			c.correlateSession(ctx, &sessConfig)
 			if cy.IsWrite || sessConfig.AccessMode == neo4j.AccessModeWrite {
 				sessConfig.AccessMode = neo4j.AccessModeWrite
 			} else {
@@ -494,15 +775,7 @@ func (c *runnerImpl) executeTransaction(
 					bookmarks := sess.LastBookmarks()
 					if bookmarks != nil && c.causalConsistencyKey != nil {
 						key := c.causalConsistencyKey(ctx)
-						if cur, ok := causalConsistencyCache[key]; ok {
-							causalConsistencyCache[key] = neo4j.CombineBookmarks(cur, bookmarks)
-						} else {
-							causalConsistencyCache[key] = bookmarks
-							go func(key string) {
-								<-ctx.Done()
-								causalConsistencyCache[key] = nil
-							}(key)
-						}
+						c.bookmarks.add(ctx, principalOf(&sessConfig), key, bookmarks)
 					}
 				}
 				if closeErr := sess.Close(ctx); closeErr != nil {
@@ -515,17 +788,48 @@ func (c *runnerImpl) executeTransaction(
 			if conf := c.execConfig.TransactionConfig; conf != nil {
 				*tc = *conf
 			}
+			tc.Metadata = c.txMetadata(ctx, tc.Metadata)
 		}
+		accessMode := neo4j.AccessModeRead
 		if cy.IsWrite || sessConfig.AccessMode == neo4j.AccessModeWrite {
+			accessMode = neo4j.AccessModeWrite
+		}
+		exec = c.watchChurn(cy, exec)
+		if stats := c.beginTx(ctx, accessMode); stats != nil {
+			run := exec
+			exec = func(tx neo4j.ManagedTransaction) (any, error) {
+				stats.reset()
+				stats.record(cy)
+				return run(tx)
+			}
+			// Records are read by the session, so it holds the stats of the
+			// transaction while it runs.
+			c.txStats = stats
+			defer func() {
+				c.txStats = nil
+				stats.finish(ctx, err)
+			}()
+		}
+		switch {
+		case len(cy.Channels) > 0:
+			// Rows are sent to channels as they are streamed, so they cannot be
+			// taken back by retrying the transaction.
+			out, err = executeOnce(ctx, sess, exec, config)
+		case accessMode == neo4j.AccessModeWrite:
 			out, err = sess.ExecuteWrite(ctx, exec, config)
-		} else {
+		default:
 			out, err = sess.ExecuteRead(ctx, exec, config)
 		}
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		out, err = exec(c.currentTx)
+		ctx, cancel := withDeadline(ctx, c.txDeadline)
+		defer cancel()
+		if preflight, _ := ctx.Value(preflightKey{}).(bool); !preflight {
+			c.txStats.record(cy)
+		}
+		out, err = work(ctx, c.currentTx)
 		if err != nil {
 			return nil, err
 		}
@@ -533,6 +837,51 @@ func (c *runnerImpl) executeTransaction(
 	return
 }
 
+// executeOnce runs exec in an explicit transaction of sess, which unlike the
+// managed transactions of ExecuteRead and ExecuteWrite is not retried.
+func executeOnce(
+	ctx context.Context,
+	sess neo4j.SessionWithContext,
+	exec neo4j.ManagedTransactionWork,
+	configurers ...func(*neo4j.TransactionConfig),
+) (any, error) {
+	tx, err := sess.BeginTransaction(ctx, configurers...)
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec(tx)
+	if err != nil {
+		return nil, errors.Join(err, tx.Rollback(context.WithoutCancel(ctx)))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// dbTypeSlice returns the elements of v if they are all temporal or spatial
+// values, which would otherwise be mangled by JSON.
+func dbTypeSlice(v reflect.Value) ([]any, bool) {
+	if v.Len() == 0 {
+		return nil, false
+	}
+	out := make([]any, v.Len())
+	for i := range out {
+		e := v.Index(i)
+		for e.Kind() == reflect.Interface || e.Kind() == reflect.Ptr {
+			if e.IsNil() {
+				return nil, false
+			}
+			e = e.Elem()
+		}
+		if !internal.IsDBType(e.Type()) {
+			return nil, false
+		}
+		out[i] = e.Interface()
+	}
+	return out, true
+}
+
 func canonicalizeParams(params map[string]any) (map[string]any, error) {
 	canon := make(map[string]any, len(params))
 	if len(params) == 0 {
@@ -546,8 +895,33 @@ func canonicalizeParams(params map[string]any) (map[string]any, error) {
 		for vv.Kind() == reflect.Ptr {
 			vv = vv.Elem()
 		}
+		if err := internal.ValidateArrayProperties(vv); err != nil {
+			return nil, err
+		}
+		if vv.IsValid() && internal.IsDBType(vv.Type()) {
+			canon[k] = vv.Interface()
+			continue
+		}
 		switch vv.Kind() {
 		case reflect.Slice:
+			if dbValues, ok := dbTypeSlice(vv); ok {
+				canon[k] = dbValues
+				continue
+			}
+			if _, ok := v.(json.Marshaler); !ok && vv.Type().Elem().Kind() == reflect.Uint8 {
+				// Bytes are written as byte arrays, rather than base64.
+				canon[k] = vv.Bytes()
+				continue
+			}
+			if vv.Type().Elem().Kind() == reflect.Float32 {
+				// Embeddings are converted directly, as they are often large.
+				floats := make([]float64, vv.Len())
+				for i := range floats {
+					floats[i] = vv.Index(i).Float()
+				}
+				canon[k] = floats
+				continue
+			}
 			bytes, err := json.Marshal(v)
 			if err != nil {
 				return nil, fmt.Errorf("cannot marshal slice: %w", err)
@@ -556,8 +930,25 @@ func canonicalizeParams(params map[string]any) (map[string]any, error) {
 			if err := json.Unmarshal(bytes, &js); err != nil {
 				return nil, fmt.Errorf("cannot unmarshal slice: %w", err)
 			}
+			for i, e := range js {
+				if m, ok := e.(map[string]any); ok {
+					internal.RestoreDBTypes(vv.Index(i), m)
+					m = internal.EncodeProtobuf(vv.Type(), m)
+					if js[i], err = internal.EncodeNested(vv.Type(), m); err != nil {
+						return nil, err
+					}
+				}
+			}
 			canon[k] = js
 		case reflect.Map, reflect.Struct:
+			if vv.Kind() == reflect.Map && vv.Type().Key().Kind() == reflect.String {
+				m, err := canonicalizeMap(vv)
+				if err != nil {
+					return nil, err
+				}
+				canon[k] = m
+				continue
+			}
 			bytes, err := json.Marshal(v)
 			if err != nil {
 				return nil, fmt.Errorf("cannot marshal map: %w", err)
@@ -566,6 +957,13 @@ func canonicalizeParams(params map[string]any) (map[string]any, error) {
 			if err := json.Unmarshal(bytes, &js); err != nil {
 				return nil, fmt.Errorf("cannot unmarshal map: %w", err)
 			}
+			if m, ok := js.(map[string]any); ok && vv.Kind() == reflect.Struct {
+				internal.RestoreDBTypes(vv, m)
+				m = internal.EncodeProtobuf(vv.Type(), m)
+				if js, err = internal.EncodeNested(vv.Type(), m); err != nil {
+					return nil, err
+				}
+			}
 			canon[k] = js
 		default:
 			canon[k] = v
@@ -573,3 +971,37 @@ func canonicalizeParams(params map[string]any) (map[string]any, error) {
 	}
 	return canon, nil
 }
+
+func isDBValue(v any) bool {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return internal.IsDBType(t)
+}
+
+// canonicalizeMap canonicalizes the entries of a map keyed by strings as
+// parameters of their own, so temporal values, embeddings and structs within
+// it are encoded as they would be at the top level. Other values marshalled
+// to JSON are replaced with their JSON representation.
+func canonicalizeMap(m reflect.Value) (map[string]any, error) {
+	if m.IsNil() {
+		return nil, nil
+	}
+	entries := make(map[string]any, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		v := iter.Value().Interface()
+		if _, ok := v.(json.Marshaler); ok && !isDBValue(v) {
+			bytes, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("cannot marshal map entry %q: %w", iter.Key().String(), err)
+			}
+			if err := json.Unmarshal(bytes, &v); err != nil {
+				return nil, fmt.Errorf("cannot unmarshal map entry %q: %w", iter.Key().String(), err)
+			}
+		}
+		entries[iter.Key().String()] = v
+	}
+	return canonicalizeParams(entries)
+}
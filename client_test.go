@@ -2,7 +2,9 @@ package neogo
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
@@ -36,7 +38,7 @@ func TestUnmarshalRecord(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecord(cy, record)
+		err := s.unmarshalRecord(context.Background(), cy, record)
 		assert.Error(t, err)
 	})
 
@@ -58,7 +60,7 @@ func TestUnmarshalRecord(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecord(cy, record)
+		err := s.unmarshalRecord(context.Background(), cy, record)
 		assert.NoError(t, err)
 		assert.Equal(t, tests.Person{
 			Name: "Jessie", Surname: "Pinkman",
@@ -76,7 +78,7 @@ func TestUnmarshalRecord(t *testing.T) {
 			Keys:   []string{"n"},
 			Values: []any{nil},
 		}
-		err := s.unmarshalRecord(cy, record)
+		err := s.unmarshalRecord(context.Background(), cy, record)
 		assert.NoError(t, err)
 		assert.Equal(t, (*tests.Person)(nil), n)
 	})
@@ -103,7 +105,7 @@ func TestUnmarshalRecord(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecord(cy, record)
+		err := s.unmarshalRecord(context.Background(), cy, record)
 		assert.NoError(t, err)
 		assert.Equal(t, &tests.Human{
 			BaseOrganism: tests.BaseOrganism{
@@ -140,7 +142,7 @@ func TestUnmarshalRecord(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecord(cy, record)
+		err := s.unmarshalRecord(context.Background(), cy, record)
 		assert.NoError(t, err)
 		assert.Equal(t, &tests.Dog{
 			BasePet: tests.BasePet{
@@ -162,7 +164,7 @@ func TestUnmarshalRecord(t *testing.T) {
 				"n": reflect.ValueOf(&n),
 			},
 		}
-		err := s.unmarshalRecord(cy,
+		err := s.unmarshalRecord(context.Background(), cy,
 			&neo4j.Record{
 				Keys: []string{"n"},
 				Values: []any{
@@ -199,7 +201,7 @@ func TestUnmarshalRecord(t *testing.T) {
 				"n": reflect.ValueOf(&n),
 			},
 		}
-		err := s.unmarshalRecord(cy,
+		err := s.unmarshalRecord(context.Background(), cy,
 			&neo4j.Record{
 				Keys: []string{"n"},
 				Values: []any{
@@ -225,7 +227,7 @@ func TestUnmarshalRecord(t *testing.T) {
 				"n": reflect.ValueOf(&n),
 			},
 		}
-		err := s.unmarshalRecord(cy,
+		err := s.unmarshalRecord(context.Background(), cy,
 			&neo4j.Record{
 				Keys: []string{"n"},
 				Values: []any{
@@ -285,7 +287,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				Values: []any{"some_value"},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.Error(t, err)
 	})
 
@@ -320,7 +322,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.NoError(t, err)
 		assert.Equal(t, tests.Person{
 			Name: "Jessie", Surname: "Pinkman",
@@ -347,7 +349,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				Values: []any{nil},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.NoError(t, err)
 		assert.Equal(t, (*tests.Person)(nil), n[0])
 		assert.Equal(t, (*tests.Person)(nil), n[1])
@@ -379,7 +381,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.NoError(t, err)
 		assert.Len(t, n, 2)
 		assert.Equal(t, tests.Person{
@@ -405,7 +407,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				Values: []any{2},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, n[0])
 		assert.Equal(t, 2, n[1])
@@ -428,7 +430,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				Values: []any{[]any{"c", "d"}},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.NoError(t, err)
 		assert.Equal(t, []any{"a", "b"}, n[0])
 		assert.Equal(t, []any{"c", "d"}, n[1])
@@ -478,7 +480,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.NoError(t, err)
 		assert.Equal(t, &tests.Dog{
 			BasePet: tests.BasePet{
@@ -547,7 +549,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.NoError(t, err)
 		assert.Equal(t, &tests.BasePet{
 			BaseOrganism: tests.BaseOrganism{
@@ -595,7 +597,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecords(cy, records)
+		err := s.unmarshalRecords(context.Background(), cy, records)
 		assert.NoError(t, err)
 		assert.Equal(t, tests.BasePet{
 			BaseOrganism: tests.BaseOrganism{
@@ -629,7 +631,7 @@ func TestUnmarshalRecords(t *testing.T) {
 				},
 			},
 		}
-		err := s.unmarshalRecord(&internal.CompiledCypher{
+		err := s.unmarshalRecord(context.Background(), &internal.CompiledCypher{
 			Bindings: map[string]reflect.Value{
 				"persons": reflect.ValueOf(&persons),
 			},
@@ -651,7 +653,7 @@ func TestUnmarshalRecords(t *testing.T) {
 			Keys:   []string{"persons"},
 			Values: []any{nil},
 		}
-		err := s.unmarshalRecord(&internal.CompiledCypher{
+		err := s.unmarshalRecord(context.Background(), &internal.CompiledCypher{
 			Bindings: map[string]reflect.Value{
 				"persons": reflect.ValueOf(&persons),
 			},
@@ -808,6 +810,98 @@ func TestRunSummary(t *testing.T) {
 	})
 }
 
+type fakeProfiledPlan struct {
+	operator string
+	args     map[string]any
+	dbHits   int64
+	records  int64
+	children []neo4j.ProfiledPlan
+}
+
+func (p fakeProfiledPlan) Operator() string               { return p.operator }
+func (p fakeProfiledPlan) Arguments() map[string]any      { return p.args }
+func (p fakeProfiledPlan) Identifiers() []string          { return []string{"n"} }
+func (p fakeProfiledPlan) DbHits() int64                  { return p.dbHits }
+func (p fakeProfiledPlan) Records() int64                 { return p.records }
+func (p fakeProfiledPlan) Children() []neo4j.ProfiledPlan { return p.children }
+func (p fakeProfiledPlan) PageCacheMisses() int64         { return 0 }
+func (p fakeProfiledPlan) PageCacheHits() int64           { return 0 }
+func (p fakeProfiledPlan) PageCacheHitRatio() float64     { return 0 }
+func (p fakeProfiledPlan) Time() int64                    { return 0 }
+
+func TestProfiledPlan(t *testing.T) {
+	plan := newProfiledPlan(fakeProfiledPlan{
+		operator: "ProduceResults@neo4j",
+		args:     map[string]any{"EstimatedRows": 10.0},
+		dbHits:   0,
+		records:  3,
+		children: []neo4j.ProfiledPlan{fakeProfiledPlan{
+			operator: "NodeByLabelScan@neo4j",
+			args:     map[string]any{"EstimatedRows": int64(10)},
+			dbHits:   4,
+			records:  3,
+		}},
+	})
+	require.Equal(t, &query.Plan{
+		Operator:      "ProduceResults@neo4j",
+		Arguments:     map[string]any{"EstimatedRows": 10.0},
+		Identifiers:   []string{"n"},
+		EstimatedRows: 10,
+		Rows:          3,
+		Children: []*query.Plan{{
+			Operator:      "NodeByLabelScan@neo4j",
+			Arguments:     map[string]any{"EstimatedRows": int64(10)},
+			Identifiers:   []string{"n"},
+			EstimatedRows: 10,
+			DbHits:        4,
+			Rows:          3,
+		}},
+	}, plan)
+	require.Nil(t, newPlan(nil))
+}
+
+func TestExplainProfile(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+	ctx := context.Background()
+	uri, cancel := startNeo4J(ctx)
+	d, err := New(uri, neo4j.BasicAuth("neo4j", "password", ""))
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := cancel(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("explain returns a plan without db hits", func(t *testing.T) {
+		var p Person
+		plan, err := d.Exec().
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Explain(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, plan)
+		assert.Contains(t, plan.Operator, "ProduceResults")
+		assert.NotEmpty(t, plan.Children)
+		assert.Zero(t, plan.DbHits)
+	})
+
+	t.Run("profile returns a plan with statistics", func(t *testing.T) {
+		var p Person
+		plan, err := d.Exec().
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Profile(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, plan)
+		assert.Contains(t, plan.Operator, "ProduceResults")
+		assert.NotEmpty(t, plan.Children)
+	})
+}
+
 func TestResultImpl(t *testing.T) {
 	// TODO: Setup mocks
 	if testing.Short() {
@@ -884,7 +978,7 @@ func TestResultImpl(t *testing.T) {
 			assert.NoError(t, err)
 
 			r := runnerImpl{session: session}
-			_, err = r.executeTransaction(ctx, cy, func(tx neo4j.ManagedTransaction) (any, error) {
+			_, err = r.executeTransaction(ctx, cy, func(ctx context.Context, tx neo4j.ManagedTransaction) (any, error) {
 				var result neo4j.ResultWithContext
 				result, err = tx.Run(ctx, cy.Cypher, params)
 				assert.NoError(t, err)
@@ -991,3 +1085,323 @@ func TestClient(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestRowMetadata(t *testing.T) {
+	var rows []Row
+	s := &session{}
+	WithRowMetadata(func(r Row) error {
+		rows = append(rows, r)
+		return nil
+	})(&s.execConfig)
+
+	var n []tests.Person
+	var r []tests.ActedIn
+	cy := &internal.CompiledCypher{
+		Bindings: map[string]reflect.Value{
+			"n": reflect.ValueOf(&n),
+			"r": reflect.ValueOf(&r),
+		},
+	}
+	records := []*neo4j.Record{
+		{
+			Keys: []string{"n", "r"},
+			Values: []any{
+				neo4j.Node{ElementId: "4:abc:0", Props: map[string]any{"name": "Jessie"}},
+				neo4j.Relationship{ElementId: "5:abc:0", Props: map[string]any{"role": "Student"}},
+			},
+		},
+		{
+			Keys: []string{"n", "r"},
+			Values: []any{
+				neo4j.Node{ElementId: "4:abc:1", Props: map[string]any{"name": "Walter"}},
+				neo4j.Relationship{ElementId: "5:abc:1", Props: map[string]any{"role": "Teacher"}},
+			},
+		},
+	}
+	require.NoError(t, s.unmarshalRecords(context.Background(), cy, records))
+	require.NoError(t, s.onRow(0, records...))
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Walter", n[1].Name)
+	assert.Equal(t, 1, rows[1].Index)
+	assert.Same(t, records[1], rows[1].Record)
+	assert.Equal(t, []string{"n", "r"}, rows[1].Keys)
+	assert.Equal(t, map[string]string{"n": "4:abc:1", "r": "5:abc:1"}, rows[1].ElementIDs)
+
+	t.Run("aborts on error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		WithRowMetadata(func(Row) error { return errStop })(&s.execConfig)
+		assert.ErrorIs(t, s.onRow(0, records...), errStop)
+	})
+}
+
+func TestChan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends each row and closes", func(t *testing.T) {
+		d := NewMock()
+		d.BindRecords([]map[string]any{
+			{"p": tests.Person{Name: "Jessie"}},
+			{"p": tests.Person{Name: "Walter"}},
+		})
+		people := make(chan tests.Person)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- d.Exec().
+				Match(db.Node(db.Qual(db.Chan(people), "p"))).
+				Return(db.Chan(people)).
+				Run(ctx)
+		}()
+		var names []string
+		for p := range people {
+			names = append(names, p.Name)
+		}
+		require.NoError(t, <-errs)
+		assert.Equal(t, []string{"Jessie", "Walter"}, names)
+		assert.Equal(t, 1, d.(*mockDriverImpl).Commits, "rows are sent in a transaction which is not retried")
+	})
+
+	t.Run("sends pointers", func(t *testing.T) {
+		d := NewMock()
+		d.BindRecords([]map[string]any{
+			{"p": tests.Person{Name: "Jessie"}},
+			{"p": tests.Person{Name: "Walter"}},
+		})
+		people := make(chan *tests.Person, 2)
+		err := d.Exec().
+			Match(db.Node(db.Qual(db.Chan(people), "p"))).
+			Return(db.Chan(people)).
+			Run(ctx)
+		require.NoError(t, err)
+		var got []*tests.Person
+		for p := range people {
+			got = append(got, p)
+		}
+		require.Len(t, got, 2)
+		assert.Equal(t, "Jessie", got[0].Name)
+		assert.Equal(t, "Walter", got[1].Name)
+	})
+
+	t.Run("stops when ctx is done", func(t *testing.T) {
+		d := NewMock()
+		d.BindRecords([]map[string]any{
+			{"n": 1},
+			{"n": 2},
+		})
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		nums := make(chan int)
+		err := d.Exec().
+			Return(db.Qual(db.Chan(nums), "n")).
+			Run(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		_, ok := <-nums
+		assert.False(t, ok)
+		assert.Equal(t, 1, d.(*mockDriverImpl).Rollbacks)
+	})
+
+	t.Run("errors on non-channel", func(t *testing.T) {
+		var n int
+		_, err := internal.NewCypherClient().
+			Return(db.Chan(&n)).
+			Compile()
+		assert.Error(t, err)
+	})
+}
+
+func TestParamNaming(t *testing.T) {
+	p := tests.Person{Name: "Jessie"}
+	compile := func(namer ParamNamer) string {
+		s := &session{registry: registry{paramNamer: namer}}
+		runner := s.newClient(internal.NewCypherClient()).
+			Create(db.Node(db.Qual(&p, "p"))).
+			Return(db.Qual(db.Param(1), "one"))
+		cy, err := runner.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		return cy.Cypher
+	}
+
+	assert.Equal(t, "CREATE (p:Person {name: $p_name})\nRETURN $v1 AS one", compile(nil))
+	assert.Equal(t, "CREATE (p:Person {name: $q1})\nRETURN $q2 AS one", compile(CounterParamNames("q")))
+	assert.Equal(t, "CREATE (p:Person {name: $app_p_name})\nRETURN $app_v1 AS one", compile(PrefixedParamNames("app_")))
+	hashed := compile(HashedParamNames())
+	assert.Regexp(t, `^CREATE \(p:Person \{name: \$p_[0-9a-f]+\}\)\nRETURN \$p_[0-9a-f]+ AS one$`, hashed)
+	assert.Equal(t, hashed, compile(HashedParamNames()))
+}
+
+func TestTraceContext(t *testing.T) {
+	type traceKey struct{}
+	d := &driver{traceContext: func(ctx context.Context) (string, string) {
+		ids, _ := ctx.Value(traceKey{}).([2]string)
+		return ids[0], ids[1]
+	}}
+	ctx := context.WithValue(context.Background(), traceKey{}, [2]string{"4bf92f35", "00f067aa"})
+
+	t.Run("prefixes cypher with a comment", func(t *testing.T) {
+		assert.Equal(t, "/* traceId=4bf92f35 spanId=00f067aa */ RETURN 1", d.traceCypher(ctx, "RETURN 1"))
+		assert.Equal(t, "RETURN 1", d.traceCypher(context.Background(), "RETURN 1"))
+		assert.Equal(t, "RETURN 1", (*driver)(nil).traceCypher(ctx, "RETURN 1"))
+	})
+
+	t.Run("merges ids into transaction metadata", func(t *testing.T) {
+		metadata := map[string]any{"app": "neogo"}
+		assert.Equal(t, map[string]any{
+			"app":     "neogo",
+			"traceId": "4bf92f35",
+			"spanId":  "00f067aa",
+		}, d.txMetadata(ctx, metadata))
+		assert.Equal(t, map[string]any{"app": "neogo"}, metadata)
+		assert.Nil(t, d.txMetadata(context.Background(), nil))
+	})
+}
+
+func TestParamTransformers(t *testing.T) {
+	upper := ParamTransformerFunc(func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		for k, v := range params {
+			if s, ok := v.(string); ok {
+				params[k] = strings.ToUpper(s)
+			}
+		}
+		return params, nil
+	})
+	suffix := ParamTransformerFunc(func(ctx context.Context, params map[string]any) (map[string]any, error) {
+		params["name"] = params["name"].(string) + "!"
+		return params, nil
+	})
+
+	t.Run("applies transformers in order", func(t *testing.T) {
+		d := &driver{paramTransformers: []ParamTransformer{upper, suffix}}
+		params, err := d.transformParams(context.Background(), map[string]any{"name": "ada", "age": 36})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "ADA!", "age": 36}, params)
+	})
+
+	t.Run("fails the query when a transformer fails", func(t *testing.T) {
+		m := NewMock()
+		errTransform := errors.New("unsupported value")
+		m.(*mockDriverImpl).paramTransformers = []ParamTransformer{
+			ParamTransformerFunc(func(context.Context, map[string]any) (map[string]any, error) {
+				return nil, errTransform
+			}),
+		}
+		var n tests.Person
+		err := m.Exec().
+			Match(db.Node(db.Qual(&n, "n"))).
+			Where(db.Cond(&n.Name, "=", db.Param("ada"))).
+			Return(&n).
+			Run(context.Background())
+		require.ErrorIs(t, err, errTransform)
+	})
+}
+
+func TestRunnerDebugString(t *testing.T) {
+	var p tests.Person
+	runner := (&session{}).newClient(internal.NewCypherClient()).
+		Match(db.Node(db.Qual(&p, "p"))).
+		Where(db.Cond(&p.Name, "=", db.Param("Ada"))).
+		Return(&p)
+	assert.Equal(t, "MATCH (p:Person)\nWHERE p.name = $v1\nRETURN p", runner.String())
+	assert.Equal(t, "MATCH (p:Person)\nWHERE p.name = 'Ada'\nRETURN p", runner.DebugString())
+}
+
+func TestApply(t *testing.T) {
+	knows := func(from, to *tests.Person) query.Fragment {
+		return func(c query.Reader) query.Querier {
+			return c.
+				Match(db.Node(from).To(db.Var(nil, db.Label("KNOWS")), to)).
+				Where(db.Cond(&to.Age, ">", db.Param(18)))
+		}
+	}
+	s := &session{}
+
+	var a, b tests.Person
+	cy, err := s.newClient(internal.NewCypherClient()).
+		Match(db.Node(db.Qual(&a, "a"))).
+		Apply(knows(&a, &b)).
+		Return(&b).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (a:Person)\nMATCH (a)-[:KNOWS]->(person:Person)\nWHERE person.age > $v1\nRETURN person", cy.Cypher)
+
+	var me, friend tests.Person
+	cy, err = s.newClient(internal.NewCypherClient()).
+		Apply(knows(&me, &friend)).
+		Return(&friend.Name).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (person:Person)-[:KNOWS]->(person1:Person)\nWHERE person1.age > $v1\nRETURN person1.name", cy.Cypher)
+}
+
+func TestRawClause(t *testing.T) {
+	var p tests.Person
+	at := "2024-01-02"
+	cy, err := (&session{}).newClient(internal.NewCypherClient()).
+		Match(db.Node(db.Qual(&p, "p"))).
+		Eval(db.Raw(func(s query.Scope) string {
+			return "CALL acme.touch(" + s.Name(&p) + ", " + s.Param(at) + ", " + s.Param(db.NamedParam(1, "n")) + ")"
+		})).
+		Return(&p).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, "MATCH (p:Person)\nCALL acme.touch(p, $v1, $n)\nRETURN p", cy.Cypher)
+	require.Equal(t, map[string]any{"v1": at, "n": 1}, cy.Parameters)
+}
+
+func TestZeroValues(t *testing.T) {
+	type flag struct {
+		Node    `neo4j:"Flag"`
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+		Rollout int    `json:"rollout"`
+		Owner   string `json:"owner" neogo:"omitzero"`
+	}
+	s := &session{registry: registry{includeZero: true}}
+	f := flag{Name: "beta"}
+	cy, err := s.newClient(internal.NewCypherClient()).
+		Create(db.Node(db.Qual(&f, "f"))).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, "CREATE (f:Flag {enabled: $f_enabled, name: $f_name, rollout: $f_rollout})", cy.Cypher)
+	require.Equal(t, map[string]any{"f_enabled": false, "f_name": "beta", "f_rollout": 0}, cy.Parameters)
+
+	cy, err = s.newClient(internal.NewCypherClient()).
+		Merge(db.Node(db.Qual(&f, "f"))).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, "MERGE (f:Flag {enabled: $f_enabled, name: $f_name, rollout: $f_rollout})", cy.Cypher)
+
+	// Zero fields aren't matched against.
+	cy, err = s.newClient(internal.NewCypherClient()).
+		Match(db.Node(db.Qual(&f, "f"))).Return(&f).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, "MATCH (f:Flag {name: $f_name})\nRETURN f", cy.Cypher)
+	require.Equal(t, map[string]any{"f_name": "beta"}, cy.Parameters)
+
+	// Zero structs still declare variables without properties.
+	var g flag
+	cy, err = s.newClient(internal.NewCypherClient()).
+		Match(db.Node(db.Qual(&g, "g"))).Return(&g).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, "MATCH (g:Flag)\nRETURN g", cy.Cypher)
+}
+
+func TestNamespace(t *testing.T) {
+	s := &session{registry: registry{namespace: "Billing"}}
+	var (
+		p tests.Person
+		m tests.Movie
+	)
+	cy, err := s.newClient(internal.NewCypherClient()).
+		Match(db.Node(db.Qual(&p, "p")).To(db.Qual(&tests.ActedIn{}, "r"), db.Qual(&m, "m"))).
+		Return(&p).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, "MATCH (p:Person:Billing)-[r:ACTED_IN]->(m:Movie:Billing)\nRETURN p", cy.Cypher)
+	require.Equal(t, []string{"Movie", "Person"}, cy.Labels)
+
+	t.Run("ignores the label when binding nodes", func(t *testing.T) {
+		r := registry{namespace: "Billing"}
+		r.registerTypes(&tests.BaseOrganism{})
+		var to tests.Organism
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Human", "Organism", "Billing"},
+			Props:  map[string]any{"name": "Raqeeb"},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, &tests.Human{Name: "Raqeeb"}, to)
+	})
+}
@@ -0,0 +1,62 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/query"
+)
+
+var (
+	// ErrNotFound is returned by [Single] when its query returns no rows.
+	ErrNotFound = errors.New("no rows returned")
+	// ErrMultipleRows is returned by [Single] when its query returns more
+	// than one row.
+	ErrMultipleRows = errors.New("more than one row returned")
+)
+
+// Collect runs the query built by match with d, returning the value bound to
+// item by each row, so results needn't be declared before the query:
+//
+//	people, err := neogo.Collect(ctx, d, func(c neogo.Query, p *Person) query.Querier {
+//		return c.Match(db.Node(db.Qual(p, "p"))).Where(db.Cond(&p.Age, ">", 18))
+//	})
+//
+//	MATCH (p:Person)
+//	WHERE p.age > 18
+//	RETURN p
+func Collect[T any](ctx context.Context, d Driver, match func(c Query, item *T) query.Querier) ([]T, error) {
+	var (
+		item  T
+		items []T
+	)
+	err := match(d.Exec(), &item).
+		Return(db.Bind(&item, &items)).
+		Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Single runs the query built by match with d, returning the value bound to
+// item by its only row. It returns [ErrNotFound] if the query returns no rows,
+// and [ErrMultipleRows] if it returns more than one.
+//
+//	person, err := neogo.Single(ctx, d, func(c neogo.Query, p *Person) query.Querier {
+//		return c.Match(db.Node(db.Qual(p, "p", db.Props{"id": db.NamedParam(id, "id")})))
+//	})
+func Single[T any](ctx context.Context, d Driver, match func(c Query, item *T) query.Querier) (T, error) {
+	var zero T
+	items, err := Collect(ctx, d, match)
+	switch {
+	case err != nil:
+		return zero, err
+	case len(items) == 0:
+		return zero, ErrNotFound
+	case len(items) > 1:
+		return zero, ErrMultipleRows
+	}
+	return items[0], nil
+}
@@ -0,0 +1,49 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+	"github.com/rlch/neogo/query"
+)
+
+func TestCollect(t *testing.T) {
+	ctx := context.Background()
+	match := func(c Query, p *tests.Person) query.Querier {
+		return c.Match(db.Node(db.Qual(p, "p")))
+	}
+	jesse := map[string]any{"name": "Jesse"}
+	walter := map[string]any{"name": "Walter"}
+
+	t.Run("returns every row", func(t *testing.T) {
+		d := NewMock()
+		d.BindRecords([]map[string]any{{"p": jesse}, {"p": walter}})
+		people, err := Collect(ctx, d, match)
+		require.NoError(t, err)
+		assert.Equal(t, []tests.Person{{Name: "Jesse"}, {Name: "Walter"}}, people)
+	})
+
+	t.Run("returns the only row", func(t *testing.T) {
+		d := NewMock()
+		d.BindRecords([]map[string]any{{"p": jesse}})
+		person, err := Single(ctx, d, match)
+		require.NoError(t, err)
+		assert.Equal(t, tests.Person{Name: "Jesse"}, person)
+	})
+
+	t.Run("fails without exactly one row", func(t *testing.T) {
+		d := NewMock()
+		d.BindRecords([]map[string]any{})
+		_, err := Single(ctx, d, match)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		d.BindRecords([]map[string]any{{"p": jesse}, {"p": walter}})
+		_, err = Single(ctx, d, match)
+		assert.ErrorIs(t, err, ErrMultipleRows)
+	})
+}
@@ -2,10 +2,12 @@ package neogo
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/notifications"
 )
 
@@ -17,6 +19,7 @@ func defaultConfig() *config.Config {
 		MaxTransactionRetryTime:         30 * time.Second,
 		MaxConnectionPoolSize:           100,
 		MaxConnectionLifetime:           1 * time.Hour,
+		ConnectionLivenessCheckTimeout:  5 * time.Minute,
 		ConnectionAcquisitionTimeout:    1 * time.Minute,
 		SocketConnectTimeout:            5 * time.Second,
 		SocketKeepalive:                 true,
@@ -34,6 +37,101 @@ type Config struct {
 
 	CausalConsistencyKey func(context.Context) string
 	Types                []any
+	PropertyAliases      map[string]map[string]string
+	// DeprecatedFieldHandler is called whenever a field tagged with
+	// `neogo:"deprecated"` is read from or written to the database.
+	DeprecatedFieldHandler func(DeprecatedField)
+	// TimeZone is the time zone times are converted to before being written.
+	// Defaults to UTC.
+	TimeZone *time.Location
+	// ZonelessTimeZone is the time zone in which zoneless LocalDateTime values
+	// are interpreted when read into a time.Time. Defaults to UTC.
+	ZonelessTimeZone *time.Location
+	// NativeTimes writes time.Time values as DateTime values rather than
+	// RFC 3339 strings.
+	NativeTimes bool
+	// ChangeTracking enables snapshots of the nodes read from the database,
+	// which [IsDirty] compares against.
+	ChangeTracking bool
+	// ChangeTrackingLimit is the number of nodes snapshotted when
+	// ChangeTracking is enabled. Defaults to 10,000.
+	ChangeTrackingLimit int
+	// Views are the materialized views refreshed through [ViewsOf].
+	Views []View
+	// ParamNamer names the parameters generated when compiling queries. By
+	// default, names are derived from the variables and properties they are
+	// bound to, or numbered $v1, $v2, ...
+	ParamNamer ParamNamer
+	// TraceContext extracts the active trace and span IDs from the context of
+	// each query, which are attached to its transaction metadata and text.
+	TraceContext TraceContext
+	// Projections are the named projections selectable with [WithProjection].
+	Projections []Projection
+	// TxListener receives the begin, commit and rollback events of every
+	// transaction.
+	TxListener TxListener
+	// ParamTransformers transform the parameters of every query just before
+	// they are sent, in order.
+	ParamTransformers []ParamTransformer
+	// Processors are the processors run on the values of each type read by
+	// queries. See [WithTypeProcessors].
+	Processors map[reflect.Type][]Processor
+	// QueryWatchdog is the time after which queries are cancelled. See
+	// [WithQueryWatchdog].
+	QueryWatchdog time.Duration
+	// RunawayQueryHandler is called whenever a query is cancelled by the
+	// watchdog.
+	RunawayQueryHandler func(RunawayQuery)
+	// UnmarshalHooks bind the values read by queries to pointers to their
+	// types. See [WithUnmarshalHooks].
+	UnmarshalHooks []UnmarshalHook
+	// Timestamps enables the timestamps of entities. See [WithTimestamps].
+	Timestamps bool
+	// Clock returns the time entities are stamped with. Defaults to time.Now.
+	Clock func() time.Time
+	// FeatureFlags are the feature flags consulted when compiling queries. See
+	// [WithFeatureFlags].
+	FeatureFlags FeatureFlags
+	// AuditActor returns the ID of the actor running a query. See
+	// [WithAuditActor].
+	AuditActor func(ctx context.Context) string
+	// ConnectionChurnHandler is called whenever a query attempt fails because
+	// its connection was lost. See [WithConnectionChurnHandler].
+	ConnectionChurnHandler func(ConnectionChurn)
+	// DefaultFuncs are the generators called by default tags, by name. See
+	// [WithDefaultFunc].
+	DefaultFuncs map[string]func() any
+	// TypeConverters convert the values of the types they are keyed by to the
+	// values stored, and back. See [WithTypeConverter].
+	TypeConverters map[reflect.Type]TypeConverter
+	// Encrypter encrypts the fields tagged with `neogo:"encrypt"`. See
+	// [WithEncrypter].
+	Encrypter Encrypter
+	// IncludeZeroValues writes the zero fields of the structs bound in
+	// patterns. See [WithZeroValues].
+	IncludeZeroValues bool
+	// DefaultContextTimeouts bound the operations run with a context without
+	// a deadline. See [WithDefaultContextTimeout].
+	DefaultContextTimeouts ContextTimeouts
+	// RegisteredAbstractOnly fails queries binding abstract node types which
+	// aren't registered. See [Register].
+	RegisteredAbstractOnly bool
+	// TagCheck fails New if the tags of the registered types disagree on the
+	// names of their properties. See [WithTagCheck].
+	TagCheck bool
+	// RuntimeTagCheck fails queries binding types whose tags disagree on the
+	// names of their properties.
+	RuntimeTagCheck bool
+	// CorrelationIDKey is the context key of the correlation IDs attached to
+	// queries. See [WithCorrelationID].
+	CorrelationIDKey any
+	// BlobStore stores the values of the fields tagged with
+	// `neogo:"external"` longer than BlobThreshold bytes. See [WithBlobStore].
+	BlobStore     BlobStore
+	BlobThreshold int
+	// Namespace is the label added to the nodes of entities. See
+	// [WithNamespace].
+	Namespace string
 }
 
 // Configurer is a function that configures a neogo Config.
@@ -43,12 +141,25 @@ type Configurer func(*Config)
 type execConfig struct {
 	*neo4j.SessionConfig
 	*neo4j.TransactionConfig
+	onRow      func(Row) error
+	projection string
+	processors []Processor
+	// unmarshalHooks are added to the unmarshal hooks of the driver, unless
+	// withoutHooks is set.
+	unmarshalHooks []UnmarshalHook
+	withoutHooks   bool
+	// maxRows is the number of rows the query may be estimated to return. See
+	// [WithRowGuard].
+	maxRows int
+	// timeout bounds the query, in place of the default timeout. See
+	// [WithTimeout].
+	timeout time.Duration
 }
 
-// causalConsistencyCache stores bookmarks for causal consistency by key.
-var causalConsistencyCache map[string]neo4j.Bookmarks = map[string]neo4j.Bookmarks{}
-
-// WithCausalConsistency configures causal consistency for the driver.
+// WithCausalConsistency configures causal consistency for the driver. The
+// bookmarks of writes are stored by the key returned by when, and by the
+// principal the session runs as, so that the reads of one tenant never wait on
+// the writes of another.
 func WithCausalConsistency(when func(ctx context.Context) string) Configurer {
 	return func(c *Config) {
 		c.CausalConsistencyKey = when
@@ -57,12 +168,355 @@ func WithCausalConsistency(when func(ctx context.Context) string) Configurer {
 
 // WithTypes is an option for [New] that allows you to register instances of
 // [IAbstract], [INode] and [IRelationship] to be used with [neogo].
+// [New] fails if any of types isn't one of them.
 func WithTypes(types ...any) Configurer {
 	return func(c *Config) {
 		c.Types = append(c.Types, types...)
 	}
 }
 
+// Register is an option for [New] that registers the type T, which must be a
+// node, abstract node or relationship, like [WithTypes]. The labels of
+// abstract nodes and their implementers are extracted when the driver is
+// created, which fails if T can't be registered.
+//
+// Unlike [WithTypes], registering types with Register or [RegisterFromPackage]
+// makes every abstract node type bound by a query have to be registered, so
+// queries binding the others fail when they are built, with
+// [ErrUnregisteredType], rather than when their results are read.
+//
+//	neogo.New(uri, auth, neogo.Register[Person](), neogo.Register[Pet]())
+func Register[T any]() Configurer {
+	return RegisterFromPackage(new(T))
+}
+
+// RegisterFromPackage is an option for [New] that registers all the types of
+// a package, which must each be a node, abstract node or relationship. Go
+// can't enumerate the types of a package at run time, so packages export them
+// instead:
+//
+//	// Types are the entities of package models.
+//	var Types = []any{&Person{}, &Pet{}, &Dog{}, &Cat{}, &Owns{}}
+//
+//	neogo.New(uri, auth, neogo.RegisterFromPackage(models.Types...))
+//
+// Abstract node types bound by queries must then be registered; see
+// [Register].
+func RegisterFromPackage(types ...any) Configurer {
+	return func(c *Config) {
+		c.Types = append(c.Types, types...)
+		c.RegisteredAbstractOnly = true
+	}
+}
+
+// WithPropertyAliases is an option for [New] that allows properties to be
+// renamed without migrating existing data. aliases maps a node label (or
+// relationship type) to a map of old property keys to their new keys.
+//
+// When reading, a property stored under either key is bound to the field
+// tagged with the new key, or with the old key if the type has no field
+// tagged with the new key. If both keys are stored, the new key takes
+// precedence. When writing nodes or relationships, as parameters or through
+// the fields bound in patterns, old keys are emitted as the new key.
+//
+//	neogo.WithPropertyAliases(map[string]map[string]string{
+//		"Person": {"surname": "lastName"},
+//	})
+func WithPropertyAliases(aliases map[string]map[string]string) Configurer {
+	return func(c *Config) {
+		if c.PropertyAliases == nil {
+			c.PropertyAliases = map[string]map[string]string{}
+		}
+		for label, keys := range aliases {
+			if c.PropertyAliases[label] == nil {
+				c.PropertyAliases[label] = map[string]string{}
+			}
+			for oldKey, newKey := range keys {
+				c.PropertyAliases[label][oldKey] = newKey
+			}
+		}
+	}
+}
+
+// logName is the name neogo logs its messages with, through the logger set
+// with [WithLogger].
+const logName = "neogo"
+
+// WithLogger is an option for [New] that sets the logger of the driver, which
+// logs the warnings of neogo, i.e. about deprecated fields, along with the
+// messages of the underlying neo4j driver. By default, nothing is logged.
+//
+//	neogo.WithLogger(neo4j.ConsoleLogger(neo4j.WARNING))
+func WithLogger(logger log.Logger) Configurer {
+	return func(c *Config) {
+		c.Log = logger
+	}
+}
+
+// WithDeprecatedFieldHandler is an option for [New] that sets the handler
+// invoked when a field tagged with `neogo:"deprecated"` is read or written,
+// i.e. to log or record a metric. By default, the first read and write of each
+// field are warned about through the logger set with [WithLogger], if any.
+//
+//	type Person struct {
+//		neogo.Node `neo4j:"Person"`
+//
+//		Nickname string `json:"nickname" neogo:"deprecated"`
+//	}
+func WithDeprecatedFieldHandler(handler func(DeprecatedField)) Configurer {
+	return func(c *Config) {
+		c.DeprecatedFieldHandler = handler
+	}
+}
+
+// WithTimeZone is an option for [New] that sets the time zone time.Time
+// parameters and properties are converted to before being written, rather
+// than keeping the zone of each value. By default, times are written in UTC.
+func WithTimeZone(loc *time.Location) Configurer {
+	return func(c *Config) {
+		c.TimeZone = loc
+	}
+}
+
+// WithNativeTimes is an option for [New] that writes time.Time parameters and
+// properties as DateTime values, keeping their nanosecond precision, rather
+// than as RFC 3339 strings. Stored strings are not migrated, and cannot be
+// compared with DateTime values in queries. Fields tagged with a temporal type,
+// i.e. `neogo:"date"`, are always written as that type.
+func WithNativeTimes() Configurer {
+	return func(c *Config) {
+		c.NativeTimes = true
+	}
+}
+
+// WithZonelessTimeZone is an option for [New] that sets the time zone in which
+// zoneless LocalDateTime values are interpreted when read into a time.Time.
+// By default, they are interpreted as UTC.
+func WithZonelessTimeZone(loc *time.Location) Configurer {
+	return func(c *Config) {
+		c.ZonelessTimeZone = loc
+	}
+}
+
+// WithChangeTracking is an option for [New] that snapshots the properties of
+// each node read from the database, keyed by its type, tenant and ID, so that
+// [IsDirty] can report whether it has been changed since. This allows
+// no-op updates to be skipped.
+//
+// Snapshots are shared by all sessions of the driver. At most limit nodes are
+// snapshotted, forgetting those read least recently first, or 10,000 if limit
+// is not positive. Snapshots can be released early with
+// [ReleaseSnapshots].
+func WithChangeTracking(limit int) Configurer {
+	return func(c *Config) {
+		c.ChangeTracking = true
+		c.ChangeTrackingLimit = limit
+	}
+}
+
+// WithViews is an option for [New] that registers materialized views, which
+// can be refreshed through [ViewsOf].
+//
+//	neogo.WithViews(neogo.View{
+//		Name:  "dailySales",
+//		Every: time.Hour,
+//		Query: func(c neogo.Query, _ neogo.ViewChunk) query.Runner {
+//			return c.Cypher(`
+//				MATCH (o:Order)
+//				WITH date(o.createdAt) AS day, sum(o.total) AS total
+//				MERGE (d:DailySales {day: day})
+//				SET d.total = total`)
+//		},
+//	})
+func WithViews(views ...View) Configurer {
+	return func(c *Config) {
+		c.Views = append(c.Views, views...)
+	}
+}
+
+// WithParamNaming is an option for [New] that sets the strategy for naming the
+// parameters generated when compiling queries. Names are deterministic, so the
+// same query always compiles to the same text and can be cached by the server.
+//
+//	neogo.WithParamNaming(neogo.CounterParamNames("p"))
+func WithParamNaming(namer ParamNamer) Configurer {
+	return func(c *Config) {
+		c.ParamNamer = namer
+	}
+}
+
+// WithTimestamps is an option for [New] that stamps entities as they are
+// written. Fields of type time.Time named CreatedAt, or tagged with
+// `neogo:"createdAt"`, are set when the entity is created if they are zero, and
+// fields named UpdatedAt, or tagged with `neogo:"updatedAt"`, whenever it is
+// created or set. Entities are stamped when the query writing them is run,
+// not when it is built. Entities written by a MERGE pattern aren't stamped, as
+// their properties are matched against.
+func WithTimestamps() Configurer {
+	return func(c *Config) {
+		c.Timestamps = true
+	}
+}
+
+// WithClock is an option for [New] that sets the clock entities are stamped
+// with when [WithTimestamps] is enabled, i.e. a fixed time in tests.
+func WithClock(now func() time.Time) Configurer {
+	return func(c *Config) {
+		c.Clock = now
+	}
+}
+
+// WithZeroValues is an option for [New] that sets whether the zero fields of
+// the structs created, merged or upserted are written as properties. By
+// default they are omitted, so false, 0 and "" cannot be written; including
+// them writes every field. The zero fields of the structs bound in MATCH
+// patterns are omitted either way, so they aren't matched against.
+//
+// Either way, zero fields tagged with `neogo:"keepzero"` are written when
+// created, merged or upserted, though never matched against, and zero fields
+// tagged with `neogo:"omitzero"` never are, such as the ID of a [Node]. Structs which are entirely zero, i.e. those declaring a variable to
+// match, have no properties.
+func WithZeroValues(include bool) Configurer {
+	return func(c *Config) {
+		c.IncludeZeroValues = include
+	}
+}
+
+// WithNamespace is an option for [New] that adds the label namespace to every
+// node pattern of an entity, so several applications can share a database
+// without matching each other's nodes:
+//
+//	d, err := neogo.New(uri, auth, neogo.WithNamespace("Billing"))
+//	d.Exec().Match(db.Node(db.Qual(&p, "p"))).Return(&p)
+//	// MATCH (p:Person:Billing)
+//	// RETURN p
+//
+// The label is ignored when binding nodes. Patterns written in Cypher, i.e.
+// with db.Label or db.Raw, and relationships are not namespaced, nor are
+// property keys. Planner hints on nodes name the namespace label, so index
+// hints need an index on it:
+//
+//	d.Exec().Match(db.Node(db.Qual(&p, "p"))).Using(db.UsingIndex(&p.Name))
+//	// MATCH (p:Person:Billing)
+//	// USING INDEX p:Billing(name)
+func WithNamespace(namespace string) Configurer {
+	return func(c *Config) {
+		c.Namespace = namespace
+	}
+}
+
+// WithDefaultFunc is an option for [New] that registers fn as the generator
+// of the fields tagged with `default:"name()"`, i.e. a UUID generator for ID
+// fields. Fields with a default tag are set to their default when the query
+// creating or setting an entity is run and they are zero, so `default:"draft"`
+// sets a string to draft. Pointer fields are left alone, as nil is an explicit
+// null. A ulid generator is built in. Defaults which can't be generated fail
+// the query.
+func WithDefaultFunc(name string, fn func() any) Configurer {
+	return func(c *Config) {
+		if c.DefaultFuncs == nil {
+			c.DefaultFuncs = map[string]func() any{}
+		}
+		c.DefaultFuncs[name] = fn
+	}
+}
+
+// WithAuditActor is an option for [New] that records who wrote entities, as
+// returned by actor from the context of each query, i.e. the ID of the
+// authenticated user. String fields named CreatedBy, or tagged with
+// `neogo:"createdBy"`, are set when the entity is created if they are empty,
+// and fields named UpdatedBy, or tagged with `neogo:"updatedBy"`, whenever it
+// is created or set. Like [WithTimestamps], entities written by a MERGE
+// pattern are left alone. If actor returns "", the fields are left as they
+// are.
+func WithAuditActor(actor func(ctx context.Context) string) Configurer {
+	return func(c *Config) {
+		c.AuditActor = actor
+	}
+}
+
+// WithTraceContext is an option for [New] that attaches the IDs of the trace
+// and span active when a query runs to its transaction metadata, as traceId
+// and spanId, and to its text as a leading comment. Slow queries found in
+// SHOW TRANSACTIONS or the query log can then be traced back to the request
+// that issued them.
+func WithTraceContext(extract TraceContext) Configurer {
+	return func(c *Config) {
+		c.TraceContext = extract
+	}
+}
+
+// WithTxListener is an option for [New] that notifies l when a transaction
+// begins, commits or rolls back, along with its duration, the number of
+// statements it ran and the node labels they referenced.
+//
+//	neogo.WithTxListener(neogo.TxListenerFunc(func(ctx context.Context, e neogo.TxEvent) {
+//		if e.Kind != neogo.TxBegin {
+//			metrics.ObserveTx(e.Kind.String(), e.Duration, e.Statements)
+//		}
+//	}))
+func WithTxListener(l TxListener) Configurer {
+	return func(c *Config) {
+		c.TxListener = l
+	}
+}
+
+// WithParamTransformers is an option for [New] that applies transformers to
+// the parameters of every query, after they have been canonicalized and just
+// before they are sent. Transformers are applied in order, after those of
+// previous options.
+//
+//	neogo.WithParamTransformers(neogo.ParamTransformerFunc(
+//		func(ctx context.Context, params map[string]any) (map[string]any, error) {
+//			for k, v := range params {
+//				if d, ok := v.(decimal.Decimal); ok {
+//					params[k] = d.String()
+//				}
+//			}
+//			return params, nil
+//		},
+//	))
+func WithParamTransformers(transformers ...ParamTransformer) Configurer {
+	return func(c *Config) {
+		c.ParamTransformers = append(c.ParamTransformers, transformers...)
+	}
+}
+
+// WithQueryWatchdog is an option for [New] that cancels queries running for
+// longer than max, protecting the database from runaway queries. The context
+// of the query is cancelled, and its transaction is terminated on the server
+// unless it runs in an explicit transaction. The query returns an error
+// wrapping [ErrRunawayQuery], and is reported to the handler set with
+// [WithRunawayQueryHandler]. By default, they are warned about through the
+// logger set with [WithLogger], if any.
+func WithQueryWatchdog(max time.Duration) Configurer {
+	return func(c *Config) {
+		c.QueryWatchdog = max
+	}
+}
+
+// WithRunawayQueryHandler is an option for [New] that sets the handler invoked
+// when a query is cancelled by the watchdog set with [WithQueryWatchdog], i.e.
+// to log or record a metric.
+func WithRunawayQueryHandler(handler func(RunawayQuery)) Configurer {
+	return func(c *Config) {
+		c.RunawayQueryHandler = handler
+	}
+}
+
+// WithProjections is an option for [New] that registers named projections,
+// which are selected for a query with [WithProjection].
+//
+//	neogo.WithProjections(
+//		neogo.NewProjection[Article]("list", "id", "title"),
+//		neogo.NewProjection[Author]("list", "id", "name"),
+//	)
+func WithProjections(projections ...Projection) Configurer {
+	return func(c *Config) {
+		c.Projections = append(c.Projections, projections...)
+	}
+}
+
 // WithTxConfig configures the transaction used by Exec().
 func WithTxConfig(configurers ...func(*neo4j.TransactionConfig)) func(ec *execConfig) {
 	return func(ec *execConfig) {
@@ -80,3 +534,128 @@ func WithSessionConfig(configurers ...func(*neo4j.SessionConfig)) func(ec *execC
 		}
 	}
 }
+
+// WithRowMetadata configures Exec() to call onRow with the metadata of each
+// record returned by the query, after its values have been bound. Returning
+// an error from onRow aborts reading the result.
+func WithRowMetadata(onRow func(Row) error) func(ec *execConfig) {
+	return func(ec *execConfig) {
+		ec.onRow = onRow
+	}
+}
+
+// WithTypeProcessors is an option for [New] that runs processors, ordered by
+// priority, on every value of type T read by a query.
+//
+//	neogo.WithTypeProcessors[Person](
+//		neogo.TrimStrings(),
+//		neogo.Derive("fullName", func(ctx context.Context, p *Person) error {
+//			p.FullName = p.Name + " " + p.Surname
+//			return nil
+//		}),
+//	)
+func WithTypeProcessors[T any](processors ...Processor) Configurer {
+	return func(c *Config) {
+		if c.Processors == nil {
+			c.Processors = make(map[reflect.Type][]Processor)
+		}
+		t := reflect.TypeOf((*T)(nil)).Elem()
+		c.Processors[t] = append(c.Processors[t], processors...)
+		sortProcessors(c.Processors[t])
+	}
+}
+
+// UnregisterProcessor is an option for [New] that removes the processors named
+// name registered by previous options, i.e. to replace a processor registered
+// by a library layered on neogo. Processors can be removed from a driver in
+// use with [UnregisterHook].
+func UnregisterProcessor(name string) Configurer {
+	return func(c *Config) {
+		for t, processors := range c.Processors {
+			kept := processors[:0:0]
+			for _, p := range processors {
+				if p.Name != name {
+					kept = append(kept, p)
+				}
+			}
+			if len(kept) == 0 {
+				delete(c.Processors, t)
+			} else {
+				c.Processors[t] = kept
+			}
+		}
+	}
+}
+
+// WithUnmarshalHooks is an option for [New] that binds the values read by
+// queries with hooks, ordered by priority, rather than neogo's default
+// unmarshalling. Libraries layered on neogo should name their hooks, so they
+// can be removed with [UnregisterHook].
+//
+//	neogo.WithUnmarshalHooks(
+//		neogo.NewUnmarshalHook("decimal", 0, decodeDecimal),
+//		neogo.NewUnmarshalHook("locale", 10, func(from any, v *Person) error {
+//			v.Name = translate(v.Name)
+//			return nil
+//		}),
+//	)
+func WithUnmarshalHooks(hooks ...UnmarshalHook) Configurer {
+	return func(c *Config) {
+		c.UnmarshalHooks = append(c.UnmarshalHooks, hooks...)
+	}
+}
+
+// WithTypedUnmarshalHook is an option for [New] that binds the values read by
+// queries into values of type T with an unnamed hook of priority 0. See
+// [NewUnmarshalHook].
+//
+//	neogo.WithTypedUnmarshalHook(func(from any, v *decimal.Decimal) error {
+//		s, ok := from.(string)
+//		if !ok {
+//			return fmt.Errorf("cannot unmarshal %T into decimal", from)
+//		}
+//		d, err := decimal.NewFromString(s)
+//		*v = d
+//		return err
+//	})
+func WithTypedUnmarshalHook[T any](hook func(from any, v *T) error) Configurer {
+	return WithUnmarshalHooks(NewUnmarshalHook("", 0, hook))
+}
+
+// WithExtraUnmarshalHook configures Exec() to bind values of type T with hook.
+// The hooks added for T replace those registered for T with
+// [WithUnmarshalHooks], and the hooks registered for other types still apply.
+func WithExtraUnmarshalHook[T any](hook func(from any, v *T) error) func(ec *execConfig) {
+	return func(ec *execConfig) {
+		ec.unmarshalHooks = append(ec.unmarshalHooks, NewUnmarshalHook("", 0, hook))
+	}
+}
+
+// WithoutHooks configures Exec() to bind values without the hooks registered
+// with [WithUnmarshalHooks], i.e. for tooling which must read the raw
+// values stored. Hooks added with [WithExtraUnmarshalHook] still apply.
+func WithoutHooks() func(ec *execConfig) {
+	return func(ec *execConfig) {
+		ec.withoutHooks = true
+	}
+}
+
+// WithProcessors configures Exec() to run processors, ordered by priority, on
+// every value read by the query, after those registered for its type.
+func WithProcessors(processors ...Processor) func(ec *execConfig) {
+	return func(ec *execConfig) {
+		ec.processors = append(ec.processors, processors...)
+		sortProcessors(ec.processors)
+	}
+}
+
+// WithProjection configures Exec() to return the nodes of each type with a
+// projection named name, registered with [WithProjections], as map
+// projections of the properties of the projection.
+//
+//	RETURN a {.id, .title} AS a
+func WithProjection(name string) func(ec *execConfig) {
+	return func(ec *execConfig) {
+		ec.projection = name
+	}
+}
@@ -0,0 +1,56 @@
+package neogo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// ConnectionChurn describes a query attempt failing because its connection
+// was lost, typically because a load balancer dropped it while idle. Managed
+// transactions are retried on a new connection, so churn is only reported.
+type ConnectionChurn struct {
+	// Cypher is the query being run.
+	Cypher string
+	// Err is the connectivity error the attempt failed with.
+	Err error
+}
+
+// WithConnectionLiveness is an option for [New] that sets how long connections
+// may be idle before they are checked before reuse, and how long they are
+// pooled before they are replaced. Load balancers silently drop connections
+// idle for longer than their timeout, so interval should be shorter than it.
+// Defaults to 5 minutes and 1 hour.
+func WithConnectionLiveness(interval, maxAge time.Duration) Configurer {
+	return func(c *Config) {
+		c.ConnectionLivenessCheckTimeout = interval
+		c.MaxConnectionLifetime = maxAge
+	}
+}
+
+// WithConnectionChurnHandler is an option for [New] that calls handler
+// whenever a query attempt fails because its connection was lost.
+func WithConnectionChurnHandler(handler func(ConnectionChurn)) Configurer {
+	return func(c *Config) {
+		c.ConnectionChurnHandler = handler
+	}
+}
+
+// watchChurn wraps exec to report the attempts running cy which fail because
+// their connection was lost.
+func (d *driver) watchChurn(cy *internal.CompiledCypher, exec neo4j.ManagedTransactionWork) neo4j.ManagedTransactionWork {
+	if d == nil || d.onConnectionChurn == nil {
+		return exec
+	}
+	return func(tx neo4j.ManagedTransaction) (any, error) {
+		out, err := exec(tx)
+		var connErr *neo4j.ConnectivityError
+		if errors.As(err, &connErr) {
+			d.onConnectionChurn(ConnectionChurn{Cypher: cy.Cypher, Err: err})
+		}
+		return out, err
+	}
+}
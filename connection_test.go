@@ -0,0 +1,41 @@
+package neogo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/internal"
+)
+
+func TestConnectionLiveness(t *testing.T) {
+	cfg := &Config{Config: *defaultConfig()}
+	assert.Equal(t, 5*time.Minute, cfg.ConnectionLivenessCheckTimeout)
+	WithConnectionLiveness(30*time.Second, 10*time.Minute)(cfg)
+	assert.Equal(t, 30*time.Second, cfg.ConnectionLivenessCheckTimeout)
+	assert.Equal(t, 10*time.Minute, cfg.MaxConnectionLifetime)
+}
+
+func TestConnectionChurn(t *testing.T) {
+	var churn []ConnectionChurn
+	d := &driver{onConnectionChurn: func(c ConnectionChurn) { churn = append(churn, c) }}
+	cy := &internal.CompiledCypher{Cypher: "MATCH (n) RETURN n"}
+	connErr := &neo4j.ConnectivityError{Inner: errors.New("broken pipe")}
+	errs := []error{fmt.Errorf("cannot run cypher: %w", connErr), errors.New("syntax error"), nil}
+	exec := d.watchChurn(cy, func(neo4j.ManagedTransaction) (any, error) {
+		err := errs[0]
+		errs = errs[1:]
+		return nil, err
+	})
+	for range 3 {
+		_, _ = exec(nil)
+	}
+	require.Len(t, churn, 1)
+	assert.Equal(t, "MATCH (n) RETURN n", churn[0].Cypher)
+	assert.ErrorIs(t, churn[0].Err, connErr)
+}
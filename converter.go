@@ -0,0 +1,269 @@
+package neogo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// TypeConverter converts values of a Go type to a representation Neo4J can
+// store, and back. See [WithTypeConverter].
+type TypeConverter struct {
+	// Stored is the type of the values stored.
+	Stored reflect.Type
+	// ToDB converts a value of the Go type to the value stored.
+	ToDB func(v any) (any, error)
+	// FromDB converts a stored value, of type Stored, to a value of the Go type.
+	FromDB func(stored any) (any, error)
+}
+
+// WithTypeConverter is an option for [New] that stores values of type T, i.e.
+// a UUID, decimal or enum, as values of type S. Parameters of type T, and
+// fields of type T in the entities written, are converted with toDB before
+// being sent, and values read into T, including fields of the entities read,
+// are converted with fromDB.
+//
+//	neogo.WithTypeConverter(
+//		func(d decimal.Decimal) (string, error) { return d.String(), nil },
+//		decimal.NewFromString,
+//	)
+func WithTypeConverter[T, S any](toDB func(T) (S, error), fromDB func(S) (T, error)) Configurer {
+	return func(c *Config) {
+		if c.TypeConverters == nil {
+			c.TypeConverters = map[reflect.Type]TypeConverter{}
+		}
+		c.TypeConverters[reflect.TypeOf((*T)(nil)).Elem()] = TypeConverter{
+			Stored: reflect.TypeOf((*S)(nil)).Elem(),
+			ToDB: func(v any) (any, error) {
+				return toDB(v.(T))
+			},
+			FromDB: func(stored any) (any, error) {
+				return fromDB(stored.(S))
+			},
+		}
+	}
+}
+
+// convertParams returns params with the values of types with a converter
+// converted to the values stored. If explain is set, the query is only
+// planned, so encrypted and external values are replaced by empty strings
+// rather than being encrypted or stored.
+func (r *registry) convertParams(ctx context.Context, params map[string]any, explain bool) (map[string]any, error) {
+	out := make(map[string]any, len(params))
+	for k, v := range params {
+		converted, _, err := r.convertValue(ctx, reflect.ValueOf(v), explain)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert parameter %s: %w", k, err)
+		}
+		out[k] = converted
+	}
+	return out, nil
+}
+
+// convertValue converts v, or the values it contains, if they are of a type
+// with a converter, reporting whether any were.
+func (r *registry) convertValue(ctx context.Context, v reflect.Value, explain bool) (any, bool, error) {
+	if !v.IsValid() {
+		return nil, false, nil
+	}
+	if conv, ok := r.converterOf(v.Type()); ok {
+		out, err := conv.ToDB(v.Interface())
+		return out, true, err
+	}
+	if (v.Type() == encryptedType || v.Type() == externalType) && explain {
+		return "", true, nil
+	}
+	if v.Type() == encryptedType {
+		out, err := r.encrypt(v.String())
+		return out, true, err
+	}
+	if v.Type() == externalType {
+		out, err := r.externalize(ctx, v.Interface().(internal.External).Value)
+		return out, true, err
+	}
+	original := func() any {
+		if v.CanInterface() {
+			return v.Interface()
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return original(), false, nil
+		}
+		out, changed, err := r.convertValue(ctx, v.Elem(), explain)
+		if !changed || err != nil {
+			return original(), false, err
+		}
+		return out, true, nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return original(), false, nil
+		}
+		out := make([]any, v.Len())
+		var anyChanged bool
+		for i := range out {
+			elem, changed, err := r.convertValue(ctx, v.Index(i), explain)
+			if err != nil {
+				return nil, false, err
+			}
+			out[i] = elem
+			anyChanged = anyChanged || changed
+		}
+		if !anyChanged {
+			return original(), false, nil
+		}
+		return out, true, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return original(), false, nil
+		}
+		out := make(map[string]any, v.Len())
+		var anyChanged bool
+		for iter := v.MapRange(); iter.Next(); {
+			elem, changed, err := r.convertValue(ctx, iter.Value(), explain)
+			if err != nil {
+				return nil, false, err
+			}
+			out[iter.Key().String()] = elem
+			anyChanged = anyChanged || changed
+		}
+		if !anyChanged {
+			return original(), false, nil
+		}
+		return out, true, nil
+	case reflect.Struct:
+		if internal.IsDBType(v.Type()) {
+			return original(), false, nil
+		}
+		converted := map[string]any{}
+		for _, f := range internal.UpsertFields(v.Type()) {
+			fv, err := v.FieldByIndexErr(f.Index)
+			if err != nil {
+				continue
+			}
+			if internal.IsEncrypted(v.Type().FieldByIndex(f.Index)) {
+				fv = reflect.Indirect(fv)
+				if !fv.IsValid() || fv.String() == "" {
+					continue
+				}
+				fv = reflect.ValueOf(internal.Encrypted(fv.String()))
+			} else if internal.IsExternal(v.Type().FieldByIndex(f.Index)) {
+				fv = reflect.Indirect(fv)
+				if !fv.IsValid() || fv.Len() == 0 {
+					continue
+				}
+				fv = reflect.ValueOf(internal.External{Value: fv.Interface()})
+			}
+			out, changed, err := r.convertValue(ctx, fv, explain)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s.%s: %w", v.Type().Name(), f.Property, err)
+			}
+			if changed {
+				converted[f.Property] = out
+			}
+		}
+		if len(converted) == 0 {
+			return original(), false, nil
+		}
+		canon, err := canonicalizeParams(map[string]any{"v": v.Interface()})
+		if err != nil {
+			return nil, false, err
+		}
+		props, ok := canon["v"].(map[string]any)
+		if !ok {
+			return original(), false, nil
+		}
+		for k, out := range converted {
+			props[k] = out
+		}
+		return props, true, nil
+	}
+	return original(), false, nil
+}
+
+// bindConverted binds from to to with the converter of the type to points to,
+// if any, allocating nil pointers along the way.
+func (r *registry) bindConverted(ctx context.Context, from any, to reflect.Value) (ok bool, err error) {
+	if from == nil {
+		return false, nil
+	}
+	conv, ok := r.converterOf(unwindType(to.Type()))
+	if !ok {
+		return false, nil
+	}
+	for to.Kind() == reflect.Ptr {
+		if to.IsNil() {
+			if !to.CanSet() {
+				return false, nil
+			}
+			to.Set(reflect.New(to.Type().Elem()))
+		}
+		to = to.Elem()
+	}
+	if !to.CanSet() {
+		return false, nil
+	}
+	stored := reflect.New(conv.Stored)
+	if err := r.bindValue(ctx, from, stored); err != nil {
+		return true, err
+	}
+	out, err := conv.FromDB(stored.Elem().Interface())
+	if err != nil {
+		return true, err
+	}
+	to.Set(reflect.ValueOf(out))
+	return true, nil
+}
+
+// popConvertedProps removes the properties of the fields of the struct to
+// points to whose types, or element types, have a converter from props, returning a function
+// binding them once the remaining properties are bound.
+func (r *registry) popConvertedProps(ctx context.Context, props map[string]any, to reflect.Value) (map[string]any, func() error) {
+	bind := func() error { return nil }
+	t := unwindType(to.Type())
+	if t.Kind() != reflect.Struct {
+		return props, bind
+	}
+	var fields []internal.UpsertField
+	for _, f := range internal.UpsertFields(t) {
+		ft := t.FieldByIndex(f.Index).Type
+		for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		if _, ok := r.converterOf(ft); ok {
+			if _, ok := props[f.Property]; ok {
+				fields = append(fields, f)
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return props, bind
+	}
+	rest := make(map[string]any, len(props))
+	for k, v := range props {
+		rest[k] = v
+	}
+	for _, f := range fields {
+		delete(rest, f.Property)
+	}
+	return rest, func() error {
+		strct := to
+		for strct.Kind() == reflect.Ptr {
+			strct = strct.Elem()
+		}
+		for _, f := range fields {
+			fv, err := strct.FieldByIndexErr(f.Index)
+			if err != nil {
+				return err
+			}
+			if err := r.bindValue(ctx, props[f.Property], fv.Addr()); err != nil {
+				return fmt.Errorf("cannot convert property %s: %w", f.Property, err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package neogo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+// WithCorrelationID is an option for [New] that attaches the correlation ID
+// stored in the context of each query under key, i.e. the ID of the request
+// issuing it, to its transaction metadata as correlationId, to the events
+// received by the [TxListener], and to the messages logged by the BoltLogger of
+// its session. The logs of a request in the application and in the server can
+// then be joined.
+//
+//	type requestIDKey struct{}
+//
+//	d, err := neogo.New(uri, auth, neogo.WithCorrelationID(requestIDKey{}))
+//	ctx = context.WithValue(ctx, requestIDKey{}, "req-42")
+//
+// Values of key which aren't strings are formatted with fmt.
+func WithCorrelationID(key any) Configurer {
+	return func(c *Config) {
+		c.CorrelationIDKey = key
+	}
+}
+
+// correlationID returns the correlation ID stored in ctx, if any.
+func (d *driver) correlationID(ctx context.Context) string {
+	if d == nil || d.correlationIDKey == nil {
+		return ""
+	}
+	switch id := ctx.Value(d.correlationIDKey).(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	default:
+		return fmt.Sprint(id)
+	}
+}
+
+// txMetadata adds the IDs identifying the query run in ctx to metadata: the
+// trace and span IDs active in ctx, the ID of the query watched in ctx, and the
+// correlation ID stored in ctx. Metadata is attached to the transaction and
+// visible in SHOW TRANSACTIONS, so the transaction can be found, and
+// terminated, on the server.
+func (d *driver) txMetadata(ctx context.Context, metadata map[string]any) map[string]any {
+	ids := map[string]any{}
+	if traceID, spanID, ok := d.traceIDs(ctx); ok {
+		ids["traceId"] = traceID
+		if spanID != "" {
+			ids["spanId"] = spanID
+		}
+	}
+	if id, ok := ctx.Value(watchedQueryKey{}).(string); ok {
+		ids["neogoQueryId"] = id
+	}
+	if id := d.correlationID(ctx); id != "" {
+		ids["correlationId"] = id
+	}
+	if len(ids) == 0 {
+		return metadata
+	}
+	out := make(map[string]any, len(metadata)+len(ids))
+	for k, v := range metadata {
+		out[k] = v
+	}
+	for k, v := range ids {
+		out[k] = v
+	}
+	return out
+}
+
+// correlateSession prefixes the messages logged by the BoltLogger of config,
+// if any, with the correlation ID stored in ctx.
+func (d *driver) correlateSession(ctx context.Context, config *neo4j.SessionConfig) {
+	if config.BoltLogger == nil {
+		return
+	}
+	if id := d.correlationID(ctx); id != "" {
+		config.BoltLogger = &correlatedBoltLogger{
+			BoltLogger: config.BoltLogger,
+			// Messages are format strings, so verbs in IDs must be escaped.
+			prefix: "correlationId=" + strings.ReplaceAll(id, "%", "%%") + " ",
+		}
+	}
+}
+
+type correlatedBoltLogger struct {
+	log.BoltLogger
+	prefix string
+}
+
+func (l *correlatedBoltLogger) LogClientMessage(id, msg string, args ...any) {
+	l.BoltLogger.LogClientMessage(id, l.prefix+msg, args...)
+}
+
+func (l *correlatedBoltLogger) LogServerMessage(id, msg string, args ...any) {
+	l.BoltLogger.LogServerMessage(id, l.prefix+msg, args...)
+}
+
+// correlateTx adds a configurer to configurers attaching the IDs identifying
+// the transaction in ctx to its metadata. See txMetadata.
+func (d *driver) correlateTx(ctx context.Context, configurers []func(*neo4j.TransactionConfig)) []func(*neo4j.TransactionConfig) {
+	if d.txMetadata(ctx, nil) == nil {
+		return configurers
+	}
+	return append(configurers[:len(configurers):len(configurers)], func(tc *neo4j.TransactionConfig) {
+		tc.Metadata = d.txMetadata(ctx, tc.Metadata)
+	})
+}
@@ -0,0 +1,81 @@
+package neogo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+type requestIDKey struct{}
+
+type recordingBoltLogger struct {
+	messages []string
+}
+
+func (l *recordingBoltLogger) LogClientMessage(id, msg string, args ...any) {
+	l.messages = append(l.messages, fmt.Sprintf(msg, args...))
+}
+
+func (l *recordingBoltLogger) LogServerMessage(id, msg string, args ...any) {
+	l.messages = append(l.messages, fmt.Sprintf(msg, args...))
+}
+
+func TestCorrelationID(t *testing.T) {
+	d := &driver{correlationIDKey: requestIDKey{}}
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-42%")
+
+	t.Run("attaches the ID to transaction metadata", func(t *testing.T) {
+		metadata := map[string]any{"app": "neogo"}
+		assert.Equal(t, map[string]any{
+			"app":           "neogo",
+			"correlationId": "req-42%",
+		}, d.txMetadata(ctx, metadata))
+		assert.Equal(t, map[string]any{"app": "neogo"}, metadata)
+		assert.Nil(t, d.txMetadata(context.Background(), nil))
+
+		tc := neo4j.TransactionConfig{}
+		for _, c := range d.correlateTx(ctx, nil) {
+			c(&tc)
+		}
+		assert.Equal(t, map[string]any{"correlationId": "req-42%"}, tc.Metadata)
+	})
+
+	t.Run("prefixes bolt logs with the ID", func(t *testing.T) {
+		logger := &recordingBoltLogger{}
+		config := neo4j.SessionConfig{BoltLogger: logger}
+		d.correlateSession(ctx, &config)
+		config.BoltLogger.LogClientMessage("bolt-1", "RUN %q", "RETURN 1")
+		config.BoltLogger.LogServerMessage("bolt-1", "SUCCESS")
+		assert.Equal(t, []string{
+			`correlationId=req-42% RUN "RETURN 1"`,
+			"correlationId=req-42% SUCCESS",
+		}, logger.messages)
+
+		config = neo4j.SessionConfig{BoltLogger: logger}
+		d.correlateSession(context.Background(), &config)
+		assert.Same(t, logger, config.BoltLogger)
+	})
+
+	t.Run("attaches the ID to transaction events", func(t *testing.T) {
+		var events []TxEvent
+		m := NewMock()
+		m.(*mockDriverImpl).correlationIDKey = requestIDKey{}
+		m.(*mockDriverImpl).txListener = TxListenerFunc(func(ctx context.Context, e TxEvent) {
+			events = append(events, e)
+		})
+		m.Bind(map[string]any{})
+		var p tests.Person
+		require.NoError(t, m.Exec().Create(db.Node(&p)).Run(ctx))
+		require.Len(t, events, 2)
+		for _, e := range events {
+			assert.Equal(t, "req-42%", e.CorrelationID)
+		}
+	})
+}
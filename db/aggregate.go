@@ -0,0 +1,94 @@
+package db
+
+import (
+	"strconv"
+
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// CollectDistinct returns the [collect] aggregating function over the distinct
+// values of value, bound to the slice to. Unless qualified, the result is
+// named after the function.
+//
+//	db.Return(db.CollectDistinct(&m.Title, &titles))
+//
+//	// RETURN collect(DISTINCT m.title) AS collect
+//
+// [collect]: https://neo4j.com/docs/cypher-manual/current/functions/aggregating/#functions-collect
+func CollectDistinct[T any](value query.ValueIdentifier, to *[]T) *internal.Variable {
+	return aggregate(&internal.FuncCall{
+		Name:     "collect",
+		Args:     []any{value},
+		Distinct: true,
+	}, to)
+}
+
+// CountDistinct returns the [count] aggregating function over the distinct
+// values of value, bound to to. Unless qualified, the result is named after
+// the function.
+//
+//	db.Return(db.CountDistinct(&p.Name, &n))
+//
+//	// RETURN count(DISTINCT p.name) AS count
+//
+// [count]: https://neo4j.com/docs/cypher-manual/current/functions/aggregating/#functions-count
+func CountDistinct(value query.ValueIdentifier, to *int) *internal.Variable {
+	return aggregate(&internal.FuncCall{
+		Name:     "count",
+		Args:     []any{value},
+		Distinct: true,
+	}, to)
+}
+
+// PercentileCont returns the [percentileCont] aggregating function, which
+// computes the given percentile of value using linear interpolation, bound to
+// to. percentile must be between 0.0 and 1.0.
+//
+//	db.Return(db.PercentileCont(&m.Rating, 0.9, &p90))
+//
+//	// RETURN percentileCont(m.rating, 0.9) AS percentileCont
+//
+// [percentileCont]: https://neo4j.com/docs/cypher-manual/current/functions/aggregating/#functions-percentilecont
+func PercentileCont(value query.ValueIdentifier, percentile float64, to *float64) *internal.Variable {
+	return aggregate(&internal.FuncCall{
+		Name: "percentileCont",
+		Args: []any{value, internal.Expr(strconv.FormatFloat(percentile, 'f', -1, 64))},
+	}, to)
+}
+
+// StDev returns the [stDev] aggregating function, which computes the standard
+// deviation of value for a sample of a population, bound to to.
+//
+// [stDev]: https://neo4j.com/docs/cypher-manual/current/functions/aggregating/#functions-stdev
+func StDev(value query.ValueIdentifier, to *float64) *internal.Variable {
+	return aggregate(&internal.FuncCall{
+		Name: "stDev",
+		Args: []any{value},
+	}, to)
+}
+
+// CollectSubquery returns a [COLLECT subquery], which collects the values
+// returned by subquery into the slice to. Variables from the enclosing query
+// can be referenced within the subquery.
+//
+//	db.Return(db.Qual(db.CollectSubquery(func(c *internal.CypherClient) *internal.CypherRunner {
+//		return c.Match(db.Node(&p).To(db.Var(nil, db.Label("ACTED_IN")), &m)).Return(&m.Title)
+//	}, &titles), "titles"))
+//
+//	// RETURN COLLECT {
+//	//   MATCH (p)-[:ACTED_IN]->(m)
+//	//   RETURN m.title
+//	// } AS titles
+//
+// [COLLECT subquery]: https://neo4j.com/docs/cypher-manual/current/subqueries/collect/
+func CollectSubquery[T any](subquery func(c *internal.CypherClient) *internal.CypherRunner, to *[]T) *internal.Variable {
+	return aggregate(&internal.CollectSubquery{Subquery: subquery}, to)
+}
+
+func aggregate(identifier query.Identifier, to any) *internal.Variable {
+	return &internal.Variable{
+		Identifier: identifier,
+		Bind:       to,
+	}
+}
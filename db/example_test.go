@@ -1,8 +1,11 @@
 package db
 
 import (
+	"time"
+
 	"github.com/rlch/neogo/internal"
 	"github.com/rlch/neogo/internal/tests"
+	"github.com/rlch/neogo/query"
 )
 
 func c() *internal.CypherClient { return internal.NewCypherClient() }
@@ -334,3 +337,223 @@ func ExampleNot() {
 	// MATCH (n)
 	// WHERE NOT n.isBlocked = true
 }
+
+func ExampleUsingIndex() {
+	var p tests.Person
+	c().
+		Match(Node(Qual(&p, "p"))).
+		Using(UsingIndex(&p.Name)).
+		Where(Cond(&p.Name, "=", String("Tom"))).
+		Return(&p).
+		Print()
+	// Output:
+	// MATCH (p:Person)
+	// USING INDEX p:Person(name)
+	// WHERE p.name = "Tom"
+	// RETURN p
+}
+
+func ExampleProcedure() {
+	var (
+		m     tests.Movie
+		score float64
+	)
+	c().
+		CallProcedure(
+			Procedure("db.index.fulltext.queryNodes", String("titles"), NamedParam("matrix", "query")).
+				Yield(Qual(&m, "node", Name("m")), Qual(&score, "score")),
+		).
+		Return(&m, &score).
+		Print()
+	// Output:
+	// CALL db.index.fulltext.queryNodes("titles", $query)
+	// YIELD node AS m, score
+	// RETURN m, score
+}
+
+func ExampleFullTextSearch() {
+	var (
+		m     tests.Movie
+		score float64
+	)
+	c().
+		CallProcedure(FullTextSearch("titles", "matrix").Yield(&m, &score)).
+		Where(Cond(&score, ">", "0.5")).
+		Return(&m, &score).
+		Print()
+	// Output:
+	// CALL db.index.fulltext.queryNodes("titles", $v1)
+	// YIELD node, score
+	// WHERE score > 0.5
+	// RETURN node, score
+}
+
+func ExampleEscape() {
+	c().
+		Match(Node("n")).
+		Return(Expr("n." + Escape("first name"))).
+		Print()
+	// Output:
+	// MATCH (n)
+	// RETURN n.`first name`
+}
+
+func ExampleVectorSearch() {
+	var (
+		m     tests.Movie
+		score float64
+	)
+	c().
+		CallProcedure(VectorSearch("plots", []float32{0.1, 0.2}, 10).Yield(&m, &score)).
+		Return(&m, &score).
+		Print()
+	// Output:
+	// CALL db.index.vector.queryNodes("plots", 10, $v1)
+	// YIELD node, score
+	// RETURN node, score
+}
+
+func ExampleDurationBetween() {
+	c().
+		Match(Node("p")).
+		Where(Cond(DurationBetween("p.joinedAt", DateTime(nil)), "<", Duration(24*time.Hour))).
+		Return(Qual(DateTimeTruncate("day", time.Now()), "today")).
+		Print()
+	// Output:
+	// MATCH (p)
+	// WHERE duration.between(p.joinedAt, datetime()) < $v1
+	// RETURN datetime.truncate("day", $v2) AS today
+}
+
+func ExampleCountDistinct() {
+	var (
+		p      tests.Person
+		m      tests.Movie
+		people int
+		titles []string
+	)
+	c().
+		Match(Node(Qual(&p, "p")).To(Var(nil, Label("ACTED_IN")), Qual(&m, "m"))).
+		Return(CountDistinct(&p, &people), CollectDistinct(&m.Title, &titles)).
+		Print()
+	// Output:
+	// MATCH (p:Person)-[:ACTED_IN]->(m:Movie)
+	// RETURN count(DISTINCT p) AS count, collect(DISTINCT m.title) AS collect
+}
+
+func ExampleReduce() {
+	var total int
+	c().
+		Unwind(Expr("[1, 2, 3]"), "n").
+		With(Qual("collect(n)", "nums")).
+		Return(Qual(Bind(Reduce("total", Expr("0"), "x", "nums", "total + x"), &total), "total")).
+		Print()
+	// Output:
+	// UNWIND [1, 2, 3] AS n
+	// WITH collect(n) AS nums
+	// RETURN reduce(total = 0, x IN nums | total + x) AS total
+}
+
+func ExampleElementID() {
+	var p tests.Person
+	c().
+		Match(Node(Qual(&p, "p"))).
+		Where(Cond(ElementID(&p), "=", Param("4:c0a8:1"))).
+		Return(Qual(ElementID(&p), "elementId")).
+		Print()
+	// Output:
+	// MATCH (p:Person)
+	// WHERE elementId(p) = $v1
+	// RETURN elementId(p) AS elementId
+}
+
+func ExampleExprf() {
+	var p tests.Person
+	c().
+		Match(Node(Qual(&p, "p"))).
+		Return(Qual(Exprf("coalesce(%s, $fallback)", &p.Name, NamedParam("Anonymous", "fallback")), "name")).
+		Print()
+	// Output:
+	// MATCH (p:Person)
+	// RETURN coalesce(p.name, $fallback) AS name
+}
+
+func ExampleFilterFromStruct() {
+	type PersonFilter struct {
+		Name   *string `filter:"name"`
+		MinAge *int    `filter:"age,>="`
+		MaxAge *int    `filter:"age,<="`
+	}
+	var (
+		p      tests.Person
+		minAge = 18
+	)
+	c().
+		Match(Node(Qual(&p, "p", Where(FilterFromStruct(PersonFilter{MinAge: &minAge}))))).
+		Return(&p).
+		Print()
+	// Output:
+	// MATCH (p:Person WHERE p.age >= $v1)
+	// RETURN p
+}
+
+func ExamplePaginate() {
+	var (
+		p      tests.Person
+		people []tests.Person
+	)
+	page, _ := Paginate("", 20, &p.Name)
+	c().
+		Match(Node(Qual(&p, "p"))).
+		Where(page).
+		Return(Return(Bind(&p, &people), page)).
+		Print()
+	// Output:
+	// MATCH (p:Person)
+	// WHERE true
+	// RETURN p
+	// ORDER BY p.name
+	// LIMIT 20
+}
+
+// recommendations is a typed wrapper of a custom procedure, binding its
+// outputs to the given identifiers.
+func recommendations(person query.Identifier, limit int, movie *tests.Movie, score *float64) *internal.Procedure {
+	return Procedure("acme.recommend", person, Param(limit)).
+		Yield(Qual(movie, "movie"), Qual(score, "score"))
+}
+
+func ExampleProcedure_custom() {
+	var (
+		p     tests.Person
+		m     tests.Movie
+		score float64
+	)
+	c().
+		Match(Node(Qual(&p, "p"))).
+		CallProcedure(recommendations(&p, 5, &m, &score)).
+		Return(&m, &score).
+		Print()
+	// Output:
+	// MATCH (p:Person)
+	// CALL acme.recommend(p, $v1)
+	// YIELD movie, score
+	// RETURN movie, score
+}
+
+func ExampleStartsWith() {
+	var p tests.Person
+	c().
+		Match(Node(Qual(&p, "p"))).
+		Where(And(
+			StartsWith(&p.Name, Param("Je")),
+			Not(Contains(&p.Surname, String("man"))),
+			IsNotNull(&p.Position),
+		)).
+		Return(&p).
+		Print()
+	// Output:
+	// MATCH (p:Person)
+	// WHERE p.name STARTS WITH $v1 AND NOT p.surname CONTAINS "man" AND p.position IS NOT NULL
+	// RETURN p
+}
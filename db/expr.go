@@ -2,8 +2,10 @@ package db
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
 )
 
 // Expr returns a Cypher literal [expression].
@@ -13,6 +15,51 @@ func Expr(expr string) internal.Expr {
 	return internal.Expr(expr)
 }
 
+// Exprf returns a raw Cypher [expression], where each %s placeholder in format
+// is replaced by the next argument: bound identifiers and their fields are
+// replaced by their names, and values are injected as parameters. Named
+// parameters, created with [NamedParam], don't fill a placeholder, and are
+// only added to the parameters of the query so they can be referenced
+// directly. Use %% for a literal %.
+//
+//	db.Exprf("coalesce(%s, $fallback)", &n.Name, db.NamedParam("Anonymous", "fallback"))
+//
+//	// coalesce(n.name, $fallback)
+//
+// The expression can be used wherever an identifier or value is expected, or
+// as a condition.
+//
+// [expression]: https://neo4j.com/docs/cypher-manual/current/syntax/expressions/
+func Exprf(format string, args ...any) *internal.Fragment {
+	return &internal.Fragment{
+		Format: format,
+		Args:   args,
+	}
+}
+
+// Raw returns a raw Cypher clause, written by fn when the query is compiled.
+// fn is passed the scope of the query, so the clause can safely refer to the
+// identifiers bound by the builder and inject values as parameters:
+//
+//	c.Match(db.Node(&p)).
+//		Eval(db.Raw(func(s query.Scope) string {
+//			return fmt.Sprintf("CALL acme.touch(%s, %s)", s.Name(&p), s.Param(at))
+//		}))
+//
+//	// MATCH (person:Person)
+//	// CALL acme.touch(person, $v1)
+//
+// Prefer the builder, or [Exprf] for expressions, where possible.
+func Raw(fn func(s query.Scope) string) query.Expression {
+	return rawClause(fn)
+}
+
+type rawClause func(s query.Scope) string
+
+func (r rawClause) Compile(s query.Scope, b *strings.Builder) {
+	b.WriteString(r(s))
+}
+
 // String returns a Cypher [string literal expression], wrapped in double-quotes.
 // This is a convenience function for:
 //
@@ -22,3 +69,33 @@ func Expr(expr string) internal.Expr {
 func String(s string) internal.Expr {
 	return internal.Expr(strconv.Quote(s))
 }
+
+// Escape backtick-quotes a label, relationship type or property name which
+// cannot be written as-is into a query, such as one containing spaces or
+// dashes, so it can be used within raw expressions.
+//
+//	db.Expr("n." + db.Escape("first name"))
+//
+//	// n.`first name`
+//
+// See [naming rules].
+//
+// [naming rules]: https://neo4j.com/docs/cypher-manual/current/syntax/naming/
+func Escape(name string) string {
+	return internal.EscapeName(name)
+}
+
+// ElementID returns the [elementId] of a node or relationship, which replaces
+// the deprecated id function.
+//
+//	db.Cond(db.ElementID(&n), "=", db.Param(n.ElementID))
+//
+//	// WHERE elementId(n) = $v1
+//
+// [elementId]: https://neo4j.com/docs/cypher-manual/current/functions/scalar/#functions-elementid
+func ElementID(identifier query.Identifier) *internal.FuncCall {
+	return &internal.FuncCall{
+		Name: "elementId",
+		Args: []any{identifier},
+	}
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// FilterFromStruct creates a condition from the non-nil pointer fields of
+// filter, a struct or pointer to a struct, for use in a [WHERE] clause. Each
+// field is compared to the property named by its filter tag, or its json tag
+// otherwise, using the operator following the name, which defaults to =.
+// Conditions are joined with AND, and an empty filter matches everything.
+//
+//	type PersonFilter struct {
+//		Name   *string   `filter:"name"`
+//		MinAge *int      `filter:"age,>="`
+//		Cities *[]string `filter:"city,IN"`
+//		Query  *string   `filter:"bio,CONTAINS"`
+//		Token  *string   `filter:"-"`
+//	}
+//
+//	WHERE name = $v1 AND age >= $v2
+//
+// Property names are resolved against the identifier the condition is
+// attached to, as with [Where] in a node pattern or [With]. In a WHERE
+// clause, they are written as-is and should be qualified, as in p.name.
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func FilterFromStruct(filter any) internal.ICondition {
+	v := reflect.ValueOf(filter)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return &internal.Condition{Key: Expr("true")}
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Errorf("FilterFromStruct: expected struct, got %s", v.Type()))
+	}
+	vT := v.Type()
+	var conds []internal.ICondition
+	for i := 0; i < vT.NumField(); i++ {
+		f := vT.Field(i)
+		fv := v.Field(i)
+		if !f.IsExported() || fv.Kind() != reflect.Ptr || fv.IsNil() {
+			continue
+		}
+		prop, op, ok := filterTag(f)
+		if !ok {
+			continue
+		}
+		conds = append(conds, Cond(prop, op, Param(fv.Elem().Interface())))
+	}
+	switch len(conds) {
+	case 0:
+		return &internal.Condition{Key: Expr("true")}
+	case 1:
+		return conds[0]
+	}
+	return And(conds...)
+}
+
+// filterTag returns the property and operator a filter field compares with.
+func filterTag(f reflect.StructField) (prop string, op string, ok bool) {
+	tag, hasTag := f.Tag.Lookup("filter")
+	if tag == "-" {
+		return "", "", false
+	}
+	if hasTag {
+		prop, op, _ = strings.Cut(tag, ",")
+		prop, op = strings.TrimSpace(prop), strings.TrimSpace(op)
+	}
+	if prop == "" {
+		prop, _, _ = strings.Cut(f.Tag.Get("json"), ",")
+		if prop == "-" {
+			return "", "", false
+		}
+	}
+	if prop == "" {
+		prop = f.Name
+	}
+	if op == "" {
+		op = "="
+	}
+	return prop, op, true
+}
@@ -0,0 +1,15 @@
+/*
+Package gds provides builders for procedures of the [Neo4J Graph Data Science]
+library, with typed YIELD targets.
+
+	var (
+		nodeID int64
+		name   string
+		score  float64
+	)
+	c.CallProcedure(gds.PageRankStream("people", nil).Yield(&nodeID, &score)).
+		Return(db.Qual(&name, "gds.util.asNode(nodeId).name", db.Name("name")), &score)
+
+[Neo4J Graph Data Science]: https://neo4j.com/docs/graph-data-science/current/
+*/
+package gds
@@ -0,0 +1,95 @@
+package gds
+
+import (
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+)
+
+func c() *internal.CypherClient { return internal.NewCypherClient() }
+
+func ExampleProject() {
+	var nodes, rels int64
+	c().
+		CallProcedure(
+			Project("people", db.String("Person"), db.String("KNOWS"), nil).
+				Yield(&nodes, &rels),
+		).
+		Return(&nodes, &rels).
+		Print()
+	// Output:
+	// CALL gds.graph.project("people", "Person", "KNOWS")
+	// YIELD nodeCount, relationshipCount
+	// RETURN nodeCount, relationshipCount
+}
+
+func ExampleDrop() {
+	c().
+		CallProcedure(Drop("people").Procedure).
+		Print()
+	// Output:
+	// CALL gds.graph.drop("people")
+}
+
+func ExamplePageRankStream() {
+	var (
+		nodeID int64
+		name   string
+		score  float64
+	)
+	c().
+		CallProcedure(
+			PageRankStream("people", db.NamedParam(map[string]any{"maxIterations": 20}, "config")).
+				Yield(&nodeID, &score),
+		).
+		Return(
+			db.Qual(&name, "gds.util.asNode(nodeId).name", db.Name("name")),
+			&score,
+		).
+		Print()
+	// Output:
+	// CALL gds.pageRank.stream("people", $config)
+	// YIELD nodeId, score
+	// RETURN gds.util.asNode(nodeId).name AS name, score
+}
+
+func ExampleNodeSimilarityStream() {
+	var (
+		node1, node2 int64
+		similarity   float64
+	)
+	c().
+		CallProcedure(
+			NodeSimilarityStream("people", nil).
+				Yield(&node1, &node2, &similarity),
+		).
+		Return(&node1, &node2, &similarity).
+		Print()
+	// Output:
+	// CALL gds.nodeSimilarity.stream("people")
+	// YIELD node1, node2, similarity
+	// RETURN node1, node2, similarity
+}
+
+func ExampleLouvainStream() {
+	var nodeID, communityID, size int64
+	c().
+		CallProcedure(LouvainStream("people", nil).Yield(&nodeID, &communityID)).
+		Return(&communityID, db.Qual(&size, "count(nodeId)", db.Name("size"))).
+		Print()
+	// Output:
+	// CALL gds.louvain.stream("people")
+	// YIELD nodeId, communityId
+	// RETURN communityId, count(nodeId) AS size
+}
+
+func ExampleWCCStream() {
+	var nodeID, componentID int64
+	c().
+		CallProcedure(WCCStream("people", nil).Yield(&nodeID, &componentID)).
+		Return(&nodeID, &componentID).
+		Print()
+	// Output:
+	// CALL gds.wcc.stream("people")
+	// YIELD nodeId, componentId
+	// RETURN nodeId, componentId
+}
@@ -0,0 +1,170 @@
+package gds
+
+import (
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+type (
+	// ProjectProcedure is a call to gds.graph.project.
+	ProjectProcedure struct{ *internal.Procedure }
+	// DropProcedure is a call to gds.graph.drop.
+	DropProcedure struct{ *internal.Procedure }
+	// CentralityProcedure is a call to a centrality algorithm in stream mode,
+	// such as gds.pageRank.stream.
+	CentralityProcedure struct{ *internal.Procedure }
+	// SimilarityProcedure is a call to a similarity algorithm in stream mode,
+	// such as gds.nodeSimilarity.stream.
+	SimilarityProcedure struct{ *internal.Procedure }
+	// CommunityProcedure is a call to a community detection algorithm in
+	// stream mode, such as gds.louvain.stream.
+	CommunityProcedure struct{ *internal.Procedure }
+	// ComponentProcedure is a call to gds.wcc.stream.
+	ComponentProcedure struct{ *internal.Procedure }
+)
+
+// Project creates a [native projection] of the graph into the graph catalog.
+// config is omitted if nil.
+//
+//	CALL gds.graph.project(<graphName>, <nodeProjection>, <relationshipProjection>, <config>)
+//
+// [native projection]: https://neo4j.com/docs/graph-data-science/current/management-ops/graph-creation/graph-project/
+func Project(
+	graphName string,
+	nodeProjection query.ValueIdentifier,
+	relationshipProjection query.ValueIdentifier,
+	config query.ValueIdentifier,
+) ProjectProcedure {
+	return ProjectProcedure{procedure(
+		"gds.graph.project",
+		config,
+		db.String(graphName),
+		nodeProjection,
+		relationshipProjection,
+	)}
+}
+
+// Yield binds the number of projected nodes and relationships.
+//
+//	YIELD nodeCount, relationshipCount
+func (p ProjectProcedure) Yield(nodeCount, relationshipCount *int64) *internal.Procedure {
+	return p.Procedure.Yield(
+		db.Qual(nodeCount, "nodeCount"),
+		db.Qual(relationshipCount, "relationshipCount"),
+	)
+}
+
+// Drop removes a projected graph from the [graph catalog].
+//
+//	CALL gds.graph.drop(<graphName>)
+//
+// [graph catalog]: https://neo4j.com/docs/graph-data-science/current/management-ops/graph-drop/
+func Drop(graphName string) DropProcedure {
+	return DropProcedure{procedure("gds.graph.drop", nil, db.String(graphName))}
+}
+
+// Yield binds the name of the dropped graph.
+//
+//	YIELD graphName
+func (p DropProcedure) Yield(graphName *string) *internal.Procedure {
+	return p.Procedure.Yield(db.Qual(graphName, "graphName"))
+}
+
+// PageRankStream streams the [PageRank] score of each node in the projected
+// graph. config is omitted if nil.
+//
+//	CALL gds.pageRank.stream(<graphName>, <config>)
+//
+// [PageRank]: https://neo4j.com/docs/graph-data-science/current/algorithms/page-rank/
+func PageRankStream(graphName string, config query.ValueIdentifier) CentralityProcedure {
+	return CentralityProcedure{procedure("gds.pageRank.stream", config, db.String(graphName))}
+}
+
+// Yield binds the id and score of each node.
+//
+//	YIELD nodeId, score
+func (p CentralityProcedure) Yield(nodeID *int64, score *float64) *internal.Procedure {
+	return p.Procedure.Yield(
+		db.Qual(nodeID, "nodeId"),
+		db.Qual(score, "score"),
+	)
+}
+
+// NodeSimilarityStream streams the [node similarity] of pairs of nodes in the
+// projected graph. config is omitted if nil.
+//
+//	CALL gds.nodeSimilarity.stream(<graphName>, <config>)
+//
+// [node similarity]: https://neo4j.com/docs/graph-data-science/current/algorithms/node-similarity/
+func NodeSimilarityStream(graphName string, config query.ValueIdentifier) SimilarityProcedure {
+	return SimilarityProcedure{procedure("gds.nodeSimilarity.stream", config, db.String(graphName))}
+}
+
+// Yield binds the ids of each pair of nodes and their similarity.
+//
+//	YIELD node1, node2, similarity
+func (p SimilarityProcedure) Yield(node1, node2 *int64, similarity *float64) *internal.Procedure {
+	return p.Procedure.Yield(
+		db.Qual(node1, "node1"),
+		db.Qual(node2, "node2"),
+		db.Qual(similarity, "similarity"),
+	)
+}
+
+// LouvainStream streams the community of each node in the projected graph,
+// detected using the [Louvain] method. config is omitted if nil.
+//
+//	CALL gds.louvain.stream(<graphName>, <config>)
+//
+// [Louvain]: https://neo4j.com/docs/graph-data-science/current/algorithms/louvain/
+func LouvainStream(graphName string, config query.ValueIdentifier) CommunityProcedure {
+	return CommunityProcedure{procedure("gds.louvain.stream", config, db.String(graphName))}
+}
+
+// LabelPropagationStream streams the community of each node in the projected
+// graph, detected using [label propagation]. config is omitted if nil.
+//
+//	CALL gds.labelPropagation.stream(<graphName>, <config>)
+//
+// [label propagation]: https://neo4j.com/docs/graph-data-science/current/algorithms/label-propagation/
+func LabelPropagationStream(graphName string, config query.ValueIdentifier) CommunityProcedure {
+	return CommunityProcedure{procedure("gds.labelPropagation.stream", config, db.String(graphName))}
+}
+
+// Yield binds the id of each node and the id of its community.
+//
+//	YIELD nodeId, communityId
+func (p CommunityProcedure) Yield(nodeID, communityID *int64) *internal.Procedure {
+	return p.Procedure.Yield(
+		db.Qual(nodeID, "nodeId"),
+		db.Qual(communityID, "communityId"),
+	)
+}
+
+// WCCStream streams the component of each node in the projected graph, found
+// using [weakly connected components]. config is omitted if nil.
+//
+//	CALL gds.wcc.stream(<graphName>, <config>)
+//
+// [weakly connected components]: https://neo4j.com/docs/graph-data-science/current/algorithms/wcc/
+func WCCStream(graphName string, config query.ValueIdentifier) ComponentProcedure {
+	return ComponentProcedure{procedure("gds.wcc.stream", config, db.String(graphName))}
+}
+
+// Yield binds the id of each node and the id of its component.
+//
+//	YIELD nodeId, componentId
+func (p ComponentProcedure) Yield(nodeID, componentID *int64) *internal.Procedure {
+	return p.Procedure.Yield(
+		db.Qual(nodeID, "nodeId"),
+		db.Qual(componentID, "componentId"),
+	)
+}
+
+func procedure(name string, config query.ValueIdentifier, args ...query.ValueIdentifier) *internal.Procedure {
+	if config != nil {
+		args = append(args, config)
+	}
+	return db.Procedure(name, args...)
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// UsingIndex creates an [index hint] for the given properties, which must all
+// belong to the same node or relationship. Multiple properties are used for
+// composite indexes.
+//
+//	USING INDEX <variable>:<label>(<property>, ...)
+//
+// [index hint]: https://neo4j.com/docs/cypher-manual/current/planning-and-tuning/query-tuning/using/#query-using-index-hint
+func UsingIndex(props ...query.PropertyIdentifier) internal.Hint {
+	return internal.Hint{
+		Kind:        internal.HintIndex,
+		Identifiers: props,
+	}
+}
+
+// UsingScan creates a [scan hint] for the given node.
+//
+//	USING SCAN <variable>:<label>
+//
+// [scan hint]: https://neo4j.com/docs/cypher-manual/current/planning-and-tuning/query-tuning/using/#query-using-scan-hint
+func UsingScan(identifier query.Identifier) internal.Hint {
+	return internal.Hint{
+		Kind:        internal.HintScan,
+		Identifiers: []any{identifier},
+	}
+}
+
+// UsingJoinOn creates a [join hint] on the given nodes.
+//
+//	USING JOIN ON <variable>, ..., <variable>
+//
+// [join hint]: https://neo4j.com/docs/cypher-manual/current/planning-and-tuning/query-tuning/using/#query-using-join-hint
+func UsingJoinOn(identifiers ...query.Identifier) internal.Hint {
+	return internal.Hint{
+		Kind:        internal.HintJoin,
+		Identifiers: identifiers,
+	}
+}
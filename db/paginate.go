@@ -0,0 +1,194 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// ErrInvalidCursor is returned when decoding a [Cursor] which was not created
+// by [NextCursor].
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is an opaque position in a paginated result, returned by
+// [NextCursor]. The empty cursor refers to the first page.
+type Cursor string
+
+// Pagination is a page of a keyset-paginated query, created with [Paginate].
+// It is used both as a condition in a [WHERE] clause, which skips the rows
+// before the cursor, and as an option of a [With] or [Return] projection,
+// which orders the rows and limits them to the page.
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+type Pagination struct {
+	*internal.Configurer
+
+	field query.PropertyIdentifier
+	after any
+	limit int
+	desc  bool
+}
+
+// Paginate returns the page of at most limit rows following cursor, ordered by
+// orderField, which must be unique such as an ID or a creation timestamp with a
+// tie-breaking suffix.
+//
+//	page, err := db.Paginate(cursor, 20, &p.ID)
+//	c.Match(db.Node(db.Qual(&p, "p"))).
+//		Where(page).
+//		Return(db.Return(db.Bind(&p, &people), page))
+//	next, err := db.NextCursor(page, people, func(p Person) any { return p.ID })
+//
+//	MATCH (p:Person)
+//	WHERE p.id > $v1
+//	RETURN p
+//	ORDER BY p.id
+//	LIMIT 20
+func Paginate(cursor Cursor, limit int, orderField query.PropertyIdentifier) (*Pagination, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("pagination limit must be positive, got %d", limit)
+	}
+	p := &Pagination{field: orderField, limit: limit}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		p.after = after
+	}
+	p.Configurer = &internal.Configurer{
+		Where: func(w *internal.Where) {
+			w.Conds = append(w.Conds, p.Condition())
+		},
+		ProjectionBody: func(pb *internal.ProjectionBody) {
+			pb.OrderBy = map[any]bool{p.field: !p.desc}
+			pb.Limit = Expr(strconv.Itoa(p.limit))
+		},
+	}
+	return p, nil
+}
+
+// Descending orders the page by orderField in descending order.
+func (p *Pagination) Descending() *Pagination {
+	p.desc = true
+	return p
+}
+
+// Condition returns the condition skipping the rows before the cursor, which is
+// always true on the first page.
+func (p *Pagination) Condition() *internal.Condition {
+	if p.after == nil {
+		return &internal.Condition{Key: Expr("true")}
+	}
+	op := ">"
+	if p.desc {
+		op = "<"
+	}
+	return Cond(p.field, op, Param(p.after)).Condition()
+}
+
+// NextCursor returns the cursor of the page following rows, derived from the
+// value of the order field of its last row, or the empty cursor if rows is the
+// last page.
+func NextCursor[T any](p *Pagination, rows []T, orderValue func(T) any) (Cursor, error) {
+	if len(rows) < p.limit {
+		return "", nil
+	}
+	return encodeCursor(orderValue(rows[len(rows)-1]))
+}
+
+type cursorValue struct {
+	Type  string          `json:"t"`
+	Value json.RawMessage `json:"v"`
+}
+
+func encodeCursor(value any) (Cursor, error) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return "", errors.New("cannot paginate by nil value")
+	}
+	var c cursorValue
+	switch {
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		c.Type = "time"
+		value = v.Interface().(time.Time).Format(time.RFC3339Nano)
+	case v.Kind() == reflect.String:
+		c.Type = "string"
+		value = v.String()
+	case v.CanInt():
+		c.Type = "int"
+		value = v.Int()
+	case v.CanUint():
+		c.Type = "int"
+		value = v.Uint()
+	case v.CanFloat():
+		c.Type = "float"
+		value = v.Float()
+	case v.Kind() == reflect.Bool:
+		c.Type = "bool"
+		value = v.Bool()
+	default:
+		return "", fmt.Errorf("cannot paginate by value of type %s", v.Type())
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	c.Value = raw
+	out, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return Cursor(base64.RawURLEncoding.EncodeToString(out)), nil
+}
+
+func decodeCursor(cursor Cursor) (any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(string(cursor))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	var c cursorValue
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	var value any
+	switch c.Type {
+	case "string":
+		var s string
+		err = json.Unmarshal(c.Value, &s)
+		value = s
+	case "int":
+		var i int64
+		err = json.Unmarshal(c.Value, &i)
+		value = i
+	case "float":
+		var f float64
+		err = json.Unmarshal(c.Value, &f)
+		value = f
+	case "bool":
+		var b bool
+		err = json.Unmarshal(c.Value, &b)
+		value = b
+	case "time":
+		var s string
+		if err = json.Unmarshal(c.Value, &s); err == nil {
+			value, err = time.Parse(time.RFC3339Nano, s)
+		}
+	default:
+		err = fmt.Errorf("unknown type %q", c.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return value, nil
+}
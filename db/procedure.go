@@ -0,0 +1,91 @@
+package db
+
+import (
+	"strconv"
+
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// Procedure creates a [procedure] invocation to be used with CallProcedure.
+// Arguments are resolved in the same way as property values: strings are
+// written as expressions, pointers to bound identifiers are referenced by
+// name, and all other values are injected as parameters.
+//
+//	db.Procedure("db.index.fulltext.queryNodes", "'titles'", db.Param(q)).
+//		Yield(db.Qual(&movie, "node", db.Name("movie")), db.Qual(&score, "score"))
+//
+//	// CALL db.index.fulltext.queryNodes('titles', $v1)
+//	// YIELD node AS movie, score
+//
+// [procedure]: https://neo4j.com/docs/cypher-manual/current/clauses/call/
+func Procedure(name string, args ...query.ValueIdentifier) *internal.Procedure {
+	return &internal.Procedure{
+		Name: name,
+		Args: args,
+	}
+}
+
+// FullTextProcedure is a query of a [full-text index].
+//
+// [full-text index]: https://neo4j.com/docs/cypher-manual/current/indexes/semantic-indexes/full-text-indexes/
+type FullTextProcedure struct {
+	*internal.Procedure
+	output string
+}
+
+// FullTextSearch queries the nodes of a [full-text index]. The search string is
+// injected as a parameter.
+//
+//	CALL db.index.fulltext.queryNodes(<index>, <search>)
+//
+// [full-text index]: https://neo4j.com/docs/cypher-manual/current/indexes/semantic-indexes/full-text-indexes/
+func FullTextSearch(index string, search string) FullTextProcedure {
+	return FullTextProcedure{
+		Procedure: Procedure("db.index.fulltext.queryNodes", String(index), Param(search)),
+		output:    "node",
+	}
+}
+
+// FullTextSearchRelationships queries the relationships of a [full-text index].
+// The search string is injected as a parameter.
+//
+//	CALL db.index.fulltext.queryRelationships(<index>, <search>)
+//
+// [full-text index]: https://neo4j.com/docs/cypher-manual/current/indexes/semantic-indexes/full-text-indexes/
+func FullTextSearchRelationships(index string, search string) FullTextProcedure {
+	return FullTextProcedure{
+		Procedure: Procedure("db.index.fulltext.queryRelationships", String(index), Param(search)),
+		output:    "relationship",
+	}
+}
+
+// Yield binds each matching node or relationship, and its relevance score.
+//
+//	YIELD node, score
+//	YIELD relationship, score
+func (p FullTextProcedure) Yield(entity query.Identifier, score *float64) *internal.Procedure {
+	return p.Procedure.Yield(Qual(entity, p.output), Qual(score, "score"))
+}
+
+// VectorProcedure is a query of a [vector index].
+//
+// [vector index]: https://neo4j.com/docs/cypher-manual/current/indexes/semantic-indexes/vector-indexes/
+type VectorProcedure struct{ *internal.Procedure }
+
+// VectorSearch queries the k nearest neighbours of embedding in a [vector index]
+// of nodes. The embedding is injected as a parameter.
+//
+//	CALL db.index.vector.queryNodes(<index>, <k>, <embedding>)
+//
+// [vector index]: https://neo4j.com/docs/cypher-manual/current/indexes/semantic-indexes/vector-indexes/
+func VectorSearch(index string, embedding []float32, k int) VectorProcedure {
+	return VectorProcedure{Procedure("db.index.vector.queryNodes", String(index), Expr(strconv.Itoa(k)), Param(embedding))}
+}
+
+// Yield binds each neighbouring node, and its similarity score.
+//
+//	YIELD node, score
+func (p VectorProcedure) Yield(node query.Identifier, score *float64) *internal.Procedure {
+	return p.Procedure.Yield(Qual(node, "node"), Qual(score, "score"))
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// Reduce returns a [reduce] expression, which evaluates expr for each element
+// x of list, storing the result in acc, which starts as init. acc and x can be
+// referenced by name within expr. The result can be used in a [RETURN] or
+// [WITH] clause, or as a value.
+//
+//	db.Return(db.Qual(db.Bind(db.Reduce("total", 0, "n", &o.Prices, "total + n"), &total), "total"))
+//
+//	// RETURN reduce(total = $v1, n IN o.prices | total + n) AS total
+//
+// If unqualified, a bound result is named acc.
+//
+// [reduce]: https://neo4j.com/docs/cypher-manual/current/functions/list/#functions-reduce
+// [RETURN]: https://neo4j.com/docs/cypher-manual/current/clauses/return/
+// [WITH]: https://neo4j.com/docs/cypher-manual/current/clauses/with/
+func Reduce(
+	acc string,
+	init query.ValueIdentifier,
+	x string,
+	list query.ValueIdentifier,
+	expr query.ValueIdentifier,
+) *internal.Reduce {
+	return &internal.Reduce{
+		Accumulator: acc,
+		Init:        init,
+		Variable:    x,
+		List:        list,
+		Expr:        expr,
+	}
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// PointDistance returns the geodesic or Euclidean [distance] between two points,
+// which can be compared within a [WHERE] clause. Points can be properties of
+// bound identifiers or neo4j.Point2D/neo4j.Point3D values, which are injected
+// as parameters.
+//
+//	db.Cond(db.PointDistance(&p.Location, origin), "<", "1000")
+//
+//	// WHERE point.distance(p.location, $v1) < 1000
+//
+// [distance]: https://neo4j.com/docs/cypher-manual/current/functions/spatial/#functions-distance
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func PointDistance(from, to query.ValueIdentifier) *internal.FuncCall {
+	return &internal.FuncCall{
+		Name: "point.distance",
+		Args: []any{from, to},
+	}
+}
+
+// PointWithinBBox returns whether point is within the [bounding box] formed by
+// lowerLeft and upperRight. It can be used as a condition within a [WHERE]
+// clause.
+//
+//	db.Where(db.PointWithinBBox(&p.Location, lowerLeft, upperRight))
+//
+//	// WHERE point.withinBBox(p.location, $v1, $v2)
+//
+// [bounding box]: https://neo4j.com/docs/cypher-manual/current/functions/spatial/#functions-withinBBox
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func PointWithinBBox(point, lowerLeft, upperRight query.ValueIdentifier) *internal.FuncCall {
+	return &internal.FuncCall{
+		Name: "point.withinBBox",
+		Args: []any{point, lowerLeft, upperRight},
+	}
+}
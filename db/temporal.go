@@ -0,0 +1,95 @@
+package db
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// DateTime returns a call to the [datetime] function, which creates a zoned
+// DateTime from value. value can be a property of a bound identifier, a
+// time.Time, which is injected as a parameter, or nil for the current
+// DateTime.
+//
+//	db.Cond(&p.CreatedAt, ">", db.DateTime(since))
+//
+//	// WHERE p.createdAt > datetime($v1)
+//
+// [datetime]: https://neo4j.com/docs/cypher-manual/current/functions/temporal/#functions-datetime
+func DateTime(value query.ValueIdentifier) *internal.FuncCall {
+	return temporalFunc("datetime", value)
+}
+
+// Date returns a call to the [date] function, which creates a Date from
+// value. value can be a property of a bound identifier, a time.Time, which is
+// injected as a parameter, or nil for the current Date.
+//
+// [date]: https://neo4j.com/docs/cypher-manual/current/functions/temporal/#functions-date
+func Date(value query.ValueIdentifier) *internal.FuncCall {
+	return temporalFunc("date", value)
+}
+
+// Duration injects d into the [parameters] of a query as a Cypher [Duration].
+//
+//	db.Cond(db.DurationBetween(&p.CreatedAt, db.DateTime(nil)), "<", db.Duration(time.Hour))
+//
+//	// WHERE duration.between(p.createdAt, datetime()) < $v1
+//
+// [parameters]: https://neo4j.com/docs/cypher-manual/current/syntax/parameters/
+// [Duration]: https://neo4j.com/docs/cypher-manual/current/values-and-types/temporal/#cypher-temporal-durations
+func Duration(d time.Duration) internal.Param {
+	return Param(durationOf(d))
+}
+
+// DurationBetween returns a call to the [duration.between] function, which
+// computes the Duration between from and to.
+//
+// [duration.between]: https://neo4j.com/docs/cypher-manual/current/functions/temporal/duration/#functions-duration-between
+func DurationBetween(from, to query.ValueIdentifier) *internal.FuncCall {
+	return &internal.FuncCall{
+		Name: "duration.between",
+		Args: []any{from, to},
+	}
+}
+
+// DateTimeTruncate returns a call to the [datetime.truncate] function, which
+// truncates value to the given unit, such as "day" or "hour".
+//
+//	db.Return(db.Qual(db.DateTimeTruncate("day", t), "day"))
+//
+//	// RETURN datetime.truncate("day", $v1) AS day
+//
+// [datetime.truncate]: https://neo4j.com/docs/cypher-manual/current/functions/temporal/#functions-datetime-truncate
+func DateTimeTruncate(unit string, value query.ValueIdentifier) *internal.FuncCall {
+	return &internal.FuncCall{
+		Name: "datetime.truncate",
+		Args: []any{internal.Expr(strconv.Quote(unit)), value},
+	}
+}
+
+// DateTruncate returns a call to the [date.truncate] function, which truncates
+// value to the given unit, such as "month" or "year".
+//
+// [date.truncate]: https://neo4j.com/docs/cypher-manual/current/functions/temporal/#functions-date-truncate
+func DateTruncate(unit string, value query.ValueIdentifier) *internal.FuncCall {
+	return &internal.FuncCall{
+		Name: "date.truncate",
+		Args: []any{internal.Expr(strconv.Quote(unit)), value},
+	}
+}
+
+func temporalFunc(name string, value query.ValueIdentifier) *internal.FuncCall {
+	f := &internal.FuncCall{Name: name}
+	if value != nil {
+		f.Args = []any{value}
+	}
+	return f
+}
+
+func durationOf(d time.Duration) neo4j.Duration {
+	return neo4j.DurationOf(0, 0, int64(d/time.Second), int(d%time.Second))
+}
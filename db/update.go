@@ -1,6 +1,9 @@
 package db
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/rlch/neogo/internal"
 	"github.com/rlch/neogo/query"
 )
@@ -21,6 +24,16 @@ func SetPropValue(identifier query.PropertyIdentifier, value query.ValueIdentifi
 //
 //	SET <identifier> += <properties>
 //
+// properties may be a map[string]any keyed by property name, which is
+// injected as a parameter. When identifier is a node or relationship, the
+// entries of the map are encoded as the fields of its type are, so nested,
+// flattened and temporal properties are written as they would be from a
+// struct.
+//
+//	db.SetMerge(&n, map[string]any{"age": 38, "address": Address{City: "Paris"}})
+//
+//	SET n += $v1
+//
 // [SET]: https://neo4j.com/docs/cypher-manual/current/clauses/set/
 func SetMerge(identifier query.PropertyIdentifier, properties any) internal.SetItem {
 	return internal.SetItem{
@@ -34,6 +47,10 @@ func SetMerge(identifier query.PropertyIdentifier, properties any) internal.SetI
 //
 //	SET <identifier>:<label>:...:<label>
 //
+// As labels cannot be parameterized, labels which are not valid identifiers
+// are escaped with backticks. This allows labels known only at runtime to be
+// used, though they should still be validated against an allow-list.
+//
 // [SET]: https://neo4j.com/docs/cypher-manual/current/clauses/set/
 func SetLabels(identifier query.PropertyIdentifier, labels ...string) internal.SetItem {
 	return internal.SetItem{
@@ -44,7 +61,7 @@ func SetLabels(identifier query.PropertyIdentifier, labels ...string) internal.S
 
 // RemoveProp removes a property in a [REMOVE] clause.
 //
-//	SET <identifier>.<prop>
+//	REMOVE <identifier>.<prop>
 //
 // [REMOVE]: https://neo4j.com/docs/cypher-manual/current/clauses/remove/
 func RemoveProp(identifier query.PropertyIdentifier) internal.RemoveItem {
@@ -57,6 +74,8 @@ func RemoveProp(identifier query.PropertyIdentifier) internal.RemoveItem {
 //
 //	REMOVE <identifier>:<label>:...:<label>
 //
+// Labels are escaped as in [SetLabels].
+//
 // [REMOVE]: https://neo4j.com/docs/cypher-manual/current/clauses/remove/
 func RemoveLabels(identifier query.PropertyIdentifier, labels ...string) internal.RemoveItem {
 	return internal.RemoveItem{
@@ -94,3 +113,90 @@ func OnMatch(set ...internal.SetItem) internal.MergeOption {
 		},
 	}
 }
+
+// Upsert sets the properties of identifier, a node or relationship bound in a
+// [MERGE] pattern, to those of entity when the MERGE creates or matches it.
+// How each property is merged is configured by the neogo tag of its field:
+//
+//	type Article struct {
+//		neogo.Node `neo4j:"Article"`
+//
+//		Title     string    `json:"title"`
+//		Views     int       `json:"views" neogo:"oncreate"`
+//		UpdatedAt time.Time `json:"updatedAt" neogo:"onmatch"`
+//		Tags      []string  `json:"tags" neogo:"append"`
+//	}
+//
+//	var a Article
+//	c.Merge(
+//		db.Node(db.Qual(&a, "a", db.Props{"id": db.Param(article.ID)})),
+//		db.Upsert(&a, article),
+//	)
+//
+//	MERGE (a:Article {id: $v1})
+//	ON CREATE
+//	  SET a += $v2
+//	ON MATCH
+//	  SET
+//	    a += $v3,
+//	    a.tags = coalesce(a.tags, []) + $v4
+//
+// Fields tagged oncreate are only written when the entity is created, and
+// those tagged onmatch only when it is matched. Fields tagged append are
+// written when the entity is created, and appended to the existing list when
+// it is matched. Other fields are always written. Properties are written as
+// those of entities in patterns are: zero fields are omitted unless tagged
+// keepzero or included by WithZeroValues, and created entities are given their
+// defaults and timestamps, without modifying entity. If entity implements
+// [internal.BeforeSaver], its hook is called when the query is run, and the
+// properties are read again afterwards. Fields tagged append are only read
+// when the query is built.
+//
+// identifier should be zero-valued, so the pattern only matches on the
+// properties given explicitly, with entity holding the values to write.
+//
+// [MERGE]: https://neo4j.com/docs/cypher-manual/current/clauses/merge/
+func Upsert(identifier any, entity any) internal.MergeOption {
+	v := reflect.ValueOf(entity)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	id := reflect.ValueOf(identifier)
+	if id.Kind() != reflect.Ptr || id.Elem().Type() != v.Type() {
+		panic(fmt.Errorf("Upsert: identifier must be a pointer to %s, got %T", v.Type(), identifier))
+	}
+	var (
+		onCreate, onMatch bool
+		appends           []internal.SetItem
+	)
+	for _, f := range internal.UpsertFields(v.Type()) {
+		switch f.Strategy {
+		case internal.MergeOnCreate:
+			onCreate = true
+		case internal.MergeOnMatch:
+			onMatch = true
+		case internal.MergeAppend:
+			onCreate = true
+			value := v.FieldByIndex(f.Index)
+			if value.IsZero() {
+				continue
+			}
+			field := id.Elem().FieldByIndex(f.Index).Addr().Interface()
+			appends = append(appends, SetPropValue(field, Exprf("coalesce(%s, []) + %s", field, Param(value.Interface()))))
+		default:
+			onCreate = true
+			onMatch = true
+		}
+	}
+	return &internal.Configurer{
+		Merge: func(mo *internal.Merge) {
+			if onCreate {
+				mo.OnCreate = append(mo.OnCreate, SetMerge(identifier, &internal.UpsertProps{Entity: entity, Created: true}))
+			}
+			if onMatch {
+				mo.OnMatch = append(mo.OnMatch, SetMerge(identifier, &internal.UpsertProps{Entity: entity}))
+			}
+			mo.OnMatch = append(mo.OnMatch, appends...)
+		},
+	}
+}
@@ -69,6 +69,31 @@ func Bind(identifier query.Identifier, toPtr any) *internal.Variable {
 	}
 }
 
+// Chan returns an identifier for ch, which must be a channel that can be sent
+// to. When the query is run, each row is bound to a new value of the element
+// type of ch and sent to ch as the result is streamed, blocking until it is
+// received. ch is closed once the query completes or fails.
+//
+// Rows are sent before the transaction of the query commits, so they may
+// describe writes which are rolled back if it fails. The query is therefore
+// run in a transaction which is not retried, unless it is run within a
+// transaction function, i.e. of WriteTransaction, which resends rows if it is
+// retried.
+//
+//	people := make(chan tests.Person)
+//	go func() {
+//		for p := range people {
+//			// ...
+//		}
+//	}()
+//	err := d.Exec().
+//		Match(db.Node(db.Qual(db.Chan(people), "p"))).
+//		Return(db.Chan(people)).
+//		Run(ctx)
+func Chan(ch any) *internal.Chan {
+	return internal.NewChan(ch)
+}
+
 // Name qualifies a [variable] with a name.
 //
 // [variable]: https://neo4j.com/docs/cypher-manual/current/syntax/variables/
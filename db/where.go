@@ -1,6 +1,9 @@
 package db
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/rlch/neogo/internal"
 	"github.com/rlch/neogo/query"
 )
@@ -54,6 +57,105 @@ func Cond(
 	}
 }
 
+// StartsWith creates a condition matching strings which start with prefix.
+//
+//	WHERE <key> STARTS WITH <prefix>
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func StartsWith(key query.PropertyIdentifier, prefix query.ValueIdentifier) internal.ICondition {
+	return Cond(key, "STARTS WITH", prefix)
+}
+
+// EndsWith creates a condition matching strings which end with suffix.
+//
+//	WHERE <key> ENDS WITH <suffix>
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func EndsWith(key query.PropertyIdentifier, suffix query.ValueIdentifier) internal.ICondition {
+	return Cond(key, "ENDS WITH", suffix)
+}
+
+// Contains creates a condition matching strings which contain substring.
+//
+//	WHERE <key> CONTAINS <substring>
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func Contains(key query.PropertyIdentifier, substring query.ValueIdentifier) internal.ICondition {
+	return Cond(key, "CONTAINS", substring)
+}
+
+// Matches creates a condition matching strings which match the regular
+// expression pattern.
+//
+//	WHERE <key> =~ <pattern>
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func Matches(key query.PropertyIdentifier, pattern query.ValueIdentifier) internal.ICondition {
+	return Cond(key, "=~", pattern)
+}
+
+// IsNull creates a condition matching missing or null values.
+//
+//	WHERE <key> IS NULL
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func IsNull(key query.PropertyIdentifier) internal.ICondition {
+	return Cond(key, "IS", Expr("NULL"))
+}
+
+// IsNotNull creates a condition matching values which are present.
+//
+//	WHERE <key> IS NOT NULL
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func IsNotNull(key query.PropertyIdentifier) internal.ICondition {
+	return Cond(key, "IS NOT", Expr("NULL"))
+}
+
+// In creates a condition matching values contained in values, a slice which is
+// injected as a single parameter.
+//
+//	WHERE <key> IN $values
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func In(key query.PropertyIdentifier, values any) internal.ICondition {
+	return Cond(key, "IN", Param(values))
+}
+
+// InTuples creates a condition matching rows whose properties are contained,
+// together, in tuples: a slice of maps keyed by the keys of fields, which is
+// injected as a single parameter.
+//
+//	db.InTuples(map[string]query.PropertyIdentifier{
+//		"name":    &p.Name,
+//		"surname": &p.Surname,
+//	}, []map[string]any{
+//		{"name": "Spongebob", "surname": "Squarepants"},
+//	})
+//
+//	WHERE {name: p.name, surname: p.surname} IN $tuples
+//
+// [WHERE]: https://neo4j.com/docs/cypher-manual/current/clauses/where/
+func InTuples(fields map[string]query.PropertyIdentifier, tuples any) internal.ICondition {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var format strings.Builder
+	args := make([]any, len(keys))
+	format.WriteString("{")
+	for i, key := range keys {
+		if i > 0 {
+			format.WriteString(", ")
+		}
+		format.WriteString(key + ": %s")
+		args[i] = fields[key]
+	}
+	format.WriteString("}")
+	return Cond(Exprf(format.String(), args...), "IN", Param(tuples))
+}
+
 // Or creates an OR condition for use in a [WHERE] clause.
 //
 //	WHERE <cond> OR <cond> ... OR <cond>
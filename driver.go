@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
@@ -30,6 +31,15 @@ func New(
 		c(cfg)
 	}
 
+	if err := checkTypes(cfg.Types...); err != nil {
+		return nil, fmt.Errorf("failed to register types: %w", err)
+	}
+	if cfg.TagCheck {
+		if err := checkTags(cfg.Types...); err != nil {
+			return nil, fmt.Errorf("failed to register types: %w", err)
+		}
+	}
+
 	neo4j, err := neo4j.NewDriverWithContext(
 		target,
 		auth,
@@ -42,6 +52,7 @@ func New(
 	d := driver{
 		db:                   neo4j,
 		causalConsistencyKey: cfg.CausalConsistencyKey,
+		bookmarks:            newBookmarkCache(),
 		sessionSemaphore:     semaphore.NewWeighted(int64(cfg.Config.MaxConnectionPoolSize)),
 	}
 
@@ -49,6 +60,58 @@ func New(
 	if len(cfg.Types) > 0 {
 		d.registerTypes(cfg.Types...)
 	}
+	d.propertyAliases = cfg.PropertyAliases
+	d.onDeprecatedField = cfg.DeprecatedFieldHandler
+	if d.onDeprecatedField == nil {
+		d.onDeprecatedField = warnDeprecatedFields(cfg.Log)
+	}
+	d.timeZone = cfg.TimeZone
+	d.zonelessTimeZone = cfg.ZonelessTimeZone
+	d.nativeTimes = cfg.NativeTimes
+	if cfg.ChangeTracking {
+		d.snapshots = newSnapshots(cfg.ChangeTrackingLimit)
+	}
+	d.views = newViews(&d, cfg.Views)
+	d.paramNamer = cfg.ParamNamer
+	if cfg.Timestamps {
+		d.clock = cfg.Clock
+		if d.clock == nil {
+			d.clock = time.Now
+		}
+	}
+	d.features = cfg.FeatureFlags
+	d.actor = cfg.AuditActor
+	d.defaultFuncs = cfg.DefaultFuncs
+	d.onConnectionChurn = cfg.ConnectionChurnHandler
+	d.traceContext = cfg.TraceContext
+	d.txListener = cfg.TxListener
+	d.paramTransformers = cfg.ParamTransformers
+	d.hooks = newHookSet(cfg.UnmarshalHooks, cfg.Processors)
+	d.converters = cfg.TypeConverters
+	d.encrypter = cfg.Encrypter
+	d.includeZero = cfg.IncludeZeroValues
+	d.timeouts = cfg.DefaultContextTimeouts
+	d.correlationIDKey = cfg.CorrelationIDKey
+	d.blobStore = cfg.BlobStore
+	d.blobThreshold = cfg.BlobThreshold
+	d.namespace = cfg.Namespace
+	d.registeredOnly = cfg.RegisteredAbstractOnly
+	if cfg.RuntimeTagCheck {
+		d.tagChecker = &tagChecker{}
+	}
+	if cfg.QueryWatchdog > 0 {
+		d.watchdog = &watchdog{
+			max:       cfg.QueryWatchdog,
+			onRunaway: cfg.RunawayQueryHandler,
+			terminate: d.terminateQuery,
+			nonce:     newWatchdogNonce(),
+		}
+		if d.watchdog.onRunaway == nil {
+			d.watchdog.onRunaway = warnRunawayQueries(cfg.Log)
+		}
+	}
+	d.lazyDriver = &d
+	d.projections = newProjections(cfg.Projections)
 
 	return &d, nil
 }
@@ -58,16 +121,25 @@ type (
 	// provides an entrypoint to a neogo [query.Client], which can be used to build
 	// cypher queries.
 	//
-	// It's safe for concurrent use.
+	// It's safe for concurrent use. Features beyond running queries, i.e.
+	// [IsDirty], [ViewsOf] or [RequireSchema], are functions taking a Driver,
+	// so the interface stays small enough to implement and decorate.
 	Driver interface {
+		BaseDriver
+
 		// DB returns the underlying neo4j driver.
 		DB() neo4j.DriverWithContext
+	}
 
+	// BaseDriver is the minimal interface of a [Driver], used to run queries.
+	// Applications can depend on it rather than [Driver] to mock or decorate
+	// the driver, i.e. with [DriverWrapper].
+	BaseDriver interface {
 		// ReadSession creates a new read-access session based on the specified session configuration.
-		ReadSession(ctx context.Context, configurers ...func(*neo4j.SessionConfig)) readSession
+		ReadSession(ctx context.Context, configurers ...func(*neo4j.SessionConfig)) ReadSession
 
 		// WriteSession creates a new write-access session based on the specified session configuration.
-		WriteSession(ctx context.Context, configurers ...func(*neo4j.SessionConfig)) writeSession
+		WriteSession(ctx context.Context, configurers ...func(*neo4j.SessionConfig)) WriteSession
 
 		// Exec creates a new transaction + session and executes the given Cypher
 		// query.
@@ -77,7 +149,29 @@ type (
 		// override the access mode.
 		//
 		// The session is closed after the query is executed.
-		Exec(configurers ...func(*execConfig)) Query
+		Exec(configurers ...ExecOption) Query
+
+		// Close closes the underlying neo4j driver, and all of its connections.
+		Close(ctx context.Context) error
+	}
+
+	// ExecOption configures a query run with [BaseDriver.Exec], i.e.
+	// [WithTxConfig] or [WithSessionConfig].
+	ExecOption = func(*execConfig)
+
+	// DriverWrapper decorates a [Driver], delegating each of its methods to the
+	// wrapped driver. Decorators embed it and override the methods they wrap:
+	//
+	//	type auditedDriver struct {
+	//		neogo.DriverWrapper
+	//	}
+	//
+	//	func (d auditedDriver) Exec(opts ...neogo.ExecOption) neogo.Query {
+	//		audit.Record("exec")
+	//		return d.DriverWrapper.Exec(opts...)
+	//	}
+	DriverWrapper struct {
+		Driver
 	}
 
 	// Expression is an interface for compiling a Cypher expression outside the context of a query.
@@ -106,7 +200,9 @@ type (
 		Close(ctx context.Context, joinedErrors ...error) error
 	}
 
-	readSession interface {
+	// ReadSession is a session running read transactions, created with
+	// [BaseDriver.ReadSession].
+	ReadSession interface {
 		// Session returns the underlying Neo4J session.
 		Session() neo4j.SessionWithContext
 		// Close closes any open resources and marks this session as unusable.
@@ -117,8 +213,10 @@ type (
 		ReadTransaction(ctx context.Context, work Work, configurers ...func(*neo4j.TransactionConfig)) error
 		BeginTransaction(ctx context.Context, configurers ...func(*neo4j.TransactionConfig)) (Transaction, error)
 	}
-	writeSession interface {
-		readSession
+	// WriteSession is a session running read and write transactions, created
+	// with [BaseDriver.WriteSession].
+	WriteSession interface {
+		ReadSession
 		// ExecuteWrite executes the given unit of work in a AccessModeWrite transaction with retry logic in place.
 		// Contexts terminating too early negatively affect connection pooling and degrade the driver performance.
 		WriteTransaction(ctx context.Context, work Work, configurers ...func(*neo4j.TransactionConfig)) error
@@ -130,26 +228,98 @@ type (
 		registry
 		db                   neo4j.DriverWithContext
 		causalConsistencyKey func(ctx context.Context) string
+		bookmarks            *bookmarkCache
 		sessionSemaphore     *semaphore.Weighted
+		views                *viewsImpl
+		traceContext         TraceContext
+		txListener           TxListener
+		paramTransformers    []ParamTransformer
+		watchdog             *watchdog
+		onConnectionChurn    func(ConnectionChurn)
+		timeouts             ContextTimeouts
+		correlationIDKey     any
 	}
 	session struct {
 		*driver
 		registry
-		db          neo4j.DriverWithContext
-		execConfig  execConfig
-		session     neo4j.SessionWithContext
-		currentTx   neo4j.ManagedTransaction
+		db         neo4j.DriverWithContext
+		execConfig execConfig
+		session    neo4j.SessionWithContext
+		currentTx  neo4j.ManagedTransaction
+		// txDeadline bounds the queries run in currentTx by the timeout of
+		// the transaction, as their work runs with its own context.
+		txDeadline time.Time
+		accessMode neo4j.AccessMode
+		// txStats records the statements run in the transaction of the session
+		// for the transaction listener, if any.
+		txStats     *txStats
 		releaseOnce sync.Once
 		done        chan struct{}
 	}
 	transactionImpl struct {
 		session *session
 		tx      neo4j.ExplicitTransaction
+		stats   *txStats
 	}
 )
 
 func (d *driver) DB() neo4j.DriverWithContext { return d.db }
 
+func (d *driver) Close(ctx context.Context) error { return d.db.Close(ctx) }
+
+// Unwrap returns the wrapped driver.
+func (w DriverWrapper) Unwrap() Driver { return w.Driver }
+
+// ErrUnsupportedDriver is returned by the functions taking a [Driver] which
+// need the driver created by [New] or [NewMock], when given another
+// implementation.
+var ErrUnsupportedDriver = errors.New("driver was not created by neogo.New")
+
+// driverOf returns the driver created by [New] or [NewMock] that d is, or
+// decorates with [DriverWrapper], or nil.
+func driverOf(d Driver) *driver {
+	for d != nil {
+		switch v := d.(type) {
+		case interface{ impl() *driver }:
+			return v.impl()
+		case interface{ Unwrap() Driver }:
+			d = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (d *driver) impl() *driver { return d }
+
+// IsDirty reports whether the node pointed to by entity has changed since it
+// was last read by d, when [WithChangeTracking] is enabled. Nodes which have
+// not been read, or when change tracking is disabled, are always dirty.
+func IsDirty(d Driver, entity any) bool {
+	impl := driverOf(d)
+	return impl == nil || impl.isDirty(entity)
+}
+
+// ReleaseSnapshots forgets the snapshots d took of the nodes pointed to by
+// entities, or the elements of slices of them, once they are no longer
+// tracked for changes, i.e. at the end of a request. They are dirty until they
+// are read again.
+func ReleaseSnapshots(d Driver, entities ...any) {
+	if impl := driverOf(d); impl != nil {
+		impl.releaseSnapshots(entities...)
+	}
+}
+
+// ViewsOf returns the materialized views registered with d by [WithViews].
+func ViewsOf(d Driver) Views {
+	impl := driverOf(d)
+	if impl == nil || impl.views == nil {
+		return newViews(impl, nil)
+	}
+	return impl.views
+}
+
 func (d *driver) Exec(configurers ...func(*execConfig)) Query {
 	sessionConfig := neo4j.SessionConfig{}
 	txConfig := neo4j.TransactionConfig{}
@@ -172,7 +342,18 @@ func (d *driver) Exec(configurers ...func(*execConfig)) Query {
 		db:         d.db,
 		execConfig: config,
 	}
-	return session.newClient(internal.NewCypherClient())
+	if config.withoutHooks || len(config.unmarshalHooks) > 0 {
+		session.hooks = d.hooks.withQueryHooks(config.unmarshalHooks, config.withoutHooks)
+	}
+	cy := internal.NewCypherClient()
+	if config.projection != "" {
+		if fields, err := d.projections.get(config.projection); err != nil {
+			cy.AddError(err)
+		} else {
+			cy.SetProjections(fields)
+		}
+	}
+	return session.newClient(cy)
 }
 
 func (d *driver) ensureCausalConsistency(ctx context.Context, sc *neo4j.SessionConfig) {
@@ -183,30 +364,32 @@ func (d *driver) ensureCausalConsistency(ctx context.Context, sc *neo4j.SessionC
 	if key = d.causalConsistencyKey(ctx); key == "" {
 		return
 	}
-	bookmarks := causalConsistencyCache[key]
+	bookmarks := d.bookmarks.get(principalOf(sc), key)
 	if bookmarks == nil {
 		return
 	}
 	sc.Bookmarks = bookmarks
 }
 
-func (d *driver) ReadSession(ctx context.Context, configurers ...func(*neo4j.SessionConfig)) readSession {
+func (d *driver) ReadSession(ctx context.Context, configurers ...func(*neo4j.SessionConfig)) ReadSession {
 	config := neo4j.SessionConfig{}
 	for _, c := range configurers {
 		c(&config)
 	}
 	config.AccessMode = neo4j.AccessModeRead
 	d.ensureCausalConsistency(ctx, &config)
+	d.correlateSession(ctx, &config)
 	if err := d.sessionSemaphore.Acquire(ctx, 1); err != nil {
 		panic(fmt.Errorf("failed to acquire session semaphore: %w", err))
 	}
 	sess := d.db.NewSession(ctx, config)
 	s := &session{
-		driver:   d,
-		registry: d.registry,
-		db:       d.db,
-		session:  sess,
-		done:     make(chan struct{}),
+		driver:     d,
+		registry:   d.registry,
+		db:         d.db,
+		session:    sess,
+		accessMode: config.AccessMode,
+		done:       make(chan struct{}),
 	}
 	go func() {
 		select {
@@ -218,23 +401,25 @@ func (d *driver) ReadSession(ctx context.Context, configurers ...func(*neo4j.Ses
 	return s
 }
 
-func (d *driver) WriteSession(ctx context.Context, configurers ...func(*neo4j.SessionConfig)) writeSession {
+func (d *driver) WriteSession(ctx context.Context, configurers ...func(*neo4j.SessionConfig)) WriteSession {
 	config := neo4j.SessionConfig{}
 	for _, c := range configurers {
 		c(&config)
 	}
 	config.AccessMode = neo4j.AccessModeWrite
 	d.ensureCausalConsistency(ctx, &config)
+	d.correlateSession(ctx, &config)
 	if err := d.sessionSemaphore.Acquire(ctx, 1); err != nil {
 		panic(fmt.Errorf("failed to acquire session semaphore: %w", err))
 	}
 	sess := d.db.NewSession(ctx, config)
 	s := &session{
-		driver:   d,
-		registry: d.registry,
-		db:       d.db,
-		session:  sess,
-		done:     make(chan struct{}),
+		driver:     d,
+		registry:   d.registry,
+		db:         d.db,
+		session:    sess,
+		accessMode: config.AccessMode,
+		done:       make(chan struct{}),
 	}
 	go func() {
 		select {
@@ -271,24 +456,40 @@ func (s *session) Close(ctx context.Context, errs ...error) error {
 }
 
 func (s *session) ReadTransaction(ctx context.Context, work Work, configurers ...func(*neo4j.TransactionConfig)) error {
+	ctx, cancel := s.txContext(ctx, false)
+	defer cancel()
+	stats := s.beginTx(ctx, neo4j.AccessModeRead)
+	s.txStats = stats
+	defer func() { s.txStats = nil }()
 	_, err := s.session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		stats.reset()
 		return nil, work(func() Query {
 			c := s.newClient(internal.NewCypherClient())
 			c.currentTx = tx
+			c.txDeadline, _ = ctx.Deadline()
 			return c
 		})
-	}, configurers...)
+	}, s.correlateTx(ctx, configurers)...)
+	stats.finish(ctx, err)
 	return err
 }
 
 func (s *session) WriteTransaction(ctx context.Context, work Work, configurers ...func(*neo4j.TransactionConfig)) error {
+	ctx, cancel := s.txContext(ctx, true)
+	defer cancel()
+	stats := s.beginTx(ctx, neo4j.AccessModeWrite)
+	s.txStats = stats
+	defer func() { s.txStats = nil }()
 	_, err := s.session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		stats.reset()
 		return nil, work(func() Query {
 			c := s.newClient(internal.NewCypherClient())
 			c.currentTx = tx
+			c.txDeadline, _ = ctx.Deadline()
 			return c
 		})
-	}, configurers...)
+	}, s.correlateTx(ctx, configurers)...)
+	stats.finish(ctx, err)
 	return err
 }
 
@@ -297,7 +498,9 @@ func (s *session) BeginTransaction(ctx context.Context, configurers ...func(*neo
 	if err != nil {
 		return nil, err
 	}
-	return &transactionImpl{s, tx}, nil
+	stats := s.beginTx(ctx, s.accessMode)
+	s.txStats = stats
+	return &transactionImpl{session: s, tx: tx, stats: stats}, nil
 }
 
 func (t *transactionImpl) Run(work Work) error {
@@ -309,15 +512,34 @@ func (t *transactionImpl) Run(work Work) error {
 }
 
 func (t *transactionImpl) Commit(ctx context.Context) error {
-	return t.tx.Commit(ctx)
+	err := t.tx.Commit(ctx)
+	t.end(ctx, TxCommit, err)
+	return err
 }
 
 func (t *transactionImpl) Rollback(ctx context.Context) error {
-	return t.tx.Rollback(ctx)
+	err := t.tx.Rollback(ctx)
+	t.end(ctx, TxRollback, err)
+	return err
+}
+
+// end notifies the transaction listener, if any, that the transaction ended
+// with kind; a failed commit is reported as a rollback.
+func (t *transactionImpl) end(ctx context.Context, kind TxEventKind, err error) {
+	if t.stats == nil {
+		return
+	}
+	if err != nil {
+		kind = TxRollback
+	}
+	t.stats.end(ctx, kind, err)
+	t.stats = nil
+	t.session.txStats = nil
 }
 
 func (t *transactionImpl) Close(ctx context.Context, errs ...error) error {
 	sessErr := t.tx.Close(ctx)
+	t.end(ctx, TxRollback, nil)
 	if sessErr != nil {
 		errs = append(errs, sessErr)
 		return errors.Join(errs...)
@@ -110,13 +110,13 @@ func ExampleDriver() {
 		Print().
 		Run(ctx)
 	fmt.Printf("err: %v\n", err)
-	fmt.Printf("person: %v\n", person)
+	fmt.Printf("person: %s %s %s %d\n", person.ID, person.Name, person.Surname, person.Age)
 	// Output:
 	// CREATE (person:Person {id: $person_id, name: $person_name, surname: $person_surname})
 	// SET person.age = $v1
 	// RETURN person
 	// err: <nil>
-	// person: {{some-unique-id} Spongebob Squarepants 20}
+	// person: some-unique-id Spongebob Squarepants 20
 }
 
 func ExampleDriver_readSession() {
@@ -0,0 +1,93 @@
+package neogo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// ErrNoEncrypter is returned when writing or reading a field tagged with
+// `neogo:"encrypt"` without an [Encrypter], so it is never stored as
+// plaintext.
+var ErrNoEncrypter = errors.New("encrypted field used without an Encrypter")
+
+// Encrypter encrypts and decrypts the values of string fields tagged with
+// `neogo:"encrypt"`, i.e. with an envelope key from a KMS. Ciphertexts are
+// stored as strings, so binary ciphertexts should be encoded, i.e. as base64.
+type Encrypter interface {
+	Encrypt(plaintext string) (ciphertext string, err error)
+	Decrypt(ciphertext string) (plaintext string, err error)
+}
+
+// WithEncrypter is an option for [New] that encrypts the string fields tagged
+// with `neogo:"encrypt"` with e before they are written, and decrypts them
+// when they are read:
+//
+//	type Patient struct {
+//		neogo.Node `neo4j:"Patient"`
+//
+//		SSN string `json:"ssn" neogo:"encrypt"`
+//	}
+//
+// Empty strings are not encrypted. Encrypted properties cannot be compared
+// with plaintext in queries, unless e is deterministic.
+func WithEncrypter(e Encrypter) Configurer {
+	return func(c *Config) {
+		c.Encrypter = e
+	}
+}
+
+var encryptedType = reflect.TypeOf(internal.Encrypted(""))
+
+// encrypt encrypts plaintext, the value of an encrypted field.
+func (r *registry) encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	if r.encrypter == nil {
+		return "", ErrNoEncrypter
+	}
+	ciphertext, err := r.encrypter.Encrypt(plaintext)
+	if err != nil {
+		return "", fmt.Errorf("cannot encrypt field: %w", err)
+	}
+	return ciphertext, nil
+}
+
+// decryptFields decrypts the encrypted fields of the struct v points to, once
+// they have been bound.
+func (r *registry) decryptFields(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	for _, f := range internal.UpsertFields(v.Type()) {
+		if !internal.IsEncrypted(v.Type().FieldByIndex(f.Index)) {
+			continue
+		}
+		fv, err := v.FieldByIndexErr(f.Index)
+		if err != nil {
+			continue
+		}
+		fv = reflect.Indirect(fv)
+		if !fv.IsValid() || fv.String() == "" {
+			continue
+		}
+		if r.encrypter == nil {
+			return fmt.Errorf("cannot decrypt %s: %w", f.Property, ErrNoEncrypter)
+		}
+		plaintext, err := r.encrypter.Decrypt(fv.String())
+		if err != nil {
+			return fmt.Errorf("cannot decrypt %s: %w", f.Property, err)
+		}
+		fv.SetString(plaintext)
+	}
+	return nil
+}
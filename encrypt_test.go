@@ -0,0 +1,114 @@
+package neogo
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+)
+
+type prefixEncrypter struct{}
+
+func (prefixEncrypter) Encrypt(plaintext string) (string, error) {
+	return "enc:" + plaintext, nil
+}
+
+func (prefixEncrypter) Decrypt(ciphertext string) (string, error) {
+	return strings.TrimPrefix(ciphertext, "enc:"), nil
+}
+
+type patient struct {
+	Node `neo4j:"Patient"`
+
+	Name string  `json:"name"`
+	SSN  string  `json:"ssn" neogo:"encrypt"`
+	Note *string `json:"note" neogo:"encrypt"`
+}
+
+func TestEncrypter(t *testing.T) {
+	ctx := context.Background()
+	note := "allergic"
+
+	t.Run("encrypts fields written through patterns", func(t *testing.T) {
+		m, params := newParamsMock(func(d *driver) { d.encrypter = prefixEncrypter{} })
+		p := patient{Name: "Ada", SSN: "123", Note: &note}
+		require.NoError(t, m.Exec().Create(db.Node(db.Qual(&p, "p"))).Run(ctx))
+		require.Equal(t, "Ada", params()["p_name"])
+		require.Equal(t, "enc:123", params()["p_ssn"])
+		require.Equal(t, "enc:allergic", params()["p_note"])
+		require.Equal(t, "123", p.SSN)
+	})
+
+	t.Run("writes nil encrypted fields as null", func(t *testing.T) {
+		m, params := newParamsMock(func(d *driver) { d.encrypter = prefixEncrypter{} })
+		p := struct {
+			Node `neo4j:"Patient"`
+
+			Name string  `json:"name"`
+			Note *string `json:"note" neogo:"encrypt,keepzero"`
+		}{Name: "Ada"}
+		require.NoError(t, m.Exec().Create(db.Node(db.Qual(&p, "p"))).Run(ctx))
+		require.Contains(t, params(), "p_note")
+		require.Nil(t, params()["p_note"])
+	})
+
+	t.Run("encrypts fields of merged maps", func(t *testing.T) {
+		m, params := newParamsMock(func(d *driver) { d.encrypter = prefixEncrypter{} })
+		var p patient
+		err := m.Exec().
+			Merge(
+				db.Node(db.Qual(&p, "p", db.Props{"name": db.Param("Ada")})),
+				db.Upsert(&p, patient{Name: "Ada", SSN: "123", Note: &note}),
+			).
+			Set(db.SetMerge(&p, map[string]any{"ssn": "456"})).
+			Run(ctx)
+		require.NoError(t, err)
+		for _, name := range []string{"v2", "v3"} {
+			props := params()[name].(map[string]any)
+			require.Equal(t, "Ada", props["name"])
+			require.Equal(t, "enc:123", props["ssn"])
+			require.Equal(t, "enc:allergic", props["note"])
+		}
+		require.Equal(t, "enc:456", params()["v4"].(map[string]any)["ssn"])
+	})
+
+	t.Run("encrypts fields of struct parameters", func(t *testing.T) {
+		r := &registry{encrypter: prefixEncrypter{}}
+		params, err := r.convertParams(context.Background(), map[string]any{
+			"p": patient{Name: "Ada", SSN: "123"},
+		}, false)
+		require.NoError(t, err)
+		props := params["p"].(map[string]any)
+		require.Equal(t, "Ada", props["name"])
+		require.Equal(t, "enc:123", props["ssn"])
+	})
+
+	t.Run("decrypts fields when reading", func(t *testing.T) {
+		r := &registry{encrypter: prefixEncrypter{}}
+		var p patient
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Patient"},
+			Props: map[string]any{
+				"name": "Ada",
+				"ssn":  "enc:123",
+				"note": "enc:allergic",
+			},
+		}, reflect.ValueOf(&p))
+		require.NoError(t, err)
+		require.Equal(t, "123", p.SSN)
+		require.Equal(t, "allergic", *p.Note)
+	})
+
+	t.Run("refuses to write plaintext without an encrypter", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{})
+		p := patient{Name: "Ada", SSN: "123"}
+		err := m.Exec().Create(db.Node(db.Qual(&p, "p"))).Run(ctx)
+		require.ErrorIs(t, err, ErrNoEncrypter)
+	})
+}
@@ -103,6 +103,21 @@ type (
 	//  }
 	Abstract = internal.Abstract
 
+	// TenantNode is a base type for nodes identified by their tenant and ID
+	// together, used in place of [Node]. Helpers identifying nodes, such as
+	// [MergeRelationship], match both properties.
+	//
+	//  type Invoice struct {
+	//   neogo.TenantNode `neo4j:"Invoice"`
+	//
+	//   Total int `json:"total"`
+	//  }
+	TenantNode = internal.TenantNode
+
+	// Keyed is implemented by nodes identified by several properties together,
+	// such as [TenantNode].
+	Keyed = internal.Keyed
+
 	// Relationship is a base type for all relationships.
 	//
 	// The neo4j tag is used to specify the type for the relationship.
@@ -114,6 +129,53 @@ type (
 	//  }
 	Relationship = internal.Relationship
 
+	// DeprecatedField describes a read or write of a field tagged with
+	// `neogo:"deprecated"`. See [WithDeprecatedFieldHandler].
+	DeprecatedField = internal.DeprecatedField
+
+	// BeforeSaver is implemented by entities which prepare themselves, i.e. by
+	// setting timestamps or validating their fields, before being written.
+	//
+	// BeforeSave is called with the context of the query once each time a
+	// query passing the entity to a CREATE, MERGE or SET clause is run, and
+	// the properties written from the entity are read again afterwards. The
+	// zero fields of created entities are written as null until then, so the
+	// fields the hook sets are written. Returning an error fails the query.
+	//
+	//  func (p *Person) BeforeSave(ctx context.Context) error {
+	//  	p.UpdatedAt = time.Now()
+	//  	return nil
+	//  }
+	BeforeSaver = internal.BeforeSaver
+
+	// FieldError describes a field of an entity failing one of the rules of
+	// its validate tag. Entities are validated when they are written, after
+	// BeforeSave:
+	//
+	//  type Person struct {
+	//  	neogo.Node `neo4j:"Person"`
+	//
+	//  	Name string `json:"name" validate:"required,max=255"`
+	//  	Role string `json:"role" validate:"oneof=admin member"`
+	//  }
+	FieldError = internal.FieldError
+
+	// ValidationErrors are the fields of an entity failing validation, which
+	// fail the query writing it.
+	//
+	//  var verrs neogo.ValidationErrors
+	//  if errors.As(err, &verrs) {
+	//  	for _, f := range verrs {
+	//  		fmt.Println(f.Property, f.Rule)
+	//  	}
+	//  }
+	ValidationErrors = internal.ValidationErrors
+
+	// BeforeDeleter is implemented by entities which are notified when a
+	// query passing them to a DELETE clause is run, with the context of the
+	// query. Returning an error fails the query.
+	BeforeDeleter = internal.BeforeDeleter
+
 	// Label is a used to specify a label for a node.
 	// This allows for multiple labels to be specified idiomatically.
 	//
@@ -0,0 +1,111 @@
+package neogo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// Enum is implemented by types whose values are one of a fixed set of names.
+// Enums of string kind are their names, whereas enums of integer kind are the
+// index of their name. Either way, enums are stored as their names, and read
+// from their names or indices, failing on unknown values.
+//
+//	type Status int
+//
+//	const (
+//		Draft Status = iota
+//		Published
+//		Archived
+//	)
+//
+//	func (Status) EnumValues() []string {
+//		return []string{"draft", "published", "archived"}
+//	}
+//
+// String fields may instead be restricted to a set of values with an enum
+// tag, i.e. `enum:"draft,published,archived"`, which are checked when they are
+// written and read.
+type Enum interface {
+	EnumValues() []string
+}
+
+var rEnum = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// converterOf returns the converter of values of type t: the converter
+// registered for it, or the converter of an [Enum] or nullable SQL type.
+func (r *registry) converterOf(t reflect.Type) (TypeConverter, bool) {
+	if conv, ok := r.converters[t]; ok {
+		return conv, true
+	}
+	if conv, ok := enumConverter(t); ok {
+		return conv, true
+	}
+	return sqlConverter(t)
+}
+
+// enumConverter returns the converter of t if it is an [Enum].
+func enumConverter(t reflect.Type) (TypeConverter, bool) {
+	if !t.Implements(rEnum) {
+		return TypeConverter{}, false
+	}
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return TypeConverter{}, false
+	}
+	isString := t.Kind() == reflect.String
+	names := reflect.Zero(t).Interface().(Enum).EnumValues()
+	invalid := func(v any) error {
+		return fmt.Errorf("invalid %s %v: must be one of %s", t.Name(), v, strings.Join(names, ", "))
+	}
+	indexOf := func(name string) int {
+		for i, n := range names {
+			if n == name {
+				return i
+			}
+		}
+		return -1
+	}
+	return TypeConverter{
+		Stored: emptyInterface,
+		ToDB: func(v any) (any, error) {
+			rv := reflect.ValueOf(v)
+			if isString {
+				if indexOf(rv.String()) < 0 {
+					return nil, invalid(fmt.Sprintf("%q", rv.String()))
+				}
+				return rv.String(), nil
+			}
+			i := rv.Convert(reflect.TypeOf(0)).Interface().(int)
+			if i < 0 || i >= len(names) {
+				return nil, invalid(i)
+			}
+			return names[i], nil
+		},
+		FromDB: func(stored any) (any, error) {
+			i := -1
+			if s, ok := stored.(string); ok {
+				if i = indexOf(s); i < 0 {
+					return nil, invalid(fmt.Sprintf("%q", s))
+				}
+				if isString {
+					return reflect.ValueOf(s).Convert(t).Interface(), nil
+				}
+			} else if n, err := cast.ToIntE(stored); err == nil && n >= 0 && n < len(names) {
+				i = n
+			}
+			if i < 0 {
+				return nil, invalid(stored)
+			}
+			if isString {
+				return reflect.ValueOf(names[i]).Convert(t).Interface(), nil
+			}
+			return reflect.ValueOf(i).Convert(t).Interface(), nil
+		},
+	}, true
+}
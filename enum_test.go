@@ -0,0 +1,88 @@
+package neogo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+)
+
+type postStatus int
+
+const (
+	statusDraft postStatus = iota
+	statusPublished
+)
+
+func (postStatus) EnumValues() []string { return []string{"draft", "published"} }
+
+type postVisibility string
+
+func (postVisibility) EnumValues() []string { return []string{"public", "private"} }
+
+type enumPost struct {
+	Node `neo4j:"Post"`
+
+	Title      string         `json:"title"`
+	Status     postStatus     `json:"status"`
+	Visibility postVisibility `json:"visibility"`
+	Kind       string         `json:"kind" enum:"article,note"`
+}
+
+func TestEnums(t *testing.T) {
+	r := &registry{}
+
+	t.Run("stores enums as their names", func(t *testing.T) {
+		params, err := r.convertParams(context.Background(), map[string]any{
+			"status":     statusPublished,
+			"visibility": postVisibility("private"),
+		}, false)
+		require.NoError(t, err)
+		assert.Equal(t, "published", params["status"])
+		assert.Equal(t, "private", params["visibility"])
+
+		_, err = r.convertParams(context.Background(), map[string]any{"status": postStatus(7)}, false)
+		assert.ErrorContains(t, err, "invalid postStatus 7: must be one of draft, published")
+		_, err = r.convertParams(context.Background(), map[string]any{"visibility": postVisibility("secret")}, false)
+		assert.ErrorContains(t, err, `invalid postVisibility "secret": must be one of public, private`)
+	})
+
+	t.Run("reads enums from their names or indices", func(t *testing.T) {
+		var p enumPost
+		require.NoError(t, r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Post"},
+			Props:  map[string]any{"title": "Hello", "status": "published", "visibility": "public", "kind": "note"},
+		}, reflect.ValueOf(&p)))
+		assert.Equal(t, statusPublished, p.Status)
+		assert.Equal(t, postVisibility("public"), p.Visibility)
+
+		var s postStatus
+		require.NoError(t, r.bindValue(context.Background(), int64(1), reflect.ValueOf(&s)))
+		assert.Equal(t, statusPublished, s)
+
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Post"},
+			Props:  map[string]any{"status": "deleted"},
+		}, reflect.ValueOf(&p))
+		assert.ErrorContains(t, err, `invalid postStatus "deleted"`)
+	})
+
+	t.Run("checks enum tags when writing and reading", func(t *testing.T) {
+		p := enumPost{Title: "Hello", Kind: "essay"}
+		_, err := (&session{}).newClient(internal.NewCypherClient()).
+			Create(db.Node(db.Qual(&p, "p"))).(baseRunner).GetRunner().Compile()
+		assert.ErrorContains(t, err, "enumPost.Kind (kind) failed enum=article,note")
+
+		err = r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Post"},
+			Props:  map[string]any{"kind": "essay"},
+		}, reflect.ValueOf(&p))
+		assert.ErrorContains(t, err, "enumPost.Kind (kind) failed enum=article,note")
+	})
+}
@@ -0,0 +1,65 @@
+package neogo
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns how long to wait before the attempt-th retry, starting at 1.
+type Backoff func(attempt int) time.Duration
+
+// ExponentialBackoff returns a [Backoff] doubling from base up to max, with
+// full jitter so concurrent retries spread out.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		wait := base
+		for i := 1; i < attempt && wait < max; i++ {
+			wait *= 2
+		}
+		wait = min(wait, max)
+		if wait <= 0 {
+			return 0
+		}
+		return rand.N(wait) + 1
+	}
+}
+
+// defaultEventuallyBackoff is the backoff of [EventuallyRead] when none is
+// given.
+var defaultEventuallyBackoff = ExponentialBackoff(50*time.Millisecond, time.Second)
+
+// EventuallyRead runs the query built by read with d until until reports that
+// its results hold, waiting between attempts as long as backoff says (or
+// [ExponentialBackoff] from 50ms to 1s if nil). It fails if a read fails, or
+// once ctx is done, so ctx should have a deadline. It is intended for
+// observing writes which have yet to be replicated to the followers serving
+// reads, without sleeping for a fixed time:
+//
+//	var p Person
+//	err := neogo.EventuallyRead(ctx, d, func(q neogo.Query) query.Runner {
+//		return q.Match(db.Node(db.Qual(&p, "p", db.Props{"id": "'1'"}))).Return(&p)
+//	}, func() bool { return p.Name == "Ada" }, nil)
+func EventuallyRead(ctx context.Context, d Driver, read Statement, until func() bool, backoff Backoff) error {
+	if backoff == nil {
+		backoff = defaultEventuallyBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		if err := read(d.Exec()).Run(ctx); err != nil {
+			return err
+		}
+		if until() {
+			return nil
+		}
+		timer := time.NewTimer(backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("condition not met after %d reads: %w", attempt, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+	"github.com/rlch/neogo/query"
+)
+
+func TestEventuallyRead(t *testing.T) {
+	var p tests.Person
+	read := func(q Query) query.Runner {
+		return q.Match(db.Node(db.Qual(&p, "p"))).Return(&p)
+	}
+	noWait := func(int) time.Duration { return 0 }
+
+	t.Run("reads until the condition holds", func(t *testing.T) {
+		d := NewMock()
+		for _, name := range []string{"", "", "Ada"} {
+			d.Bind(map[string]any{"p": map[string]any{"name": name}})
+		}
+		reads := 0
+		err := EventuallyRead(context.Background(), d, read, func() bool {
+			reads++
+			return p.Name == "Ada"
+		}, noWait)
+		require.NoError(t, err)
+		assert.Equal(t, 3, reads)
+	})
+
+	t.Run("fails once the context is done", func(t *testing.T) {
+		d := NewMock()
+		d.Bind(map[string]any{"p": map[string]any{"name": ""}})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := EventuallyRead(ctx, d, read, func() bool { return false }, noWait)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("backs off exponentially with jitter", func(t *testing.T) {
+		backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+		for attempt, max := range []time.Duration{10, 20, 40, 50, 50} {
+			wait := backoff(attempt + 1)
+			assert.Greater(t, wait, time.Duration(0))
+			assert.LessOrEqual(t, wait, max*time.Millisecond)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package neogo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// QueryArtifact describes a named query for review, as exported by
+// [ExportQueries].
+type QueryArtifact struct {
+	Name   string `json:"name"`
+	Cypher string `json:"cypher"`
+	// Parameters are the names of the parameters of the query, in sorted
+	// order. Their values are not exported, as they may be sensitive.
+	Parameters []string `json:"parameters"`
+	// Labels are the node labels touched by the query, in sorted order.
+	Labels []string `json:"labels"`
+	// AccessMode is the access mode inferred for the query, either read or
+	// write.
+	AccessMode string `json:"accessMode"`
+}
+
+// QueryExport is the named queries of a service, exported for review by
+// [ExportQueries].
+type QueryExport []QueryArtifact
+
+// ExportQueries compiles each of the named queries with the configuration of
+// d, without running them, so all the graph access of a service can be
+// reviewed, i.e. by writing the export to a file checked in alongside it:
+//
+//	export, err := neogo.ExportQueries(d, map[string]neogo.Statement{
+//		"FindPerson": func(q neogo.Query) query.Runner {
+//			var p Person
+//			return q.Match(db.Node(db.Qual(&p, "p", db.Props{"id": "$id"}))).Return(&p)
+//		},
+//	})
+//
+// Queries are exported in the order of their names.
+func ExportQueries(d Driver, queries map[string]Statement) (QueryExport, error) {
+	names := make([]string, 0, len(queries))
+	for name := range queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	export := make(QueryExport, 0, len(names))
+	for _, name := range names {
+		runner, ok := queries[name](d.Exec()).(baseRunner)
+		if !ok {
+			return nil, fmt.Errorf("cannot export query %q: unsupported runner", name)
+		}
+		cy, err := runner.GetRunner().Compile()
+		if err != nil {
+			return nil, fmt.Errorf("cannot export query %q: %w", name, err)
+		}
+		params := make([]string, 0, len(cy.Parameters))
+		for param := range cy.Parameters {
+			params = append(params, param)
+		}
+		sort.Strings(params)
+		mode := "read"
+		if cy.IsWrite {
+			mode = "write"
+		}
+		labels := cy.Labels
+		if labels == nil {
+			labels = []string{}
+		}
+		export = append(export, QueryArtifact{
+			Name:       name,
+			Cypher:     cy.Cypher,
+			Parameters: params,
+			Labels:     labels,
+			AccessMode: mode,
+		})
+	}
+	return export, nil
+}
+
+// JSON returns the export as indented JSON.
+func (e QueryExport) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// Markdown returns the export as a Markdown document, with a section per
+// query.
+func (e QueryExport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Queries\n")
+	list := func(values []string) string {
+		if len(values) == 0 {
+			return "none"
+		}
+		return "`" + strings.Join(values, "`, `") + "`"
+	}
+	for _, q := range e {
+		fmt.Fprintf(&b, "\n## %s\n\n", q.Name)
+		fmt.Fprintf(&b, "- Access mode: %s\n", q.AccessMode)
+		fmt.Fprintf(&b, "- Parameters: %s\n", list(q.Parameters))
+		fmt.Fprintf(&b, "- Labels: %s\n", list(q.Labels))
+		fmt.Fprintf(&b, "\n```cypher\n%s\n```\n", q.Cypher)
+	}
+	return b.String()
+}
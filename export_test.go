@@ -0,0 +1,53 @@
+package neogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+	"github.com/rlch/neogo/query"
+)
+
+func TestExportQueries(t *testing.T) {
+	d := NewMock()
+	export, err := ExportQueries(d, map[string]Statement{
+		"FindPerson": func(q Query) query.Runner {
+			var p tests.Person
+			return q.
+				Match(db.Node(db.Qual(&p, "p"))).
+				Where(db.Cond(&p.Name, "=", db.NamedParam("", "name"))).
+				Return(&p)
+		},
+		"CreatePerson": func(q Query) query.Runner {
+			p := tests.Person{Name: "Ada"}
+			return q.Create(db.Node(db.Qual(&p, "p")))
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, QueryExport{
+		{
+			Name:       "CreatePerson",
+			Cypher:     "CREATE (p:Person {name: $p_name})",
+			Parameters: []string{"p_name"},
+			Labels:     []string{"Person"},
+			AccessMode: "write",
+		},
+		{
+			Name:       "FindPerson",
+			Cypher:     "MATCH (p:Person)\nWHERE p.name = $name\nRETURN p",
+			Parameters: []string{"name"},
+			Labels:     []string{"Person"},
+			AccessMode: "read",
+		},
+	}, export)
+
+	md := export.Markdown()
+	require.Contains(t, md, "## CreatePerson\n\n- Access mode: write\n- Parameters: `p_name`\n- Labels: `Person`\n")
+	require.Contains(t, md, "```cypher\nMATCH (p:Person)\nWHERE p.name = $name\nRETURN p\n```\n")
+
+	js, err := export.JSON()
+	require.NoError(t, err)
+	require.Contains(t, string(js), `"accessMode": "read"`)
+}
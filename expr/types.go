@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/rlch/neogo"
+	"github.com/rlch/neogo/db"
 	"github.com/rlch/neogo/internal"
 	"github.com/rlch/neogo/query"
 )
@@ -222,6 +223,17 @@ func (e *Reader) Call(procedure string) *Yielder {
 	return newYielder(q)
 }
 
+func CallProcedure(procedure *internal.Procedure) *Querier {
+	e := empty()
+	q := e.buffer.CallProcedure(procedure)
+	return newQuerier(q)
+}
+
+func (e *Reader) CallProcedure(procedure *internal.Procedure) *Querier {
+	q := e.buffer.CallProcedure(procedure)
+	return newQuerier(q)
+}
+
 func Show(command string) *Yielder {
 	e := empty()
 	q := e.buffer.Show(command)
@@ -243,6 +255,15 @@ func Subquery(subquery func(c *Client) Runner) *Querier {
 	return newQuerier(q)
 }
 
+// Collect returns a COLLECT subquery, which collects the values returned by
+// subquery into the slice to. See [db.CollectSubquery].
+func Collect[T any](subquery func(c *Client) Runner, to *[]T) *internal.Variable {
+	return db.CollectSubquery(func(cc *internal.CypherClient) *internal.CypherRunner {
+		runner := subquery(newClient(cc))
+		return runner.getBuffer()
+	}, to)
+}
+
 func (e *Reader) Subquery(subquery func(c *Client) Runner) *Querier {
 	inSubquery := func(cc *internal.CypherClient) *internal.CypherRunner {
 		runner := subquery(newClient(cc))
@@ -298,6 +319,11 @@ func (e *Querier) Where(opts ...internal.WhereOption) *Querier {
 	return newQuerier(q)
 }
 
+func (e *Querier) Using(hints ...internal.Hint) *Querier {
+	q := e.buffer.Using(hints...)
+	return newQuerier(q)
+}
+
 func Create(pattern internal.Patterns) *Querier {
 	e := empty()
 	q := e.buffer.Create(pattern)
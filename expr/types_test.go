@@ -181,6 +181,32 @@ func ExampleSubquery() {
 	// RETURN p.name, numberOfConnections
 }
 
+func ExampleCollect() {
+	var (
+		p      tests.Person
+		m      tests.Movie
+		titles []string
+	)
+
+	Match(db.Node(db.Qual(&p, "p"))).
+		Return(
+			&p.Name,
+			db.Qual(Collect(func(c *Client) Runner {
+				return c.
+					Match(db.Node("p").To(db.Var(nil, db.Label("ACTED_IN")), db.Qual(&m, "m"))).
+					Return(&m.Title)
+			}, &titles), "titles"),
+		).
+		Print()
+
+	// Output:
+	// MATCH (p:Person)
+	// RETURN p.name, COLLECT {
+	//   MATCH (p)-[:ACTED_IN]->(m:Movie)
+	//   RETURN m.title
+	// } AS titles
+}
+
 func ExampleCall() {
 	var labels []string
 
@@ -0,0 +1,50 @@
+package neogo
+
+import (
+	"os"
+	"strings"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// FeatureFlags enable, by name, behaviours of neogo being rolled out gradually,
+// so they can be enabled in one environment before another. Flags which aren't
+// set are disabled.
+type FeatureFlags = internal.FeatureFlags
+
+// FeatureExistsSubqueries writes the patterns of WHERE conditions as EXISTS
+// subqueries, i.e. WHERE EXISTS { (n)-[:KNOWS]->(m) }, rather than as pattern
+// predicates, which are deprecated since Neo4J 5.
+const FeatureExistsSubqueries = internal.FeatureExistsSubqueries
+
+// WithFeatureFlags is an option for [New] that sets the feature flags
+// consulted when compiling queries. Flags are merged with those of previous
+// options, so later options take precedence.
+//
+//	neogo.WithFeatureFlags(neogo.FeatureFlagsFromEnv("NEOGO_FEATURES"))
+func WithFeatureFlags(flags FeatureFlags) Configurer {
+	return func(c *Config) {
+		if c.FeatureFlags == nil {
+			c.FeatureFlags = FeatureFlags{}
+		}
+		for name, enabled := range flags {
+			c.FeatureFlags[name] = enabled
+		}
+	}
+}
+
+// FeatureFlagsFromEnv returns the feature flags listed by the environment
+// variable key, separated by commas. Flags prefixed with - are disabled, i.e.
+// NEOGO_FEATURES=existsSubqueries,-otherFeature.
+func FeatureFlagsFromEnv(key string) FeatureFlags {
+	flags := FeatureFlags{}
+	for _, name := range strings.Split(os.Getenv(key), ",") {
+		name = strings.TrimSpace(name)
+		enabled := !strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		if name != "" {
+			flags[name] = enabled
+		}
+	}
+	return flags
+}
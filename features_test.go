@@ -0,0 +1,40 @@
+package neogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	t.Run("reads flags from the environment", func(t *testing.T) {
+		t.Setenv("NEOGO_FEATURES", "existsSubqueries, -parallelDecoding,,")
+		assert.Equal(t, FeatureFlags{
+			FeatureExistsSubqueries: true,
+			"parallelDecoding":      false,
+		}, FeatureFlagsFromEnv("NEOGO_FEATURES"))
+	})
+
+	t.Run("merges flags of options", func(t *testing.T) {
+		cfg := &Config{}
+		WithFeatureFlags(FeatureFlags{"a": true, "b": true})(cfg)
+		WithFeatureFlags(FeatureFlags{"b": false})(cfg)
+		assert.Equal(t, FeatureFlags{"a": true, "b": false}, cfg.FeatureFlags)
+	})
+
+	t.Run("consults flags when compiling", func(t *testing.T) {
+		var p, f tests.Person
+		s := &session{registry: registry{features: FeatureFlags{FeatureExistsSubqueries: true}}}
+		cy, err := s.newClient(internal.NewCypherClient()).
+			Match(db.Node(db.Qual(&p, "p"))).
+			Where(db.Node(&p).To(nil, db.Qual(&f, "f"))).
+			Return(&p).(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, "MATCH (p:Person)\nWHERE EXISTS { (p)-->(f:Person) }\nRETURN p", cy.Cypher)
+	})
+}
@@ -0,0 +1,76 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// ErrTooManyRows is returned by queries guarded with [WithRowGuard] which are
+// estimated to return more rows than allowed.
+var ErrTooManyRows = errors.New("query may return too many rows")
+
+// WithRowGuard configures Exec() to EXPLAIN the query before running it, and
+// fail with [ErrTooManyRows] without running it if the planner estimates it
+// returns more than maxRows rows. This protects endpoints from accidentally
+// unbounded list queries, at the cost of a round trip. Estimates are only as
+// good as the statistics of the database, so maxRows should leave headroom.
+// Entities are prepared only once the guard passes, and the transaction of
+// the EXPLAIN is reported to the transaction listener with
+// [TxEvent.Preflight] set.
+func WithRowGuard(maxRows int) func(ec *execConfig) {
+	return func(ec *execConfig) {
+		ec.maxRows = maxRows
+	}
+}
+
+// preflightKey marks the context of the transactions run before a query, so
+// they are reported to the transaction listener as preflights.
+type preflightKey struct{}
+
+// guardRows fails if the query of cy is guarded and estimated to return more
+// rows than allowed. The query is explained as it is built, before its
+// entities are prepared, stamped or stored, and in a transaction reported as a
+// preflight.
+func (c *runnerImpl) guardRows(ctx context.Context, cy *internal.CompiledCypher) error {
+	if c.execConfig.maxRows <= 0 {
+		return nil
+	}
+	explain := *cy
+	explain.Cypher = "EXPLAIN " + cy.Cypher
+	explain.SetActor("")
+	params, err := c.sendParams(ctx, &explain, true)
+	if err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, preflightKey{}, true)
+	plan, err := c.executeTransaction(ctx, &explain, func(ctx context.Context, tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, explain.Cypher, params)
+		if err != nil {
+			return nil, fmt.Errorf("cannot explain cypher: %w", err)
+		}
+		summary, err := result.Consume(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return newPlan(summary.Plan()), nil
+	})
+	if err != nil {
+		return err
+	}
+	return checkRowEstimate(plan.(*query.Plan), c.execConfig.maxRows)
+}
+
+// checkRowEstimate fails if plan is estimated to return more than maxRows
+// rows. Queries without a plan aren't estimated, so they pass.
+func checkRowEstimate(plan *query.Plan, maxRows int) error {
+	if plan == nil || plan.EstimatedRows <= float64(maxRows) {
+		return nil
+	}
+	return fmt.Errorf("%w: estimated %.0f rows, at most %d allowed", ErrTooManyRows, plan.EstimatedRows, maxRows)
+}
@@ -0,0 +1,66 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+	"github.com/rlch/neogo/query"
+)
+
+func TestRowGuard(t *testing.T) {
+	t.Run("fails plans estimated to return too many rows", func(t *testing.T) {
+		assert.NoError(t, checkRowEstimate(nil, 10))
+		assert.NoError(t, checkRowEstimate(&query.Plan{EstimatedRows: 10}, 10))
+		err := checkRowEstimate(&query.Plan{EstimatedRows: 1500}, 100)
+		require.ErrorIs(t, err, ErrTooManyRows)
+		assert.ErrorContains(t, err, "estimated 1500 rows, at most 100 allowed")
+	})
+
+	t.Run("explains guarded queries before running them", func(t *testing.T) {
+		d := NewMock()
+		// The first transaction explains the query, and the second runs it.
+		d.Bind(map[string]any{})
+		d.Bind(map[string]any{"p": tests.Person{Name: "Ada"}})
+		var p tests.Person
+		err := d.Exec(WithRowGuard(100)).
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "Ada", p.Name)
+	})
+
+	t.Run("explains queries before preparing their entities", func(t *testing.T) {
+		m := NewMock()
+		impl := m.(*mockDriverImpl)
+		var sent []map[string]any
+		impl.paramTransformers = append(impl.paramTransformers, ParamTransformerFunc(
+			func(_ context.Context, p map[string]any) (map[string]any, error) {
+				sent = append(sent, p)
+				return p, nil
+			},
+		))
+		var preflights []bool
+		impl.txListener = TxListenerFunc(func(_ context.Context, e TxEvent) {
+			preflights = append(preflights, e.Preflight)
+		})
+		m.Bind(map[string]any{})
+		m.Bind(map[string]any{})
+		p := hookedPerson{Name: "Ada"}
+		err := m.Exec(WithRowGuard(100)).
+			Create(db.Node(db.Qual(&p, "p"))).
+			Run(context.Background())
+		require.NoError(t, err)
+		require.Len(t, sent, 2)
+		assert.Nil(t, sent[0]["p_slug"])
+		assert.Equal(t, "ada", sent[1]["p_slug"])
+		assert.Equal(t, 1, p.saves)
+		// Each transaction begins and commits.
+		assert.Equal(t, []bool{true, true, false, false}, preflights)
+	})
+}
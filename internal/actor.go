@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"maps"
+	"reflect"
+)
+
+// ActorField is a field of an entity written by a query recording the actor
+// who created or last updated it, which is only known once the query is run.
+// Properties written from the field are parameterized by the ActorField
+// itself until [CompiledCypher.SetActor] replaces it.
+type ActorField struct {
+	// Field is the string field of the entity.
+	Field reflect.Value
+}
+
+// SetActorFields enables actor fields. See [CompiledCypher.SetActor].
+func (s *Scope) SetActorFields(enabled bool) {
+	s.actorFieldsEnabled = enabled
+}
+
+// stampActor records the actor fields of the entity identifier refers to, if
+// actor fields are enabled. String fields named CreatedBy, or tagged with
+// `neogo:"createdBy"`, are recorded if created is true and they are empty;
+// fields named UpdatedBy, or tagged with `neogo:"updatedBy"`, are always
+// recorded.
+func (cy *cypher) stampActor(identifier any, created bool) {
+	if !cy.actorFieldsEnabled {
+		return
+	}
+	entity := lifecycleEntity(identifier)
+	if entity == nil {
+		return
+	}
+	rv := reflect.ValueOf(entity).Elem()
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	var walk func(st reflect.Value)
+	walk = func(st reflect.Value) {
+		t := st.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fv := st.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				walk(fv)
+				continue
+			}
+			if !f.IsExported() || f.Type.Kind() != reflect.String {
+				continue
+			}
+			switch {
+			case f.Name == "UpdatedBy" || hasNeogoOption(f, "updatedBy"):
+				cy.addActorField(fv)
+			case f.Name == "CreatedBy" || hasNeogoOption(f, "createdBy"):
+				if created && fv.String() == "" {
+					cy.addActorField(fv)
+				}
+			}
+		}
+	}
+	walk(rv)
+}
+
+func (s *Scope) addActorField(field reflect.Value) {
+	if s.actorField(field) == nil {
+		s.actorFields = append(s.actorFields, &ActorField{Field: field})
+	}
+}
+
+// actorField returns the actor field recorded for field, or nil.
+func (s *Scope) actorField(field reflect.Value) *ActorField {
+	if !field.CanAddr() {
+		return nil
+	}
+	for _, f := range s.actorFields {
+		if f.Field.Addr().Pointer() == field.Addr().Pointer() && f.Field.Type() == field.Type() {
+			return f
+		}
+	}
+	return nil
+}
+
+// SetActor sets the actor fields written by the query to actor, along with the
+// parameters they are written through. If actor is empty, the fields are left
+// as they are and the parameters of empty fields are null.
+func (cy *CompiledCypher) SetActor(actor string) {
+	if len(cy.ActorFields) == 0 {
+		return
+	}
+	if actor != "" {
+		for _, f := range cy.ActorFields {
+			f.Field.SetString(actor)
+		}
+	}
+	params := maps.Clone(cy.Parameters)
+	for name, v := range params {
+		f, ok := v.(*ActorField)
+		if !ok {
+			continue
+		}
+		if s := f.Field.String(); s != "" {
+			params[name] = s
+		} else {
+			params[name] = nil
+		}
+	}
+	cy.Parameters = params
+}
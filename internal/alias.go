@@ -0,0 +1,32 @@
+package internal
+
+// SetPropertyAliases sets the keys properties are renamed to, by node label
+// (or relationship type) and old key, so the fields of entities bound in
+// patterns are written under the same keys as entities passed as parameters.
+func (s *Scope) SetPropertyAliases(aliases map[string]map[string]string) {
+	s.propertyAliases = aliases
+}
+
+// aliasesOf returns the new keys of the properties of the node or
+// relationship identifier, by old key.
+func (s *Scope) aliasesOf(identifier any) map[string]string {
+	if len(s.propertyAliases) == 0 {
+		return nil
+	}
+	labels := ExtractNodeLabels(identifier)
+	if labels == nil {
+		if typ := ExtractRelationshipType(identifier); typ != "" {
+			labels = []string{typ}
+		}
+	}
+	var aliases map[string]string
+	for _, label := range labels {
+		for oldKey, newKey := range s.propertyAliases[label] {
+			if aliases == nil {
+				aliases = map[string]string{}
+			}
+			aliases[oldKey] = newKey
+		}
+	}
+	return aliases
+}
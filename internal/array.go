@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	neo4jTypesPath = "github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// isArrayProperty reports whether v is a slice or array to be stored as a
+// Neo4J array. Byte slices are stored as byte arrays and are excluded.
+func isArrayProperty(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) &&
+		t.Elem().Kind() != reflect.Uint8
+}
+
+// ArrayProperty converts v, the value of the property name, to a list whose
+// elements are all of the same type and supported by Neo4J arrays (booleans,
+// integers, floats, strings, temporal and spatial values). Elements of named,
+// sized or unsigned types are converted to their canonical type, i.e. int64.
+func ArrayProperty(name string, v reflect.Value) ([]any, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return nil, nil
+	}
+	out := make([]any, v.Len())
+	var firstKind string
+	for i := 0; i < v.Len(); i++ {
+		e, kind, err := arrayElement(v.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("property %q: element %d: %w", name, i, err)
+		}
+		if i == 0 {
+			firstKind = kind
+		} else if kind != firstKind {
+			return nil, fmt.Errorf(
+				"property %q: arrays must be homogeneous, element 0 is %s but element %d is %s",
+				name, firstKind, i, kind,
+			)
+		}
+		out[i] = e
+	}
+	return out, nil
+}
+
+func arrayElement(e reflect.Value) (any, string, error) {
+	for e.Kind() == reflect.Ptr || e.Kind() == reflect.Interface {
+		if e.IsNil() {
+			return nil, "", fmt.Errorf("null elements are not supported in arrays")
+		}
+		e = e.Elem()
+	}
+	switch e.Kind() {
+	case reflect.Bool:
+		return e.Bool(), "boolean", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return e.Int(), "integer", nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := e.Uint()
+		if u > math.MaxInt64 {
+			return nil, "", fmt.Errorf("%d overflows a Neo4J integer", u)
+		}
+		return int64(u), "integer", nil
+	case reflect.Float32, reflect.Float64:
+		return e.Float(), "float", nil
+	case reflect.String:
+		return e.String(), "string", nil
+	case reflect.Struct:
+		t := e.Type()
+		if IsDBType(t) {
+			return e.Interface(), t.String(), nil
+		}
+	}
+	return nil, "", fmt.Errorf("unsupported array element type %s", e.Type())
+}
+
+// IsDBType reports whether t is a temporal or spatial type which the Neo4J
+// driver accepts as-is, i.e. time.Time or neo4j.Point2D.
+func IsDBType(t reflect.Type) bool {
+	return t == timeType || t.PkgPath() == neo4jTypesPath
+}
+
+// RestoreDBTypes sets the temporal and spatial fields of the struct v within
+// props, its JSON representation, to their original values, as the driver
+// cannot accept their JSON representations. Time fields tagged with a temporal
+// type are converted to it; see [TemporalValue].
+func RestoreDBTypes(v reflect.Value, props map[string]any) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, ok := extractJSONFieldName(f)
+		if !ok {
+			if f.Anonymous {
+				RestoreDBTypes(v.Field(i), props)
+			}
+			continue
+		}
+		if _, ok := props[name]; !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if temporal, ok := TemporalValue(f, fv); ok {
+			props[name] = temporal
+			continue
+		}
+		for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() != reflect.Ptr && IsDBType(fv.Type()) {
+			props[name] = fv.Interface()
+		}
+	}
+}
+
+// ValidateArrayProperties checks the array properties of the struct v (or
+// slice of structs) using [ArrayProperty]. Only nodes and relationships are
+// validated, as other structs need not be stored as properties.
+func ValidateArrayProperties(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := ValidateArrayProperties(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		if !reflect.PointerTo(t).Implements(nodeType) && !reflect.PointerTo(t).Implements(relationshipType) {
+			return nil
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, ok := extractJSONFieldName(f)
+			if !ok {
+				if f.Anonymous {
+					if err := ValidateArrayProperties(v.Field(i)); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !f.IsExported() || !isArrayProperty(f.Type) {
+				continue
+			}
+			if _, ok := nestedFieldOf(f); ok {
+				continue
+			}
+			if _, err := ArrayProperty(name, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type status string
+
+type tagged struct {
+	Node   `neo4j:"Tagged"`
+	Tags   []any    `json:"tags"`
+	Status []status `json:"status"`
+	Sizes  []uint8  `json:"sizes"`
+}
+
+func TestArrayProperty(t *testing.T) {
+	t.Run("converts elements to canonical types", func(t *testing.T) {
+		arr, err := ArrayProperty("xs", reflect.ValueOf([]uint16{1, 2}))
+		require.NoError(t, err)
+		require.Equal(t, []any{int64(1), int64(2)}, arr)
+
+		arr, err = ArrayProperty("xs", reflect.ValueOf([]status{"a", "b"}))
+		require.NoError(t, err)
+		require.Equal(t, []any{"a", "b"}, arr)
+	})
+
+	t.Run("accepts homogeneous []any", func(t *testing.T) {
+		now := time.Now()
+		arr, err := ArrayProperty("xs", reflect.ValueOf([]any{now, now}))
+		require.NoError(t, err)
+		require.Equal(t, []any{now, now}, arr)
+	})
+
+	t.Run("nil slice", func(t *testing.T) {
+		arr, err := ArrayProperty("xs", reflect.ValueOf([]string(nil)))
+		require.NoError(t, err)
+		require.Nil(t, arr)
+	})
+
+	t.Run("errors on heterogeneous elements", func(t *testing.T) {
+		_, err := ArrayProperty("xs", reflect.ValueOf([]any{1, "2"}))
+		require.EqualError(t, err, `property "xs": arrays must be homogeneous, element 0 is integer but element 1 is string`)
+	})
+
+	t.Run("errors on null elements", func(t *testing.T) {
+		_, err := ArrayProperty("xs", reflect.ValueOf([]any{1, nil}))
+		require.EqualError(t, err, `property "xs": element 1: null elements are not supported in arrays`)
+	})
+
+	t.Run("errors on unsupported elements", func(t *testing.T) {
+		_, err := ArrayProperty("xs", reflect.ValueOf([]any{map[string]any{}}))
+		require.EqualError(t, err, `property "xs": element 0: unsupported array element type map[string]interface {}`)
+	})
+}
+
+func TestValidateArrayProperties(t *testing.T) {
+	t.Run("validates nodes", func(t *testing.T) {
+		err := ValidateArrayProperties(reflect.ValueOf([]*tagged{{Tags: []any{true, 1.5}}}))
+		require.EqualError(t, err, `property "tags": arrays must be homogeneous, element 0 is boolean but element 1 is float`)
+	})
+
+	t.Run("ignores structs which are not nodes or relationships", func(t *testing.T) {
+		row := struct {
+			Tags []any `json:"tags"`
+		}{Tags: []any{true, 1.5}}
+		require.NoError(t, ValidateArrayProperties(reflect.ValueOf(row)))
+	})
+
+	t.Run("errors when injected into patterns", func(t *testing.T) {
+		_, err := NewCypherClient().
+			Create(NewNode(&tagged{Tags: []any{1, "a"}})).
+			Compile()
+		require.Error(t, err)
+	})
+
+	t.Run("injects converted arrays into patterns", func(t *testing.T) {
+		cy, err := NewCypherClient().
+			Create(NewNode(&tagged{Status: []status{"active"}, Sizes: []uint8{1}})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"tagged_status": []any{"active"},
+			"tagged_sizes":  []uint8{1},
+		}, cy.Parameters)
+	})
+}
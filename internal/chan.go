@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+)
+
+var errInvalidChan = errors.New("Chan must be given a channel which can be sent to")
+
+// Chan is an identifier for a channel, which is sent each row bound to its
+// element type as the result of a query is read.
+type Chan struct {
+	ch   reflect.Value
+	elem any
+}
+
+// NewChan returns a [Chan] identifier for ch, which must be a channel that can
+// be sent to.
+func NewChan(ch any) *Chan {
+	c := &Chan{ch: reflect.ValueOf(ch)}
+	if c.ch.Kind() == reflect.Chan && c.ch.Type().ChanDir()&reflect.SendDir != 0 {
+		c.elem = reflect.New(c.ch.Type().Elem()).Interface()
+	}
+	return c
+}
+
+// registerChan returns the identifier rows sent to c are bound to.
+func (s *Scope) registerChan(c *Chan) any {
+	if c.elem == nil {
+		panic(errInvalidChan)
+	}
+	// Identifiers of the same channel must be bound to the same value.
+	for elem, ch := range s.channels {
+		if ch.Pointer() == c.ch.Pointer() {
+			c.elem = elem.Interface()
+			return c.elem
+		}
+	}
+	s.channels[reflect.ValueOf(c.elem)] = c.ch
+	return c.elem
+}
+
+// channelBindings returns the channels of the bindings which are sent rows,
+// by name.
+func (s *Scope) channelBindings() map[string]reflect.Value {
+	var out map[string]reflect.Value
+	for name, binding := range s.bindings {
+		ch, ok := s.channels[binding]
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = map[string]reflect.Value{}
+		}
+		out[name] = ch
+	}
+	return out
+}
+
+// CloseChannels closes the channels registered by [Chan] identifiers, once the
+// query is no longer sending rows.
+func (s *Scope) CloseChannels() {
+	for elem, ch := range s.channels {
+		ch.Close()
+		delete(s.channels, elem)
+	}
+}
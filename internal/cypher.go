@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"runtime/debug"
 	"sort"
 	"strings"
@@ -12,6 +13,9 @@ import (
 type cypher struct {
 	*Scope
 	*strings.Builder
+	// hintsAt is the length of the query after the last MATCH clause or hint,
+	// which is the only place hints can be written, or -1 if there is none.
+	hintsAt int
 }
 
 type CompiledCypher struct {
@@ -19,12 +23,26 @@ type CompiledCypher struct {
 	Parameters map[string]any
 	Bindings   map[string]reflect.Value
 	IsWrite    bool
+	// Channels are the channels sent each row bound to the binding of the same
+	// name.
+	Channels map[string]reflect.Value
+	// DeprecatedWrites are the deprecated fields written by the query.
+	DeprecatedWrites []DeprecatedField
+	// Labels are the node labels written in the query, in sorted order.
+	Labels []string
+	// ActorFields are the actor fields of the entities written by the query.
+	ActorFields []*ActorField
+	// Hooks are the lifecycle hooks of the entities written and deleted by
+	// the query. See [CompiledCypher.RunHooks].
+	Hooks      []Hook
+	refreshers map[string]func() (any, error)
 }
 
 func newCypher() *cypher {
 	return &cypher{
 		Scope:   newScope(),
 		Builder: &strings.Builder{},
+		hintsAt: -1,
 	}
 }
 
@@ -42,8 +60,66 @@ var (
 	errInvalidPropExpr        = errors.New("invalid property expression. Property expressions must be strings or an identifier")
 	errSubqueryImportAlias    = errors.New("aliasing or expressions are not supported in importing WITH clauses")
 	errUnresolvedProps        = errors.New("resolving from multiple properties is not allowed")
+	errEmptyLabel             = errors.New("labels cannot be empty")
+	errIndexHintVariables     = errors.New("index hints must reference properties of a single variable")
+	errHintWithoutMatch       = errors.New("hints must directly follow a MATCH or OPTIONAL MATCH clause")
+	errEmptyProcedure         = errors.New("procedure name cannot be empty")
+	errFragmentArgs           = errors.New("number of arguments does not match the placeholders of the expression")
 )
 
+var unquotedNameRe = regexp.MustCompile(`^[\p{L}_][\p{L}\p{M}\p{Nd}_]*$`)
+
+// EscapeName backtick-quotes a label, relationship type or property name if it
+// cannot be written as-is into a query, i.e. it contains characters other
+// than letters (of any script), digits and underscores, or starts with a digit.
+// Backticks within names are always doubled, so names cannot break out of their
+// quotes.
+//
+// Reserved words such as IN or ORDER are valid labels, relationship types and
+// property names, so they are not quoted.
+func EscapeName(name string) string {
+	if unquotedNameRe.MatchString(name) {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// escapeName escapes a label or relationship type so that it can be safely
+// written into a query, as labels cannot be parameterized.
+func escapeName(name string) string {
+	if name == "" {
+		panic(errEmptyLabel)
+	}
+	return EscapeName(name)
+}
+
+// splitProperty splits a property expression, i.e. n.name, on the dots which
+// are not within backticks.
+func splitProperty(expr string) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+	for i, r := range expr {
+		switch {
+		case r == '`':
+			quoted = !quoted
+		case r == '.' && !quoted:
+			parts = append(parts, expr[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+func (cy *cypher) writeLabels(labels []string) {
+	for _, label := range labels {
+		cy.addLabel(label)
+		cy.WriteString(":" + escapeName(label))
+	}
+}
+
 func (s *cypher) catch(op func()) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -85,7 +161,10 @@ func (cy *cypher) writeNode(m *member) {
 				_, _ = fmt.Fprintf(cy, ":%s", m.variable.Pattern)
 			} else if nodeLabels != nil {
 				padProps = true
-				_, _ = fmt.Fprintf(cy, ":%s", strings.Join(nodeLabels, ":"))
+				cy.writeLabels(nodeLabels)
+				if cy.namespace != "" {
+					cy.WriteString(":" + escapeName(cy.namespace))
+				}
 			}
 			var resolvedProps int
 			if m.variable != nil {
@@ -149,7 +228,7 @@ func (cy *cypher) writeRelationship(m *member, rs *relationshipPattern) {
 			if m.variable != nil && m.variable.Pattern != "" {
 				inner = ":" + string(m.variable.Pattern)
 			} else if label != "" {
-				inner = ":" + label
+				inner = ":" + escapeName(label)
 			}
 			if m.expr != "" {
 				inner = m.expr + inner
@@ -214,12 +293,20 @@ func (cy *cypher) writeProps(props Props) {
 	}, len(props))
 	i := 0
 	for k := range props {
-		name := cy.propertyIdentifier(nil)(k)
-		accessors := strings.Split(name, ".")
-		if len(accessors) == 2 {
-			name = accessors[1]
-		} else if len(accessors) > 2 || name == "" {
-			panic(errInvalidPropExpr)
+		var name string
+		if key, ok := k.(propertyKey); ok {
+			name = escapeName(string(key))
+		} else {
+			name = cy.propertyIdentifier(nil)(k)
+			accessors := splitProperty(name)
+			if len(accessors) == 2 {
+				// Properties of variables are already escaped.
+				name = accessors[1]
+			} else if len(accessors) > 2 || name == "" {
+				panic(errInvalidPropExpr)
+			} else {
+				name = EscapeName(name)
+			}
 		}
 		keys[i] = struct {
 			Key  string
@@ -260,7 +347,11 @@ func (cy *cypher) writeCondition(c *Condition, parseKey, parseValue func(any) st
 				prevBuilder := cy.Builder
 				cy.Builder = &strings.Builder{}
 				cy.writePattern(c.Path.nodePattern())
-				s += cy.String()
+				if cy.FeatureEnabled(FeatureExistsSubqueries) {
+					s += "EXISTS { " + cy.String() + " }"
+				} else {
+					s += cy.String()
+				}
 				cy.Builder = prevBuilder
 			} else if n := len(c.Xor); n > 0 {
 				for i, cond := range c.Xor {
@@ -320,6 +411,62 @@ func (cy *cypher) writePattern(pattern *nodePattern) {
 	})
 }
 
+// beforeSave records the hooks preparing the entities of pattern before their
+// properties are written. Created entities are given their defaults and
+// stamped when the query is run; merged entities aren't, as their properties
+// are matched against.
+func (cy *cypher) beforeSave(pattern *nodePattern, created bool) {
+	hook := Hook{created: created}
+	if created {
+		hook.defaults = true
+		hook.clock = cy.clock
+	}
+	save := func(identifier any) {
+		if !cy.writesEntity(identifier) {
+			return
+		}
+		if created {
+			cy.stampActor(identifier, true)
+		}
+		cy.addSaveHook(identifier, hook)
+	}
+	for {
+		save(pattern.data)
+		rs := pattern.relationship
+		if rs == nil {
+			return
+		}
+		save(rs.data)
+		next := pattern.next()
+		if next == pattern {
+			return
+		}
+		pattern = next
+	}
+}
+
+// writesEntity reports whether the entity identifier refers to is written by
+// its pattern. Entities bound by earlier clauses, or given explicit
+// properties, only identify what is written.
+func (cy *cypher) writesEntity(identifier any) bool {
+	switch v := identifier.(type) {
+	case *Variable:
+		if v.Props != nil || v.PropsExpr != "" {
+			return false
+		}
+	case Variable:
+		if v.Props != nil || v.PropsExpr != "" {
+			return false
+		}
+	}
+	entity := lifecycleEntity(identifier)
+	if entity == nil {
+		return false
+	}
+	_, bound := cy.names[reflect.ValueOf(entity)]
+	return !bound
+}
+
 func (cy *cypher) writeReadingClause(patterns []*nodePattern, optional bool) {
 	clause := "MATCH"
 	if optional {
@@ -329,6 +476,68 @@ func (cy *cypher) writeReadingClause(patterns []*nodePattern, optional bool) {
 		pattern := patterns[i]
 		cy.writePattern(pattern)
 	})
+	cy.hintsAt = cy.Len()
+}
+
+// hint ::= "USING" "INDEX" nodeVariable ":" labelName "(" propertyKeyName { "," propertyKeyName } ")"
+//
+//	| "USING" "SCAN" nodeVariable ":" labelName
+//	| "USING" "JOIN" "ON" nodeVariable { "," nodeVariable }
+func (cy *cypher) writeUsingClause(hints ...Hint) {
+	cy.catch(func() {
+		if cy.Len() != cy.hintsAt {
+			panic(errHintWithoutMatch)
+		}
+		for _, hint := range hints {
+			cy.WriteString("USING " + string(hint.Kind) + " ")
+			switch hint.Kind {
+			case HintIndex:
+				var (
+					name  string
+					props = make([]string, len(hint.Identifiers))
+				)
+				for i, identifier := range hint.Identifiers {
+					accessors := splitProperty(cy.propertyIdentifier(nil)(identifier))
+					if len(accessors) != 2 || (name != "" && accessors[0] != name) {
+						panic(errIndexHintVariables)
+					}
+					name = accessors[0]
+					props[i] = accessors[1]
+				}
+				_, _ = fmt.Fprintf(cy, "%s:%s(%s)", name, cy.labelOf(name), strings.Join(props, ", "))
+			case HintScan:
+				name := cy.propertyIdentifier(nil)(hint.Identifiers[0])
+				_, _ = fmt.Fprintf(cy, "%s:%s", name, cy.labelOf(name))
+			case HintJoin:
+				names := make([]string, len(hint.Identifiers))
+				for i, identifier := range hint.Identifiers {
+					names[i] = cy.propertyIdentifier(nil)(identifier)
+				}
+				cy.WriteString(strings.Join(names, ", "))
+			}
+			cy.newline()
+		}
+		cy.hintsAt = cy.Len()
+	})
+}
+
+// labelOf returns the label (or type) of the node (or relationship) bound to
+// name, which is used to resolve index hints. Nodes resolve to the namespace
+// label if one is set, as it is the label shared by every node the pattern
+// can match.
+func (cy *cypher) labelOf(name string) string {
+	if v, ok := cy.bindings[name]; ok && v.IsValid() && v.CanInterface() {
+		if labels := ExtractNodeLabels(v.Interface()); len(labels) > 0 {
+			if cy.namespace != "" {
+				return escapeName(cy.namespace)
+			}
+			return escapeName(labels[0])
+		}
+		if typ := ExtractRelationshipType(v.Interface()); typ != "" {
+			return escapeName(typ)
+		}
+	}
+	panic(fmt.Errorf("cannot resolve the label of %q for hint", name))
 }
 
 func (cy *cypher) writeUseClause(graphExpr string) {
@@ -371,7 +580,8 @@ func (cy *cypher) writeCreateClause(
 	nodes []*nodePattern,
 ) {
 	cy.writeMultilineQuery("CREATE", len(nodes), func(i int) {
-		cy.writePattern(nodes[i])
+		cy.beforeSave(nodes[i], true)
+		cy.writePatterns(func() { cy.writePattern(nodes[i]) })
 	})
 }
 
@@ -384,8 +594,9 @@ func (cy *cypher) writeMergeClause(
 		opt.configureMerge(merge)
 	}
 	cy.catch(func() {
+		cy.beforeSave(node, false)
 		cy.WriteString("MERGE ")
-		cy.writePattern(node)
+		cy.writePatterns(func() { cy.writePattern(node) })
 		cy.newline()
 
 		if merge.OnCreate != nil {
@@ -408,6 +619,7 @@ func (cy *cypher) writeDeleteClause(detach bool, propIdentifiers ...any) {
 		cy.WriteString("DETACH ")
 	}
 	cy.writeSinglelineQuery("DELETE", len(propIdentifiers), func(i int) {
+		cy.addDeleteHook(propIdentifiers[i])
 		cy.WriteString(cy.propertyIdentifier(nil)(propIdentifiers[i]))
 	})
 	cy.newline()
@@ -556,9 +768,13 @@ func (cy *cypher) writeProjectionBodyClause(clause string, parent *Scope, vars .
 					cy.WriteString("DISTINCT ")
 				}
 			}
-			cy.WriteString(m.expr)
-			if m.alias != "" {
-				_, _ = fmt.Fprintf(cy, " AS %s", m.alias)
+			if projection, ok := cy.projection(m); ok && !isWith && m.alias == "" {
+				_, _ = fmt.Fprintf(cy, "%s AS %s", projection, m.expr)
+			} else {
+				cy.WriteString(m.expr)
+				if m.alias != "" {
+					_, _ = fmt.Fprintf(cy, " AS %s", m.alias)
+				}
 			}
 		}
 		cy.newline()
@@ -621,25 +837,72 @@ func (cy *cypher) writeSetClause(items ...SetItem) {
 		prop := cy.propertyIdentifier(nil)(item.PropIdentifier)
 		cy.WriteString(prop)
 		if len(item.Labels) > 0 {
-			cy.WriteString(":" + strings.Join(item.Labels, ":"))
+			cy.writeLabels(item.Labels)
 			return
 		}
+		value := item.ValIdentifier
+		if u, ok := value.(*UpsertProps); ok {
+			cy.addSaveHook(u.Entity, Hook{created: u.Created})
+			value = cy.upsertProps(u)
+		} else {
+			cy.stampActor(value, false)
+			cy.addSaveHook(value, Hook{defaults: !item.Merge, clock: cy.clock})
+		}
 		if item.Merge {
 			cy.WriteString(" += ")
+			value = mergeProps(item.PropIdentifier, value)
 		} else {
 			cy.WriteString(" = ")
 		}
-		cy.WriteString(cy.valueIdentifier(item.ValIdentifier))
+		cy.WriteString(cy.valueIdentifier(value))
 	})
 }
 
+// mergeProps encodes a map of properties merged into an entity with SET +=,
+// directly or as a parameter, as the properties of the entity's type, so
+// nested and renamed fields are written as they are for structs. Parameters
+// are encoded again when they are reread.
+func mergeProps(identifier, value any) any {
+	t := reflect.TypeOf(identifier)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return value
+	}
+	props, ok := value.(map[string]any)
+	param, isParam := value.(Param)
+	if isParam && param.Value != nil {
+		props, ok = (*param.Value).(map[string]any)
+	}
+	if !ok {
+		return value
+	}
+	encoded, err := encodeProps(t.Elem(), props)
+	if err != nil {
+		panic(fmt.Errorf("cannot encode properties merged into %s: %w", t.Elem(), err))
+	}
+	if !isParam {
+		return encoded
+	}
+	var v any = encoded
+	param.Value = &v
+	if refresh := param.refresh; refresh != nil {
+		param.refresh = func() (any, error) {
+			v, err := refresh()
+			if props, ok := v.(map[string]any); ok && err == nil {
+				return encodeProps(t.Elem(), props)
+			}
+			return v, err
+		}
+	}
+	return param
+}
+
 func (cy *cypher) writeRemoveClause(items ...RemoveItem) {
 	cy.writeMultilineQuery("REMOVE", len(items), func(i int) {
 		item := items[i]
 		prop := cy.propertyIdentifier(nil)(item.PropIdentifier)
 		cy.WriteString(prop)
 		if len(item.Labels) > 0 {
-			cy.WriteString(":" + strings.Join(item.Labels, ":"))
+			cy.writeLabels(item.Labels)
 			return
 		}
 	})
@@ -651,6 +914,7 @@ func (cy *cypher) writeForEachClause(identifier, elementsExpr any, do func(c *Cy
 		value := cy.valueIdentifier(elementsExpr)
 
 		foreach := newCypher()
+		foreach.paramNamer = cy.paramNamer
 		m := foreach.register(identifier, false, nil)
 		_, _ = fmt.Fprintf(cy, "%s IN %s | ", m.expr, value)
 
@@ -674,6 +938,26 @@ func (cy *cypher) writeCallClause(procedure string) {
 	cy.newline()
 }
 
+func (cy *cypher) writeCallProcedureClause(procedure *Procedure) {
+	cy.catch(func() {
+		if procedure.Name == "" {
+			panic(errEmptyProcedure)
+		}
+		cy.WriteString("CALL " + procedure.Name + "(")
+		for i, arg := range procedure.Args {
+			if i > 0 {
+				cy.WriteString(", ")
+			}
+			cy.WriteString(cy.valueIdentifier(arg))
+		}
+		cy.WriteString(")")
+		cy.newline()
+	})
+	if len(procedure.Yields) > 0 {
+		cy.writeYieldClause(procedure.Yields...)
+	}
+}
+
 func (cy *cypher) writeShowClause(procedure string) {
 	cy.WriteString("SHOW " + procedure)
 	cy.newline()
@@ -152,6 +152,12 @@ func (c *CypherReader) Call(procedure string) *CypherYielder {
 	return newCypherYielder(c.cypher)
 }
 
+func (c *CypherReader) CallProcedure(procedure *Procedure) *CypherQuerier {
+	c.writeCallProcedureClause(procedure)
+	c.isWrite = true
+	return newCypherQuerier(c.cypher)
+}
+
 func (c *CypherReader) Show(command string) *CypherYielder {
 	c.writeShowClause(command)
 	return newCypherYielder(c.cypher)
@@ -186,6 +192,18 @@ func (c *CypherQuerier) Where(opts ...WhereOption) *CypherQuerier {
 	return newCypherQuerier(c.cypher)
 }
 
+func (c *CypherQuerier) Using(hints ...Hint) *CypherQuerier {
+	c.writeUsingClause(hints...)
+	return newCypherQuerier(c.cypher)
+}
+
+func (c *CypherQuerier) When(cond bool, then func(c *CypherQuerier) *CypherQuerier) *CypherQuerier {
+	if !cond {
+		return c
+	}
+	return then(c)
+}
+
 func (c *CypherUpdater[To]) Create(pattern Patterns) To {
 	c.writeCreateClause(pattern.nodes())
 	return c.To(c.cypher)
@@ -240,10 +258,16 @@ func (c *CypherRunner) Compile() (*CompiledCypher, error) {
 		c.bindings = map[string]reflect.Value{}
 	}
 	cy := &CompiledCypher{
-		Cypher:     out,
-		Parameters: c.parameters,
-		Bindings:   c.bindings,
-		IsWrite:    c.isWrite,
+		Cypher:           out,
+		Parameters:       c.parameters,
+		Bindings:         c.bindings,
+		IsWrite:          c.isWrite,
+		Channels:         c.channelBindings(),
+		DeprecatedWrites: c.deprecatedWrites,
+		Labels:           c.sortedLabels(),
+		ActorFields:      c.actorFields,
+		Hooks:            c.hooks,
+		refreshers:       c.refreshers,
 	}
 	if c.err != nil {
 		return nil, c.err
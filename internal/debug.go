@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// RedactedParams are the case-insensitive substrings of the names of
+// parameters, and of the keys of maps within them, whose values are redacted
+// by [CompiledCypher.DebugString].
+var RedactedParams = []string{"password", "secret", "token", "apikey", "api_key", "credential"}
+
+const redacted = "'<redacted>'"
+
+var paramRegexp = regexp.MustCompile("\\$(?:[\\p{L}_][\\p{L}\\p{N}_]*|`[^`]+`)")
+
+// String returns the query.
+func (cy *CompiledCypher) String() string { return cy.Cypher }
+
+// DebugString returns the query with its parameters inlined as literals, so
+// it can be pasted into Neo4J Browser. Parameters whose names contain one of
+// [RedactedParams] or redact, ignoring case, are replaced with '<redacted>',
+// as are the entries of maps with such keys and the values of encrypted
+// fields.
+//
+// The output is meant for debugging only: queries should always be run with
+// parameters.
+func (cy *CompiledCypher) DebugString(redact ...string) string {
+	redact = append(redact, RedactedParams...)
+	return paramRegexp.ReplaceAllStringFunc(cy.Cypher, func(match string) string {
+		name := strings.Trim(match[1:], "`")
+		v, ok := cy.Parameters[name]
+		if !ok {
+			return match
+		}
+		if isRedacted(name, redact) {
+			return redacted
+		}
+		return cypherLiteral(reflect.ValueOf(v), redact)
+	})
+}
+
+func isRedacted(name string, redact []string) bool {
+	name = strings.ToLower(name)
+	for _, r := range redact {
+		if r != "" && strings.Contains(name, strings.ToLower(r)) {
+			return true
+		}
+	}
+	return false
+}
+
+// cypherLiteral writes v as a Cypher literal.
+func cypherLiteral(v reflect.Value, redact []string) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "null"
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return "null"
+	}
+	if v.Type() == encryptedType {
+		return redacted
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return "datetime(" + quoteString(t.Format(time.RFC3339Nano)) + ")"
+	}
+	if IsDBType(v.Type()) {
+		return quoteString(fmt.Sprint(v.Interface()))
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.String:
+		return quoteString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Slice, reflect.Array:
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = cypherLiteral(v.Index(i), redact)
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := make([]string, 0, v.Len())
+		entries := make(map[string]reflect.Value, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			key := iter.Key().String()
+			keys = append(keys, key)
+			entries[key] = iter.Value()
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			value := redacted
+			if !isRedacted(key, redact) {
+				value = cypherLiteral(entries[key], redact)
+			}
+			keys[i] = EscapeName(key) + ": " + value
+		}
+		return "{" + strings.Join(keys, ", ") + "}"
+	case reflect.Struct:
+		bytes, err := json.Marshal(v.Interface())
+		if err != nil {
+			break
+		}
+		var props map[string]any
+		if err := json.Unmarshal(bytes, &props); err != nil {
+			break
+		}
+		return cypherLiteral(reflect.ValueOf(props), redact)
+	}
+	return quoteString(fmt.Sprint(v.Interface()))
+}
+
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
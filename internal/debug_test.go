@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugString(t *testing.T) {
+	cy := &CompiledCypher{
+		Cypher: "MATCH (u:User {name: $name})\n" +
+			"WHERE u.age >= $v1 AND u.tags = $v10 AND u.password = $password\n" +
+			"SET u += $props, u.seen = $seen, u.note = $unknown",
+		Parameters: map[string]any{
+			"name":     "O'Brien",
+			"v1":       18,
+			"v10":      []string{"a", "b"},
+			"password": "hunter2",
+			"props": map[string]any{
+				"active":   true,
+				"apiToken": "abc",
+				"score":    1.5,
+				"manager":  nil,
+			},
+			"seen": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	}
+
+	require.Equal(t, cy.Cypher, cy.String())
+	require.Equal(t, "MATCH (u:User {name: 'O\\'Brien'})\n"+
+		"WHERE u.age >= 18 AND u.tags = ['a', 'b'] AND u.password = '<redacted>'\n"+
+		"SET u += {active: true, apiToken: '<redacted>', manager: null, score: 1.5}, u.seen = datetime('2024-01-02T03:04:05Z'), u.note = $unknown",
+		cy.DebugString())
+	require.Contains(t, cy.DebugString("name"), "{name: '<redacted>'}")
+
+	encrypted := &CompiledCypher{
+		Cypher:     "SET p.ssn = $v1, p += $v2",
+		Parameters: map[string]any{"v1": Encrypted("123"), "v2": map[string]any{"ssn": Encrypted("123")}},
+	}
+	require.Equal(t, "SET p.ssn = '<redacted>', p += {ssn: '<redacted>'}", encrypted.DebugString())
+}
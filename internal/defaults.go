@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const defaultTag = "default"
+
+// builtinDefaultFuncs are the generators available to default tags of every
+// driver.
+var builtinDefaultFuncs = map[string]func() any{
+	"ulid": func() any {
+		return ulid.MustNew(ulid.Now(), defaultEntropySource).String()
+	},
+}
+
+// SetDefaultFuncs sets the generators called by default tags, by name, in
+// addition to the built-in ulid generator.
+func (s *Scope) SetDefaultFuncs(funcs map[string]func() any) {
+	s.defaultFuncs = funcs
+}
+
+// ApplyDefaults sets the zero fields of the entity identifier refers to to the
+// values of their default tags, i.e. `default:"draft"`. Tags of the form
+// `default:"name()"` call the generator registered as name in funcs, or the
+// built-in ulid generator. Pointer fields are skipped, as nil is an explicit
+// null.
+func ApplyDefaults(identifier any, funcs map[string]func() any) error {
+	entity := lifecycleEntity(identifier)
+	if entity == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(entity).Elem()
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var walk func(st reflect.Value) error
+	walk = func(st reflect.Value) error {
+		t := st.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fv := st.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				if err := walk(fv); err != nil {
+					return err
+				}
+				continue
+			}
+			tag, ok := defaultOf(f)
+			if !ok || !fv.IsZero() {
+				continue
+			}
+			v, err := defaultValue(tag, f.Type, funcs)
+			if err != nil {
+				return fmt.Errorf("invalid default of %s.%s: %w", t.Name(), f.Name, err)
+			}
+			fv.Set(v)
+		}
+		return nil
+	}
+	return walk(rv)
+}
+
+// defaultValue returns the value of the default tag of a field of type t.
+func defaultValue(tag string, t reflect.Type, funcs map[string]func() any) (reflect.Value, error) {
+	if name, ok := strings.CutSuffix(tag, "()"); ok {
+		fn, ok := funcs[name]
+		if !ok {
+			fn, ok = builtinDefaultFuncs[name]
+		}
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("unknown default function %s", name)
+		}
+		v := reflect.ValueOf(fn())
+		if !v.IsValid() || !v.Type().ConvertibleTo(t) {
+			return reflect.Value{}, fmt.Errorf("%s returned %v, which is not convertible to %s", name, v, t)
+		}
+		return v.Convert(t), nil
+	}
+	v := reflect.New(t).Elem()
+	if t == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(tag)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(tag)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(tag)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(tag, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(tag, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(tag, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetFloat(n)
+	default:
+		return reflect.Value{}, fmt.Errorf("fields of type %s can only default to functions", t)
+	}
+	return v, nil
+}
+
+// defaultOf returns the default tag of f, unless f is a pointer, as nil is an
+// explicit null.
+func defaultOf(f reflect.StructField) (string, bool) {
+	tag, ok := f.Tag.Lookup(defaultTag)
+	if !ok || !f.IsExported() || f.Type.Kind() == reflect.Ptr {
+		return "", false
+	}
+	return tag, true
+}
+
+// hasDefaults reports whether entities of type t have fields with defaults.
+func hasDefaults(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && hasDefaults(f.Type) {
+			return true
+		}
+		if _, ok := defaultOf(f); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	type base struct {
+		ID string `json:"id" default:"ulid()"`
+	}
+	type post struct {
+		base
+		Title   string        `json:"title"`
+		Status  string        `json:"status" default:"draft"`
+		Views   int           `json:"views" default:"1"`
+		Public  bool          `json:"public" default:"true"`
+		TTL     time.Duration `json:"ttl" default:"1h"`
+		Slug    string        `json:"slug" default:"slug()"`
+		Subject *string       `json:"subject" default:"none"`
+	}
+	funcs := map[string]func() any{"slug": func() any { return "hello" }}
+
+	t.Run("sets zero fields to their defaults", func(t *testing.T) {
+		p := post{Title: "Hello"}
+		require.NoError(t, ApplyDefaults(&p, funcs))
+		assert.Len(t, p.ID, 26)
+		assert.Equal(t, "draft", p.Status)
+		assert.Equal(t, 1, p.Views)
+		assert.True(t, p.Public)
+		assert.Equal(t, time.Hour, p.TTL)
+		assert.Equal(t, "hello", p.Slug)
+		assert.Nil(t, p.Subject)
+	})
+
+	t.Run("keeps set fields", func(t *testing.T) {
+		p := post{base: base{ID: "1"}, Status: "published", Views: 10}
+		require.NoError(t, ApplyDefaults(&p, funcs))
+		assert.Equal(t, "1", p.ID)
+		assert.Equal(t, "published", p.Status)
+		assert.Equal(t, 10, p.Views)
+	})
+
+	t.Run("fails on invalid defaults", func(t *testing.T) {
+		p := post{Title: "Hello"}
+		assert.ErrorContains(t, ApplyDefaults(&p, nil), "invalid default of post.Slug: unknown default function slug")
+
+		type counter struct {
+			Name  string `json:"name"`
+			Count int    `json:"count" default:"many"`
+		}
+		assert.ErrorContains(t, ApplyDefaults(&counter{Name: "views"}, nil), "invalid default of counter.Count")
+	})
+}
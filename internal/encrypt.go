@@ -0,0 +1,51 @@
+package internal
+
+import "reflect"
+
+// Encrypted is the value of a string field tagged with `neogo:"encrypt"`,
+// which parameterizes the properties written from the field until it is
+// encrypted, once the query is run.
+type Encrypted string
+
+// IsEncrypted reports whether f is a string (or *string) field tagged with
+// `neogo:"encrypt"`.
+func IsEncrypted(f reflect.StructField) bool {
+	t := f.Type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String && hasNeogoOption(f, "encrypt")
+}
+
+// EncryptProps returns props, a map keyed by the JSON names of the fields of
+// t, with the values of its encrypted fields wrapped in [Encrypted], so maps
+// merged into entities are encrypted as their structs are. Empty and nil
+// values are left as they are.
+func EncryptProps(t reflect.Type, props map[string]any) map[string]any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var out map[string]any
+	for _, f := range UpsertFields(t) {
+		if !IsEncrypted(t.FieldByIndex(f.Index)) {
+			continue
+		}
+		v := reflect.Indirect(reflect.ValueOf(props[f.Property]))
+		if !v.IsValid() || v.Kind() != reflect.String || v.Type() == encryptedType || v.String() == "" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(props))
+			for k, v := range props {
+				out[k] = v
+			}
+		}
+		out[f.Property] = Encrypted(v.String())
+	}
+	if out == nil {
+		return props
+	}
+	return out
+}
+
+var encryptedType = reflect.TypeOf(Encrypted(""))
@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"reflect"
 
 	"github.com/oklog/ulid/v2"
 )
@@ -17,6 +20,7 @@ var (
 	_ interface {
 		INode
 		IDSetter
+		ElementIDSetter
 	} = (*Node)(nil)
 	_ IRelationship = (*Relationship)(nil)
 )
@@ -31,14 +35,107 @@ type IDSetter interface {
 	GenerateID()
 }
 
+// ElementIDSetter is implemented by entities which record the element ID
+// Neo4J assigned them.
+type ElementIDSetter interface {
+	SetElementID(id string)
+}
+
+// BeforeSaver is implemented by entities which prepare themselves, i.e. by
+// setting timestamps or validating their fields, before they are written by a
+// CREATE, MERGE or SET clause. It is called with the context of the query
+// when the query is run. Returning an error fails the query.
+type BeforeSaver interface {
+	BeforeSave(ctx context.Context) error
+}
+
+// BeforeDeleter is implemented by entities which are notified before they are
+// deleted by a DELETE clause. It is called with the context of the query when
+// the query is run. Returning an error fails the query.
+type BeforeDeleter interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// ValidateSaved validates the entity identifier refers to against the
+// validate tags of its fields, unless it implements [BeforeSaver], in which
+// case it is validated after its hook is called.
+func ValidateSaved(identifier any) error {
+	entity := lifecycleEntity(identifier)
+	if _, ok := entity.(BeforeSaver); ok || entity == nil {
+		return nil
+	}
+	if err := Validate(entity); err != nil {
+		return fmt.Errorf("invalid %T: %w", entity, err)
+	}
+	return nil
+}
+
+// beforeSave calls the BeforeSave method of entity, if it implements
+// [BeforeSaver], then validates it.
+func beforeSave(ctx context.Context, entity any) error {
+	if s, ok := entity.(BeforeSaver); ok {
+		if err := s.BeforeSave(ctx); err != nil {
+			return fmt.Errorf("BeforeSave of %T failed: %w", entity, err)
+		}
+	}
+	if err := Validate(entity); err != nil {
+		return fmt.Errorf("invalid %T: %w", entity, err)
+	}
+	return nil
+}
+
+// beforeDelete calls the BeforeDelete method of entity.
+func beforeDelete(ctx context.Context, entity BeforeDeleter) error {
+	if err := entity.BeforeDelete(ctx); err != nil {
+		return fmt.Errorf("BeforeDelete of %T failed: %w", entity, err)
+	}
+	return nil
+}
+
+// lifecycleEntity returns the non-nil pointer to an entity identifier refers
+// to, unwrapping variables and parameters, or nil.
+func lifecycleEntity(identifier any) any {
+	for {
+		switch v := identifier.(type) {
+		case *Variable:
+			identifier = v.Identifier
+		case Variable:
+			identifier = v.Identifier
+		case *ProjectionBody:
+			identifier = v.Identifier
+		case ProjectionBody:
+			identifier = v.Identifier
+		case Param:
+			if v.Value == nil {
+				return nil
+			}
+			identifier = *v.Value
+		default:
+			rv := reflect.ValueOf(identifier)
+			if rv.Kind() != reflect.Ptr || rv.IsNil() {
+				return nil
+			}
+			return identifier
+		}
+	}
+}
+
 type Node struct {
-	ID string `json:"id"`
+	ID string `json:"id" neogo:"omitzero"`
+	// ElementID is the element ID Neo4J assigned the node, which is populated
+	// when the node is read from the database. It is not a property, and
+	// is only stable within a transaction.
+	ElementID string `json:"-"`
 }
 
 func (Node) IsNode() {}
 
 func (n Node) GetID() string { return n.ID }
 
+func (n Node) GetElementID() string { return n.ElementID }
+
+func (n *Node) SetElementID(id string) { n.ElementID = id }
+
 func (n *Node) SetID(id any) {
 	if s, ok := id.(string); ok {
 		n.ID = s
@@ -49,6 +146,30 @@ func (n *Node) GenerateID() {
 	n.ID = ulid.MustNew(ulid.Now(), defaultEntropySource).String()
 }
 
+// Keyed is implemented by nodes identified by several properties together,
+// i.e. a tenant and an ID, rather than their ID alone.
+type Keyed interface {
+	KeyProperties() []string
+}
+
+// TenantNode is a base type for nodes identified by their tenant and ID
+// together, for graphs shared by tenants whose IDs may collide.
+type TenantNode struct {
+	Node
+	TenantID string `json:"tenantId" neogo:"omitzero"`
+}
+
+var _ interface {
+	INode
+	Keyed
+} = (*TenantNode)(nil)
+
+func (n TenantNode) GetTenantID() string { return n.TenantID }
+
+func (n *TenantNode) SetTenantID(id string) { n.TenantID = id }
+
+func (TenantNode) KeyProperties() []string { return []string{"tenantId", "id"} }
+
 type IAbstract interface {
 	INode
 	IsAbstract()
@@ -0,0 +1,21 @@
+package internal
+
+import "reflect"
+
+// External is the value of a string or []byte field tagged with
+// `neogo:"external"`, which parameterizes the properties written from the
+// field until it is offloaded to the blob store, once the query is run.
+type External struct {
+	Value any
+}
+
+// IsExternal reports whether f is a string (or *string) or []byte field
+// tagged with `neogo:"external"`.
+func IsExternal(f reflect.StructField) bool {
+	t := f.Type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	isBytes := t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+	return (t.Kind() == reflect.String || isBytes) && hasNeogoOption(f, "external")
+}
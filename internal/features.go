@@ -0,0 +1,20 @@
+package internal
+
+// FeatureFlags enable, by name, behaviours of the compiler being rolled out
+// gradually. Flags which aren't set are disabled.
+type FeatureFlags map[string]bool
+
+// FeatureExistsSubqueries writes the patterns of WHERE conditions as EXISTS
+// subqueries, i.e. WHERE EXISTS { (n)-[:KNOWS]->(m) }, rather than as pattern
+// predicates, which are deprecated since Neo4J 5.
+const FeatureExistsSubqueries = "existsSubqueries"
+
+// SetFeatureFlags sets the feature flags consulted when compiling queries.
+func (s *Scope) SetFeatureFlags(flags FeatureFlags) {
+	s.features = flags
+}
+
+// FeatureEnabled reports whether the feature flag name is enabled.
+func (s *Scope) FeatureEnabled(name string) bool {
+	return s.features[name]
+}
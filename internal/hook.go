@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"time"
+)
+
+// Hook prepares an entity written by a query, or notifies an entity deleted
+// by it, when the query is run. Written entities are given their defaults,
+// stamped, passed to their BeforeSave hook and validated; deleted entities are
+// passed to their BeforeDelete hook. See [CompiledCypher.RunHooks].
+type Hook struct {
+	// Entity is the pointer to the entity, implementing [BeforeDeleter] if
+	// Delete is set.
+	Entity any
+	Delete bool
+	// created is set if the entity is created by the query.
+	created bool
+	// defaults is set if the entity is given its defaults, generated by
+	// defaultFuncs.
+	defaults     bool
+	defaultFuncs map[string]func() any
+	// clock returns the time the entity is stamped with, if it is.
+	clock func() time.Time
+}
+
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+
+// addSaveHook records hook, preparing the entity identifier refers to when
+// the query is run, if the entity is given defaults, stamped or implements
+// [BeforeSaver], or validates the entity otherwise. Zero entities may be
+// filled in before the query is run, so they are validated then. If
+// hook.created is set, the zero fields of the entity which may be set when it
+// is prepared are written as null until then.
+func (s *Scope) addSaveHook(identifier any, hook Hook) {
+	entity := lifecycleEntity(identifier)
+	if entity == nil {
+		return
+	}
+	hook.Entity = entity
+	t := reflect.TypeOf(entity)
+	if hook.clock != nil && !hasTimestamps(t) {
+		hook.clock = nil
+	}
+	hook.defaults = hook.defaults && hasDefaults(t)
+	hook.defaultFuncs = s.defaultFuncs
+	zero := reflect.ValueOf(entity).Elem().IsZero()
+	if _, ok := entity.(BeforeSaver); !ok && hook.clock == nil && !hook.defaults && !zero {
+		if err := Validate(entity); err != nil {
+			panic(fmt.Errorf("invalid %T: %w", entity, err))
+		}
+		return
+	}
+	s.addHook(hook)
+}
+
+// addDeleteHook records the BeforeDelete hook of the entity identifier refers
+// to, if it implements [BeforeDeleter]. Zero entities only identify what is
+// deleted, so they are skipped.
+func (s *Scope) addDeleteHook(identifier any) {
+	entity, ok := lifecycleEntity(identifier).(BeforeDeleter)
+	if ok && !reflect.ValueOf(entity).Elem().IsZero() {
+		s.addHook(Hook{Entity: entity, Delete: true})
+	}
+}
+
+func (s *Scope) addHook(hook Hook) {
+	for i, h := range s.hooks {
+		if h.Entity == hook.Entity && h.Delete == hook.Delete {
+			s.hooks[i].created = h.created || hook.created
+			s.hooks[i].defaults = h.defaults || hook.defaults
+			if hook.clock != nil {
+				s.hooks[i].clock = hook.clock
+			}
+			return
+		}
+	}
+	s.hooks = append(s.hooks, hook)
+}
+
+// saveHook returns the BeforeSave hook recorded for entity, or nil.
+func (s *Scope) saveHook(entity any) *Hook {
+	for i, h := range s.hooks {
+		if !h.Delete && h.Entity == entity {
+			return &s.hooks[i]
+		}
+	}
+	return nil
+}
+
+// placeholder reports whether the zero field f of the entity prepared by h is
+// written as null until the entity is prepared, as preparing it may set f.
+func (h *Hook) placeholder(f reflect.StructField) bool {
+	if h == nil || !h.created {
+		return false
+	}
+	if _, ok := h.Entity.(BeforeSaver); ok {
+		return true
+	}
+	if _, ok := defaultOf(f); ok && h.defaults {
+		return true
+	}
+	created, updated := timestampOf(f)
+	return h.clock != nil && (created || updated)
+}
+
+// prepare gives the entity of h its defaults, stamps it, and calls its
+// BeforeSave hook with ctx, then validates it.
+func (h Hook) prepare(ctx context.Context) error {
+	if h.defaults {
+		if err := ApplyDefaults(h.Entity, h.defaultFuncs); err != nil {
+			return err
+		}
+	}
+	if h.clock != nil {
+		Stamp(h.Entity, h.clock(), h.created)
+	}
+	return beforeSave(ctx, h.Entity)
+}
+
+// EntityParam returns a parameter of value, read from entity when the query is
+// built. If entity implements [BeforeSaver], its hook is called when the query
+// is run, after which the parameter is set to read().
+func EntityParam(entity, value any, read func() any) Param {
+	param := Param{Value: &value}
+	if _, ok := entity.(BeforeSaver); ok {
+		param.saved = entity
+		param.refresh = func() (any, error) { return read(), nil }
+	}
+	return param
+}
+
+// addParam adds param as a parameter of the query, returning its name, i.e.
+// $v1.
+func (s *Scope) addParam(param Param) string {
+	v := reflect.ValueOf(*param.Value)
+	if !v.IsValid() && param.refresh != nil {
+		// Parameters which are refreshed may be null until they are.
+		v = reflect.Zero(anyType)
+	}
+	name := s.addParameter(v, param.Name, param.generated || param.Name == "")
+	if param.refresh != nil {
+		if entity, ok := param.saved.(BeforeSaver); ok {
+			s.addHook(Hook{Entity: entity})
+		}
+		s.refreshers[name[1:]] = param.refresh
+	}
+	return name
+}
+
+// RunHooks prepares the entities written by the query, calling their
+// BeforeSave hooks with ctx, and calls the BeforeDelete hooks of those it
+// deletes, in the order they are written. The parameters written from the
+// prepared entities are then reread. It is called once each time the query is
+// run, rather than when it is built or compiled, so entities are only changed
+// when they are written.
+func (cy *CompiledCypher) RunHooks(ctx context.Context) error {
+	for _, h := range cy.Hooks {
+		var err error
+		if h.Delete {
+			err = beforeDelete(ctx, h.Entity.(BeforeDeleter))
+		} else {
+			err = h.prepare(ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(cy.refreshers) == 0 {
+		return nil
+	}
+	params := maps.Clone(cy.Parameters)
+	for name, refresh := range cy.refreshers {
+		v, err := refresh()
+		if err != nil {
+			return fmt.Errorf("cannot reread parameter %s: %w", name, err)
+		}
+		params[name] = v
+	}
+	cy.Parameters = params
+	return nil
+}
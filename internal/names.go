@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/iancoleman/strcase"
+)
+
+// isIdentifierRune reports whether r can appear in an unquoted name or a
+// parameter name. Marks are included so that decomposed graphemes, i.e. e
+// followed by a combining acute accent, are kept intact.
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// lcFirst lowercases the first rune of s.
+func lcFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError || !unicode.IsUpper(r) {
+		return s
+	}
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+// ucFirst uppercases the first rune of s.
+func ucFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError || !unicode.IsLower(r) {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// toLowerCamel converts s to lowerCamelCase, to name variables after labels,
+// types and Go types. Unlike [strcase.ToLowerCamel], which drops non-ASCII
+// runes, letters of any script are preserved and only runes which cannot
+// appear in names (i.e. emoji) are dropped.
+func toLowerCamel(s string) string {
+	if isASCII(s) {
+		return strcase.ToLowerCamel(s)
+	}
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || !isIdentifierRune(r)
+	})
+	var b strings.Builder
+	for i, w := range words {
+		if i == 0 {
+			b.WriteString(lcFirst(w))
+		} else {
+			b.WriteString(ucFirst(w))
+		}
+	}
+	return b.String()
+}
+
+// paramName converts s, a property name or key, into a valid parameter name.
+// Separators are replaced by underscores, while other runes which cannot
+// appear in parameter names (i.e. emoji) are replaced by their code point.
+// Keys differing only in their separators, i.e. "a-b" and "a_b", share a
+// name, so callers must disambiguate them.
+func paramName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case isIdentifierRune(r):
+			b.WriteRune(r)
+		case r == '.' || r == '-' || unicode.IsSpace(r):
+			b.WriteByte('_')
+		default:
+			_, _ = fmt.Fprintf(&b, "u%04x", r)
+		}
+	}
+	return b.String()
+}
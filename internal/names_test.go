@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNames(t *testing.T) {
+	t.Run("lowerCamel preserves non-ASCII letters", func(t *testing.T) {
+		for in, want := range map[string]string{
+			"Person":          "person",
+			"Spare Part":      "sparePart",
+			"Ärger":           "ärger",
+			"Straße":          "straße",
+			"日本語":             "日本語",
+			"Émoji😀":          "émoji",
+			"Été":            "été",
+			"Über Fahrzeug":   "überFahrzeug",
+			"Ωmega_Συστήματα": "ωmegaΣυστήματα",
+		} {
+			require.Equal(t, want, toLowerCamel(in), in)
+		}
+	})
+
+	t.Run("paramName keeps letters and encodes symbols", func(t *testing.T) {
+		require.Equal(t, "größe", paramName("größe"))
+		require.Equal(t, "address_city", paramName("address.city"))
+		require.Equal(t, "first_name", paramName("first name"))
+		require.Equal(t, "u2764", paramName("❤"))
+		require.NotEqual(t, paramName("❤"), paramName("⭐"))
+	})
+
+	t.Run("EscapeName only quotes names which need it", func(t *testing.T) {
+		require.Equal(t, "größe", EscapeName("größe"))
+		require.Equal(t, "名前", EscapeName("名前"))
+		require.Equal(t, "été", EscapeName("été"))
+		require.Equal(t, "`❤ count`", EscapeName("❤ count"))
+		require.Equal(t, "`1st`", EscapeName("1st"))
+		require.Equal(t, "```a```", EscapeName("`a`"))
+	})
+
+	t.Run("compiles non-English identifiers", func(t *testing.T) {
+		type fahrzeug struct {
+			Node  `neo4j:"Fahrzeug"`
+			Größe int               `json:"größe"`
+			Tags  map[string]string `json:"tags" neogo:"flatten"`
+		}
+		cy, err := NewCypherClient().
+			Create(NewNode(&fahrzeug{Größe: 3, Tags: map[string]string{"❤": "a", "⭐": "b"}})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (fahrzeug:Fahrzeug {`tags_❤`: $fahrzeug_tags_u2764, `tags_⭐`: $fahrzeug_tags_u2b50, größe: $fahrzeug_größe})", cy.Cypher)
+		require.Equal(t, map[string]any{
+			"fahrzeug_größe":      3,
+			"fahrzeug_tags_u2764": "a",
+			"fahrzeug_tags_u2b50": "b",
+		}, cy.Parameters)
+	})
+
+	t.Run("quotes property keys once", func(t *testing.T) {
+		type item struct {
+			Node `neo4j:"Item"`
+			Size string `json:"size cm"`
+		}
+		var v item
+		cy, err := NewCypherClient().
+			Match(NewNode(&Variable{Identifier: &v, Name: "n", Props: Props{&v.Size: "'1'", "other key": "'2'"}})).
+			Return(&v.Size).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, "MATCH (n:Item {`other key`: '2', `size cm`: '1'})\nRETURN n.`size cm`", cy.Cypher)
+	})
+}
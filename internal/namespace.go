@@ -0,0 +1,8 @@
+package internal
+
+// SetNamespace sets the label added to the node patterns of entities, so the
+// nodes of applications sharing a database are kept apart. The label is not
+// reported as one of the labels of the query.
+func (s *Scope) SetNamespace(label string) {
+	s.namespace = label
+}
@@ -0,0 +1,355 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// nestedField is a struct field tagged with `neogo:"nested"`, whose value is
+// stored as dot-suffixed properties or, with `neogo:"nested,json"`, as a JSON
+// string. `neogo:"json_blob"` is shorthand for the latter, for semi-structured
+// values of any type, i.e. a slice of structs. In patterns, the dot-suffixed
+// properties of zero fields are omitted like the zero fields of entities,
+// following their keepzero and omitzero tags.
+//
+// Neo4J can't store maps as properties, so there is no map mode: APOC stores
+// them as JSON strings too. Properties written as JSON strings can be read in
+// Cypher with apoc.convert.getJsonPropertyMap, and those written with
+// apoc.convert.setJsonProperty read into fields tagged `neogo:"nested,json"`.
+//
+// Map fields tagged with `neogo:"flatten"` are also nested fields, whose
+// entries are stored as underscore-suffixed properties, i.e. attrs_<key>.
+// Those tagged with `neogo:"extra"` catch all the properties not stored by
+// other fields, whose entries are stored as-is.
+type nestedField struct {
+	name    string
+	asJSON  bool
+	flatten bool
+	extra   bool
+}
+
+func nestedFieldOf(f reflect.StructField) (nestedField, bool) {
+	if hasNeogoOption(f, "extra") {
+		name, ok := extractJSONFieldName(f)
+		return nestedField{name: name, extra: true}, ok
+	}
+	blob := hasNeogoOption(f, "json_blob")
+	flatten := !blob && hasNeogoOption(f, "flatten")
+	if !blob && !flatten && !hasNeogoOption(f, "nested") {
+		return nestedField{}, false
+	}
+	name, ok := extractJSONFieldName(f)
+	if !ok {
+		return nestedField{}, false
+	}
+	return nestedField{
+		name:    name,
+		asJSON:  blob || !flatten && hasNeogoOption(f, "json"),
+		flatten: flatten,
+	}, true
+}
+
+func nestedFields(t reflect.Type) []nestedField {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []nestedField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, ok := extractJSONFieldName(f); !ok {
+			if f.Anonymous {
+				fields = append(fields, nestedFields(f.Type)...)
+			}
+			continue
+		}
+		if nf, ok := nestedFieldOf(f); ok {
+			fields = append(fields, nf)
+		}
+	}
+	return fields
+}
+
+// EncodeNested encodes the nested fields of t within props, the JSON
+// representation of a value of type t, so they can be stored as properties.
+func EncodeNested(t reflect.Type, props map[string]any) (map[string]any, error) {
+	for _, f := range nestedFields(t) {
+		v, ok := props[f.name]
+		if !ok {
+			continue
+		}
+		delete(props, f.name)
+		encoded, err := f.encode(v)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range encoded {
+			if _, ok := props[k]; ok && f.extra {
+				continue
+			}
+			props[k] = v
+		}
+	}
+	return props, nil
+}
+
+// encodeProps encodes props, a map keyed by the JSON names of the fields of t,
+// so they are stored as the same properties as a value of type t.
+func encodeProps(t reflect.Type, props map[string]any) (map[string]any, error) {
+	props = EncryptProps(t, props)
+	out := make(map[string]any, len(props))
+	for k, v := range props {
+		out[k] = v
+	}
+	for _, f := range nestedFields(t) {
+		v, ok := out[f.name]
+		if !ok || v == nil {
+			continue
+		}
+		delete(out, f.name)
+		encoded, err := f.encodeValue(reflect.ValueOf(v))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range encoded {
+			if _, ok := out[k]; ok && f.extra {
+				continue
+			}
+			out[k] = v
+		}
+	}
+	return EncodeProtobuf(t, out), nil
+}
+
+// DecodeNested reverses [EncodeNested], so that props can be unmarshalled into
+// a value of type t.
+func DecodeNested(t reflect.Type, props map[string]any) (map[string]any, error) {
+	fields := nestedFields(t)
+	if len(fields) == 0 {
+		return props, nil
+	}
+	out := make(map[string]any, len(props))
+	for k, v := range props {
+		out[k] = v
+	}
+	var (
+		declared map[string]reflect.Type
+		extra    string
+	)
+	for _, f := range fields {
+		if f.extra {
+			extra = f.name
+			continue
+		}
+		if f.flatten {
+			if declared == nil {
+				declared = PropertyTypes(t)
+			}
+			var m map[string]any
+			prefix := f.name + "_"
+			for k, v := range props {
+				if _, ok := declared[k]; ok || !strings.HasPrefix(k, prefix) {
+					continue
+				}
+				delete(out, k)
+				if m == nil {
+					m = map[string]any{}
+				}
+				m[strings.TrimPrefix(k, prefix)] = v
+			}
+			if m != nil {
+				out[f.name] = m
+			}
+			continue
+		}
+		if f.asJSON {
+			s, ok := out[f.name].(string)
+			if !ok {
+				continue
+			}
+			var v any
+			if err := json.Unmarshal([]byte(s), &v); err != nil {
+				return nil, fmt.Errorf("cannot decode nested property %q: %w", f.name, err)
+			}
+			out[f.name] = v
+			continue
+		}
+		var tree map[string]any
+		prefix := f.name + "."
+		for k, v := range props {
+			if !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			delete(out, k)
+			if tree == nil {
+				tree = map[string]any{}
+			}
+			node := tree
+			path := strings.Split(strings.TrimPrefix(k, prefix), ".")
+			for _, key := range path[:len(path)-1] {
+				next, ok := node[key].(map[string]any)
+				if !ok {
+					next = map[string]any{}
+					node[key] = next
+				}
+				node = next
+			}
+			node[path[len(path)-1]] = v
+		}
+		if tree != nil {
+			out[f.name] = tree
+		}
+	}
+	if extra != "" {
+		if declared == nil {
+			declared = PropertyTypes(t)
+		}
+		var m map[string]any
+		for k, v := range out {
+			if _, ok := declared[k]; ok {
+				continue
+			}
+			delete(out, k)
+			if m == nil {
+				m = map[string]any{}
+			}
+			m[k] = v
+		}
+		if m != nil {
+			out[extra] = m
+		}
+	}
+	return out, nil
+}
+
+func (f nestedField) encode(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if f.asJSON {
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode nested property %q: %w", f.name, err)
+		}
+		return map[string]any{f.name: string(bytes)}, nil
+	}
+	if f.flatten || f.extra {
+		kind := "flattened"
+		if f.extra {
+			kind = "extra"
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s property %q must be a map", kind, f.name)
+		}
+		out := make(map[string]any, len(m))
+		for k, v := range m {
+			if _, ok := v.(map[string]any); ok {
+				return nil, fmt.Errorf("%s property %q cannot contain maps, found one at key %q", kind, f.name, k)
+			}
+			if f.extra {
+				out[k] = v
+			} else {
+				out[f.name+"_"+k] = v
+			}
+		}
+		return out, nil
+	}
+	out := map[string]any{}
+	var flatten func(prefix string, v any)
+	flatten = func(prefix string, v any) {
+		m, ok := v.(map[string]any)
+		if !ok {
+			out[prefix] = v
+			return
+		}
+		for k, v := range m {
+			flatten(prefix+"."+k, v)
+		}
+	}
+	if _, ok := v.(map[string]any); !ok {
+		return nil, fmt.Errorf("nested property %q must be a struct or map", f.name)
+	}
+	flatten(f.name, v)
+	return out, nil
+}
+
+// nestedProp is a property a nested field is stored as, holding the zero value
+// of the struct field it is encoded from.
+type nestedProp struct {
+	zero  any
+	field reflect.StructField
+}
+
+// zeroProps returns the properties a zero value of type t, that of the nested
+// field, is stored as with dot-suffixed keys, so zero fields can be told apart
+// from those which are set.
+func (f nestedField) zeroProps(t reflect.Type) (map[string]nestedProp, error) {
+	if f.asJSON || f.flatten || f.extra {
+		return nil, nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	encoded, err := f.encodeValue(reflect.New(t).Elem())
+	if err != nil {
+		return nil, err
+	}
+	props := make(map[string]nestedProp, len(encoded))
+	for key, zero := range encoded {
+		path := strings.Split(strings.TrimPrefix(key, f.name+"."), ".")
+		field, _ := fieldByJSONPath(t, path)
+		props[key] = nestedProp{zero: zero, field: field}
+	}
+	return props, nil
+}
+
+// fieldByJSONPath returns the struct field of t named by path, the JSON names
+// of the fields leading to it.
+func fieldByJSONPath(t reflect.Type, path []string) (reflect.StructField, bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || len(path) == 0 {
+		return reflect.StructField{}, false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := extractJSONFieldName(f)
+		if !ok {
+			if f.Anonymous {
+				if field, ok := fieldByJSONPath(f.Type, path); ok {
+					return field, true
+				}
+			}
+			continue
+		}
+		if name != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return f, true
+		}
+		return fieldByJSONPath(f.Type, path[1:])
+	}
+	return reflect.StructField{}, false
+}
+
+// encodeValue encodes the value of a nested field, keyed by the
+// properties it is stored as.
+func (f nestedField) encodeValue(v reflect.Value) (map[string]any, error) {
+	bytes, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode nested property %q: %w", f.name, err)
+	}
+	var js any
+	if err := json.Unmarshal(bytes, &js); err != nil {
+		return nil, fmt.Errorf("cannot encode nested property %q: %w", f.name, err)
+	}
+	return f.encode(js)
+}
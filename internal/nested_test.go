@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	address struct {
+		City string   `json:"city"`
+		Geo  location `json:"geo"`
+	}
+	location struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	}
+	customer struct {
+		Node    `neo4j:"Customer"`
+		Name    string         `json:"name"`
+		Address address        `json:"address" neogo:"nested"`
+		Meta    map[string]any `json:"meta" neogo:"nested,json"`
+	}
+)
+
+func TestNested(t *testing.T) {
+	typ := reflect.TypeOf(customer{})
+
+	t.Run("encodes as flat keys and JSON", func(t *testing.T) {
+		props, err := EncodeNested(typ, map[string]any{
+			"name": "Ada",
+			"address": map[string]any{
+				"city": "London",
+				"geo":  map[string]any{"lat": 51.5, "lng": -0.1},
+			},
+			"meta": map[string]any{"vip": true},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"name":            "Ada",
+			"address.city":    "London",
+			"address.geo.lat": 51.5,
+			"address.geo.lng": -0.1,
+			"meta":            `{"vip":true}`,
+		}, props)
+	})
+
+	t.Run("decodes flat keys and JSON", func(t *testing.T) {
+		props, err := DecodeNested(typ, map[string]any{
+			"name":            "Ada",
+			"address.city":    "London",
+			"address.geo.lat": 51.5,
+			"meta":            `{"vip":true}`,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"name": "Ada",
+			"address": map[string]any{
+				"city": "London",
+				"geo":  map[string]any{"lat": 51.5},
+			},
+			"meta": map[string]any{"vip": true},
+		}, props)
+	})
+
+	t.Run("injects flat keys into patterns", func(t *testing.T) {
+		cy, err := NewCypherClient().
+			Create(NewNode(&customer{
+				Name:    "Ada",
+				Address: address{City: "London", Geo: location{Lat: 51.5}},
+			})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (customer:Customer {`address.city`: $customer_address_city, `address.geo.lat`: $customer_address_geo_lat, name: $customer_name})", cy.Cypher)
+		require.Equal(t, map[string]any{
+			"customer_name":            "Ada",
+			"customer_address_city":    "London",
+			"customer_address_geo_lat": 51.5,
+		}, cy.Parameters)
+	})
+
+	t.Run("writes zero flat keys like other zero fields", func(t *testing.T) {
+		type (
+			point struct {
+				Lat float64 `json:"lat" neogo:"keepzero"`
+				Lng float64 `json:"lng"`
+			}
+			site struct {
+				Node  `neo4j:"Site"`
+				Name  string `json:"name"`
+				Point point  `json:"point" neogo:"nested"`
+			}
+		)
+		cy, err := NewCypherClient().
+			Create(NewNode(&site{Name: "HQ", Point: point{Lng: 1}})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (site:Site {`point.lat`: $site_point_lat, `point.lng`: $site_point_lng, name: $site_name})", cy.Cypher)
+
+		client := NewCypherClient()
+		client.SetIncludeZero(true)
+		cy, err = client.
+			Create(NewNode(&customer{Name: "Ada", Address: address{City: "London"}})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"customer_name":            "Ada",
+			"customer_address_city":    "London",
+			"customer_address_geo_lat": float64(0),
+			"customer_address_geo_lng": float64(0),
+		}, cy.Parameters)
+	})
+}
+
+func TestFlatten(t *testing.T) {
+	type product struct {
+		Node       `neo4j:"Product"`
+		Name       string            `json:"name"`
+		AttrsCount int               `json:"attrs_count"`
+		Attrs      map[string]string `json:"attrs" neogo:"flatten"`
+	}
+	typ := reflect.TypeOf(product{})
+
+	t.Run("encodes entries as prefixed keys", func(t *testing.T) {
+		props, err := EncodeNested(typ, map[string]any{
+			"name":  "Lamp",
+			"attrs": map[string]any{"color": "red", "size": "L"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"name":        "Lamp",
+			"attrs_color": "red",
+			"attrs_size":  "L",
+		}, props)
+	})
+
+	t.Run("errors on nested maps", func(t *testing.T) {
+		_, err := EncodeNested(typ, map[string]any{
+			"attrs": map[string]any{"dims": map[string]any{"w": 1}},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("decodes prefixed keys, ignoring declared properties", func(t *testing.T) {
+		props, err := DecodeNested(typ, map[string]any{
+			"name":        "Lamp",
+			"attrs_count": 2,
+			"attrs_color": "red",
+			"attrs_size":  "L",
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"name":        "Lamp",
+			"attrs_count": 2,
+			"attrs":       map[string]any{"color": "red", "size": "L"},
+		}, props)
+	})
+
+	t.Run("injects prefixed keys into patterns", func(t *testing.T) {
+		cy, err := NewCypherClient().
+			Create(NewNode(&product{
+				Name:  "Lamp",
+				Attrs: map[string]string{"shade color": "red"},
+			})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (product:Product {`attrs_shade color`: $product_attrs_shade_color, name: $product_name})", cy.Cypher)
+		require.Equal(t, map[string]any{
+			"product_name":              "Lamp",
+			"product_attrs_shade_color": "red",
+		}, cy.Parameters)
+	})
+
+	t.Run("suffixes keys sharing a parameter name", func(t *testing.T) {
+		cy, err := NewCypherClient().
+			Create(NewNode(&product{
+				Name:  "Lamp",
+				Attrs: map[string]string{"a-b": "dash", "a_b": "underscore"},
+			})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (product:Product {`attrs_a-b`: $product_attrs_a_b, attrs_a_b: $product_attrs_a_b_2, name: $product_name})", cy.Cypher)
+		require.Equal(t, map[string]any{
+			"product_name":        "Lamp",
+			"product_attrs_a_b":   "dash",
+			"product_attrs_a_b_2": "underscore",
+		}, cy.Parameters)
+	})
+}
@@ -149,6 +149,10 @@ func (c *Condition) Condition() *Condition {
 
 type Props map[any]any
 
+// propertyKey is a key in [Props] which is written as-is (escaped if
+// necessary), rather than resolved as a property identifier.
+type propertyKey string
+
 func (p Props) configureVariable(v *Variable) {
 	v.Props = p
 }
@@ -166,7 +170,101 @@ type (
 	}
 )
 
+type (
+	HintKind string
+	Hint     struct {
+		Kind        HintKind
+		Identifiers []any
+	}
+)
+
+const (
+	HintIndex HintKind = "INDEX"
+	HintScan  HintKind = "SCAN"
+	HintJoin  HintKind = "JOIN ON"
+)
+
+// Procedure is an invocation of a stored procedure, written as a CALL clause
+// followed by an optional YIELD clause.
+type Procedure struct {
+	Name   string
+	Args   []any
+	Yields []any
+}
+
+// Yield sets the outputs of the procedure which are yielded and bound to the
+// given identifiers.
+func (p *Procedure) Yield(identifiers ...any) *Procedure {
+	p.Yields = append(p.Yields, identifiers...)
+	return p
+}
+
+// FuncCall is a call of a Cypher function, whose arguments are resolved as
+// values when the query is compiled. It can be used as an identifier, or as a
+// condition if the function returns a boolean.
+type FuncCall struct {
+	Name string
+	Args []any
+	// Distinct makes an aggregating function only consider distinct values.
+	Distinct bool
+}
+
+// Fragment is a raw Cypher expression, whose %s placeholders are replaced by
+// the names or parameters of Args when the query is compiled. Named Params
+// don't fill a placeholder, and are only added to the parameters of the query.
+// It can be used as an identifier, or as a condition.
+type Fragment struct {
+	Format string
+	Args   []any
+}
+
+func (f *Fragment) configureWhere(w *Where) {
+	w.Conds = append(w.Conds, f.Condition())
+}
+
+func (f *Fragment) Condition() *Condition {
+	return &Condition{Key: f}
+}
+
+// Reduce is a reduce() expression, which folds Expr over each Variable in List
+// into Accumulator, starting from Init. It can be used as an identifier.
+type Reduce struct {
+	Accumulator string
+	Init        any
+	Variable    string
+	List        any
+	Expr        any
+}
+
+// CollectSubquery is a COLLECT subquery, which collects the values returned by
+// Subquery into a list. It can be used as an identifier.
+type CollectSubquery struct {
+	Subquery func(c *CypherClient) *CypherRunner
+}
+
+func (f *FuncCall) configureWhere(w *Where) {
+	w.Conds = append(w.Conds, f.Condition())
+}
+
+func (f *FuncCall) Condition() *Condition {
+	return &Condition{Key: f}
+}
+
 type Param struct {
 	Name  string
 	Value *any
+	// generated is set for parameters whose name was generated, rather than
+	// given explicitly.
+	generated bool
+	// saved is the entity the parameter is read from, whose BeforeSave hook is
+	// called when the query is run, after which the parameter is set to
+	// refresh().
+	saved   any
+	refresh func() (any, error)
 }
+
+// ParamNamer returns the name of a generated parameter, given its default
+// name and its 1-based position amongst the generated parameters of a query.
+// It must return the same name for the same inputs, so compiled queries are
+// deterministic.
+type ParamNamer func(name string, index int) string
@@ -2,6 +2,7 @@ package internal_test
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -81,3 +82,45 @@ func TestParameter(t *testing.T) {
 		assert.Nil(t, cy)
 	})
 }
+
+func TestParamNamer(t *testing.T) {
+	compile := func(namer internal.ParamNamer) (*internal.CompiledCypher, error) {
+		p := tests.Person{Name: "Jessie", Surname: "Pinkman"}
+		var m tests.Movie
+		c := internal.NewCypherClient()
+		c.SetParamNamer(namer)
+		return c.
+			Merge(db.Node(db.Qual(&p, "p"))).
+			Match(db.Node(db.Qual(&m, "m"))).
+			Where(db.And(
+				db.Cond(&m.Released, ">", db.Param(2000)),
+				db.Cond(&m.Title, "=", db.NamedParam("The Matrix", "title")),
+			)).
+			Return(&m).
+			Compile()
+	}
+	counter := func(_ string, i int) string { return "p" + strconv.Itoa(i) }
+
+	cy, err := compile(counter)
+	tests.Check(t, cy, err, internal.CompiledCypher{
+		Cypher: `
+				MERGE (p:Person {name: $p1, surname: $p2})
+				MATCH (m:Movie)
+				WHERE m.released > $p3 AND m.title = $title
+				RETURN m
+				`,
+		Parameters: map[string]any{
+			"p1":    "Jessie",
+			"p2":    "Pinkman",
+			"p3":    2000,
+			"title": "The Matrix",
+		},
+		Bindings: map[string]reflect.Value{
+			"m": cy.Bindings["m"],
+		},
+	})
+
+	again, err := compile(counter)
+	assert.NoError(t, err)
+	assert.Equal(t, cy.Cypher, again.Cypher, "compiles deterministically")
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// extractProtobufFieldName returns the JSON name of field from its protobuf
+// tag, i.e. `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3"`.
+// The json option is omitted by protoc when it is the same as name.
+func extractProtobufFieldName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup(protobufTag)
+	if !ok {
+		return "", false
+	}
+	var name string
+	for _, opt := range strings.Split(tag, ",") {
+		if v, ok := strings.CutPrefix(opt, "json="); ok {
+			return v, true
+		}
+		if v, ok := strings.CutPrefix(opt, "name="); ok {
+			name = v
+		}
+	}
+	return name, name != ""
+}
+
+// protobufFields maps the Go field names of t, which are only tagged by
+// protobuf, to their property names.
+func protobufFields(t reflect.Type) map[string]string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields map[string]string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, ok := f.Tag.Lookup("json"); ok || !f.IsExported() {
+			continue
+		}
+		if f.Anonymous {
+			for k, v := range protobufFields(f.Type) {
+				if fields == nil {
+					fields = map[string]string{}
+				}
+				fields[k] = v
+			}
+			continue
+		}
+		name, ok := extractProtobufFieldName(f)
+		if !ok || name == f.Name {
+			continue
+		}
+		if fields == nil {
+			fields = map[string]string{}
+		}
+		fields[f.Name] = name
+	}
+	return fields
+}
+
+// EncodeProtobuf renames the properties of props, the JSON representation of
+// a value of type t, from the Go names of fields only tagged by protobuf to
+// their property names.
+func EncodeProtobuf(t reflect.Type, props map[string]any) map[string]any {
+	for goName, name := range protobufFields(t) {
+		if v, ok := props[goName]; ok {
+			delete(props, goName)
+			props[name] = v
+		}
+	}
+	return props
+}
+
+// DecodeProtobuf reverses [EncodeProtobuf], so that props can be unmarshalled
+// into a value of type t.
+func DecodeProtobuf(t reflect.Type, props map[string]any) map[string]any {
+	fields := protobufFields(t)
+	if len(fields) == 0 {
+		return props
+	}
+	out := make(map[string]any, len(props))
+	for k, v := range props {
+		out[k] = v
+	}
+	for goName, name := range fields {
+		if v, ok := out[name]; ok {
+			delete(out, name)
+			out[goName] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// account mirrors the shape of a protoc-gen-go message without json tags.
+type account struct {
+	Node `neo4j:"Account"`
+
+	Handle        string   `protobuf:"bytes,1,opt,name=handle,proto3"`
+	DisplayName   string   `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3"`
+	FollowerCount int64    `protobuf:"varint,3,opt,name=follower_count,json=followerCount,proto3"`
+	Aliases       []string `protobuf:"bytes,4,rep,name=aliases,proto3"`
+	Email         string   `protobuf:"bytes,5,opt,name=email,proto3" json:"mail"`
+}
+
+func TestProtobufFields(t *testing.T) {
+	typ := reflect.TypeOf(account{})
+
+	t.Run("extracts names from protobuf tags", func(t *testing.T) {
+		for field, want := range map[string]string{
+			"Handle":        "handle",
+			"DisplayName":   "displayName",
+			"FollowerCount": "followerCount",
+			"Email":         "mail",
+		} {
+			f, _ := typ.FieldByName(field)
+			name, ok := extractJSONFieldName(f)
+			require.True(t, ok)
+			require.Equal(t, want, name, field)
+		}
+	})
+
+	t.Run("injects props of protobuf messages", func(t *testing.T) {
+		cy, err := NewCypherClient().
+			Create(NewNode(&account{Handle: "ada", DisplayName: "Ada"})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (account:Account {displayName: $account_displayName, handle: $account_handle})", cy.Cypher)
+		require.Equal(t, map[string]any{
+			"account_handle":      "ada",
+			"account_displayName": "Ada",
+		}, cy.Parameters)
+	})
+
+	t.Run("renames JSON-marshalled fields", func(t *testing.T) {
+		props := EncodeProtobuf(typ, map[string]any{
+			"id":            "1",
+			"Handle":        "ada",
+			"DisplayName":   "Ada",
+			"FollowerCount": 3,
+			"mail":          "ada@example.com",
+		})
+		require.Equal(t, map[string]any{
+			"id":            "1",
+			"handle":        "ada",
+			"displayName":   "Ada",
+			"followerCount": 3,
+			"mail":          "ada@example.com",
+		}, props)
+		require.Equal(t, map[string]any{
+			"id":            "1",
+			"Handle":        "ada",
+			"DisplayName":   "Ada",
+			"FollowerCount": 3,
+			"mail":          "ada@example.com",
+		}, DecodeProtobuf(typ, props))
+	})
+}
@@ -4,10 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
-
-	"github.com/iancoleman/strcase"
+	"time"
 )
 
 func newScope() *Scope {
@@ -18,6 +18,8 @@ func newScope() *Scope {
 		fields:         make(map[uintptr]field),
 		parameters:     map[string]any{},
 		paramAddrs:     map[uintptr]string{},
+		channels:       map[reflect.Value]reflect.Value{},
+		refreshers:     map[string]func() (any, error){},
 	}
 }
 
@@ -33,9 +35,50 @@ type (
 
 		paramCounter int
 		paramPrefix  string
+		paramNamer   ParamNamer
+		// namedParams counts the parameters named by paramNamer.
+		namedParams int
 
 		parameters map[string]any
 		paramAddrs map[uintptr]string
+
+		// channels maps the identifiers of Chan identifiers to their channels.
+		channels map[reflect.Value]reflect.Value
+		// projections maps node types to the properties returned for them.
+		projections map[reflect.Type][]string
+		// clock returns the time entities are stamped with, if timestamps are
+		// enabled.
+		clock func() time.Time
+		// features are the feature flags consulted when compiling queries.
+		features FeatureFlags
+		// actorFields are the actor fields of the entities written by the query,
+		// recorded if actorFieldsEnabled is set.
+		actorFields        []*ActorField
+		actorFieldsEnabled bool
+		// hooks are the lifecycle hooks of the entities written and deleted by
+		// the query, and refreshers reread the parameters written from saved
+		// entities, by name, once their hooks are called.
+		hooks      []Hook
+		refreshers map[string]func() (any, error)
+		// defaultFuncs are the generators called by default tags, by name.
+		defaultFuncs map[string]func() any
+		// includeZero writes the zero fields of the structs bound in the
+		// patterns of CREATE and MERGE clauses.
+		includeZero bool
+		// writing is set while the patterns of CREATE and MERGE clauses are
+		// registered, whose properties are written rather than matched.
+		writing bool
+		// checkType checks the types bound in the query.
+		checkType func(reflect.Type) error
+		// namespace is the label added to the node patterns of entities.
+		namespace string
+		// propertyAliases are the keys properties are renamed to, by label and
+		// old key.
+		propertyAliases map[string]map[string]string
+
+		deprecatedWrites []DeprecatedField
+		// labels are the node labels written in the query.
+		labels []string
 	}
 	// An instance of a node/relationship in the cypher query
 	member struct {
@@ -111,14 +154,38 @@ func (s *Scope) clone() *Scope {
 	for k, v := range s.paramAddrs {
 		paramAddrs[k] = v
 	}
+	channels := make(map[reflect.Value]reflect.Value, len(s.channels))
+	for k, v := range s.channels {
+		channels[k] = v
+	}
+	refreshers := make(map[string]func() (any, error), len(s.refreshers))
+	for k, v := range s.refreshers {
+		refreshers[k] = v
+	}
 	return &Scope{
-		bindings:       bindings,
-		generatedNames: generatedNames,
-		names:          names,
-		fields:         fields,
-		paramCounter:   paramCounter,
-		parameters:     parameters,
-		paramAddrs:     paramAddrs,
+		bindings:           bindings,
+		generatedNames:     generatedNames,
+		names:              names,
+		fields:             fields,
+		paramCounter:       paramCounter,
+		paramNamer:         s.paramNamer,
+		namedParams:        s.namedParams,
+		clock:              s.clock,
+		features:           s.features,
+		actorFields:        append([]*ActorField(nil), s.actorFields...),
+		actorFieldsEnabled: s.actorFieldsEnabled,
+		hooks:              append([]Hook(nil), s.hooks...),
+		refreshers:         refreshers,
+		defaultFuncs:       s.defaultFuncs,
+		includeZero:        s.includeZero,
+		checkType:          s.checkType,
+		namespace:          s.namespace,
+		propertyAliases:    s.propertyAliases,
+		parameters:         parameters,
+		paramAddrs:         paramAddrs,
+		channels:           channels,
+		deprecatedWrites:   append([]DeprecatedField(nil), s.deprecatedWrites...),
+		labels:             append([]string(nil), s.labels...),
 	}
 }
 
@@ -128,6 +195,16 @@ func (child *Scope) mergeParentScope(parent *Scope) {
 	// We assume people that aren't using generated names know what they're
 	// doing (and therefore delegate potential errors to Neo4J).
 	child.paramCounter = parent.paramCounter
+	child.paramNamer = parent.paramNamer
+	child.clock = parent.clock
+	child.features = parent.features
+	child.actorFieldsEnabled = parent.actorFieldsEnabled
+	child.defaultFuncs = parent.defaultFuncs
+	child.includeZero = parent.includeZero
+	child.checkType = parent.checkType
+	child.namespace = parent.namespace
+	child.propertyAliases = parent.propertyAliases
+	child.namedParams = parent.namedParams
 	for generatedName := range parent.generatedNames {
 		v := parent.bindings[generatedName]
 		child.bindings[generatedName] = v
@@ -145,6 +222,11 @@ func (s *Scope) clear() {
 	s.fields = map[uintptr]field{}
 	s.parameters = map[string]any{}
 	s.paramAddrs = map[uintptr]string{}
+	s.deprecatedWrites = nil
+	s.labels = nil
+	s.actorFields = nil
+	s.hooks = nil
+	s.refreshers = map[string]func() (any, error){}
 }
 
 func (s *Scope) MergeChildScope(child *Scope) {
@@ -166,7 +248,26 @@ func (s *Scope) MergeChildScope(child *Scope) {
 	for k, v := range child.paramAddrs {
 		s.paramAddrs[k] = v
 	}
+	for k, v := range child.channels {
+		s.channels[k] = v
+	}
+	for _, f := range child.deprecatedWrites {
+		s.addDeprecatedWrite(f)
+	}
+	for _, label := range child.labels {
+		s.addLabel(label)
+	}
+	for _, f := range child.actorFields {
+		s.addActorField(f.Field)
+	}
+	for _, h := range child.hooks {
+		s.addHook(h)
+	}
+	for k, v := range child.refreshers {
+		s.refreshers[k] = v
+	}
 	s.paramCounter = child.paramCounter
+	s.namedParams = child.namedParams
 	if child.isWrite {
 		s.isWrite = true
 	}
@@ -226,6 +327,8 @@ RecurseToEntity:
 		case *Variable:
 			mergeV(v)
 			identifier = v.Identifier
+		case *Chan:
+			identifier = s.registerChan(v)
 		default:
 			break RecurseToEntity
 		}
@@ -289,7 +392,7 @@ func (s *Scope) bindFields(strct reflect.Value, memberName string) {
 		}
 		ptr := uintptr(vf.Addr().UnsafePointer())
 		f := field{
-			name:       accessor,
+			name:       EscapeName(accessor),
 			identifier: memberName,
 		}
 		s.fields[ptr] = f
@@ -336,9 +439,33 @@ func (s *Scope) register(value any, lookup bool, isNode *bool) *member {
 	if identifier == nil {
 		return m
 	}
+	if expr, prefix, ok := s.inlineExpr(identifier); ok {
+		if lookup {
+			return nil
+		}
+		// Function calls and subquery expressions are written in place, and are
+		// only bound to a result through Bind.
+		if m.expr != "" {
+			m.alias = m.expr
+		}
+		m.expr = expr
+		if variable != nil && variable.Bind != nil {
+			if m.alias == "" {
+				m.alias = s.generateName(prefix)
+			}
+			m.identifier = variable.Bind
+			s.replaceBinding(m)
+		}
+		return m
+	}
 
 	v := reflect.ValueOf(identifier)
 	vT := v.Type()
+	if s.checkType != nil {
+		if err := s.checkType(vT); err != nil {
+			panic(err)
+		}
+	}
 	canElem := vT.Kind() == reflect.Ptr ||
 		vT.Kind() == reflect.Slice
 
@@ -377,30 +504,16 @@ func (s *Scope) register(value any, lookup bool, isNode *bool) *member {
 		if needsName {
 			var prefix string
 			if vT.Implements(nodeType) {
-				prefix = strcase.ToLowerCamel(ExtractNodeLabels(identifier)[0])
+				prefix = toLowerCamel(ExtractNodeLabels(identifier)[0])
 			} else if vT.Implements(relationshipType) {
-				prefix = strcase.ToLowerCamel(ExtractRelationshipType(identifier))
+				prefix = toLowerCamel(ExtractRelationshipType(identifier))
 			} else {
-				prefix = strcase.ToLowerCamel(vT.Elem().Name())
+				prefix = toLowerCamel(vT.Elem().Name())
 				if prefix == "" {
-					prefix = strcase.ToLowerCamel(vT.Elem().Kind().String())
-				}
-			}
-			if _, ok := s.bindings[prefix]; !ok {
-				m.expr = prefix
-			} else {
-				var potentialName string
-				i := 1
-				for {
-					potentialName = fmt.Sprintf("%s%d", prefix, i)
-					if _, ok := s.bindings[potentialName]; !ok {
-						break
-					}
-					i++
+					prefix = toLowerCamel(vT.Elem().Kind().String())
 				}
-				m.expr = potentialName
 			}
-			s.generatedNames[m.expr] = struct{}{}
+			m.expr = s.generateName(prefix)
 		}
 	}
 
@@ -439,7 +552,9 @@ func (s *Scope) register(value any, lookup bool, isNode *bool) *member {
 	for inner.Kind() == reflect.Ptr {
 		inner = inner.Elem()
 	}
-	if inner.IsValid() && m.isNew && !inner.IsZero() {
+	// Zero entities created by the query are written as their placeholders.
+	hook := s.saveHook(identifier)
+	if inner.IsValid() && m.isNew && (!inner.IsZero() || hook != nil && hook.created) {
 		if m.alias != "" {
 			panic(fmt.Errorf("%w: alias %s already bound to expression %s", ErrAliasAlreadyBound, m.alias, m.expr))
 		}
@@ -450,12 +565,14 @@ func (s *Scope) register(value any, lookup bool, isNode *bool) *member {
 			if effName == "" {
 				effName = m.expr
 			}
+			generated := true
 			if p, ok := inner.Interface().(Param); ok {
 				effName = p.Name
+				generated = p.generated || p.Name == ""
 				prop := *p.Value
 				effProp = reflect.ValueOf(prop)
 			}
-			param := s.addParameter(effProp, effName)
+			param := s.addParameter(effProp, effName, generated)
 			if canHaveProps {
 				m.propsParam = param
 			} else {
@@ -477,15 +594,43 @@ func (s *Scope) register(value any, lookup bool, isNode *bool) *member {
 			// qualified parameters. This allows props to be used in MATCH and MERGE
 			// clause for instance, where a property expression is not allowed.
 			props := make(Props)
+			// Distinct keys can share a parameter name, i.e. "a-b" and "a_b",
+			// in which case the later ones are suffixed.
+			paramNames := map[string]struct{}{}
+			uniqueParamName := func(name string) string {
+				unique := name
+				for i := 2; ; i++ {
+					if _, ok := paramNames[unique]; !ok {
+						break
+					}
+					unique = name + "_" + strconv.Itoa(i)
+				}
+				paramNames[unique] = struct{}{}
+				return unique
+			}
+			// The fields of entities prepared when the query is run are reread
+			// once they are.
+			aliases := s.aliasesOf(identifier)
+			// qualify names the parameter of the property key after the
+			// variable of the entity.
+			qualify := func(key string) string {
+				if m.expr == "" {
+					return paramName(key)
+				}
+				return m.expr + "_" + paramName(key)
+			}
 			var bindFieldsFrom func(reflect.Value)
 			bindFieldsFrom = func(value reflect.Value) {
 				for value.Kind() == reflect.Ptr {
+					if value.IsNil() {
+						return
+					}
 					value = value.Elem()
 				}
 				innerT := value.Type()
 				for i := 0; i < innerT.NumField(); i++ {
 					f := value.Field(i)
-					if !f.IsValid() || !f.CanInterface() || f.IsZero() {
+					if !f.IsValid() || !f.CanInterface() {
 						continue
 					}
 					fT := innerT.Field(i)
@@ -496,16 +641,105 @@ func (s *Scope) register(value any, lookup bool, isNode *bool) *member {
 						}
 						continue
 					}
-					propName := name
-					if m.expr != "" {
-						propName = m.expr + "_" + name
+					actorField := s.actorField(f)
+					_, nested := nestedFieldOf(fT)
+					keep := s.keepZero(fT, s.writing)
+					zero := f.IsZero() && actorField == nil && !keep
+					// The zero fields of created entities are written as null
+					// until they are prepared, which may set them. Nested
+					// fields are only written as they are when the query is
+					// built.
+					if zero && (nested || !hook.placeholder(fT)) {
+						continue
+					}
+					if hasNeogoOption(fT, "deprecated") {
+						s.addDeprecatedWrite(DeprecatedField{
+							Type:     innerT,
+							Field:    fT.Name,
+							Property: name,
+							Write:    true,
+						})
 					}
+					propName := qualify(name)
 
-					prop := f.Interface()
-					props[name] = Param{
-						Name:  propName,
-						Value: &prop,
+					if nf, ok := nestedFieldOf(fT); ok {
+						encoded, err := nf.encodeValue(f)
+						if err != nil {
+							panic(err)
+						}
+						// Zero nested fields are omitted like other fields.
+						zeroProps, err := nf.zeroProps(fT.Type)
+						if err != nil {
+							panic(err)
+						}
+						keys := make([]string, 0, len(encoded))
+						for key := range encoded {
+							keys = append(keys, key)
+						}
+						sort.Strings(keys)
+						for _, key := range keys {
+							v := encoded[key]
+							if zp, ok := zeroProps[key]; ok && !keep &&
+								!s.keepZero(zp.field, s.writing) &&
+								reflect.DeepEqual(v, zp.zero) {
+								continue
+							}
+							suffix := strings.TrimPrefix(key, name)
+							if nf.extra {
+								if _, ok := PropertyTypes(innerT)[key]; ok {
+									continue
+								}
+								suffix = "_" + key
+							}
+							keyParam := propName + paramName(suffix)
+							if newKey, ok := aliases[key]; ok {
+								if _, ok := PropertyTypes(innerT)[newKey]; ok {
+									continue
+								}
+								key, keyParam = newKey, qualify(newKey)
+							}
+							prop := v
+							props[propertyKey(key)] = Param{
+								Name:      uniqueParamName(keyParam),
+								Value:     &prop,
+								generated: true,
+							}
+						}
+						continue
+					}
+					// Aliased fields are written under their new keys, unless
+					// the entity also has a field with the new key.
+					key := name
+					if newKey, ok := aliases[name]; ok {
+						if _, ok := PropertyTypes(innerT)[newKey]; ok {
+							continue
+						}
+						key, propName = newKey, qualify(newKey)
+					}
+					var prop any
+					switch {
+					case actorField != nil:
+						prop = actorField
+					case !zero:
+						var err error
+						if prop, err = fieldProperty(name, fT, f); err != nil {
+							panic(err)
+						}
+					}
+					param := Param{
+						Name:      uniqueParamName(propName),
+						Value:     &prop,
+						generated: true,
+					}
+					if hook != nil && actorField == nil {
+						param.refresh = func() (any, error) {
+							if f.IsZero() && !keep {
+								return nil, nil
+							}
+							return fieldProperty(name, fT, f)
+						}
 					}
+					props[key] = param
 				}
 			}
 			bindFieldsFrom(inner)
@@ -522,6 +756,30 @@ func (s *Scope) register(value any, lookup bool, isNode *bool) *member {
 	return m
 }
 
+// fieldProperty returns the property written from the field f named name.
+func fieldProperty(name string, fT reflect.StructField, f reflect.Value) (any, error) {
+	prop := f.Interface()
+	if isArrayProperty(fT.Type) {
+		arr, err := ArrayProperty(name, f)
+		if err != nil {
+			return nil, err
+		}
+		prop = arr
+	}
+	if temporal, ok := TemporalValue(fT, f); ok {
+		prop = temporal
+	}
+	switch v := reflect.Indirect(f); {
+	case !v.IsValid():
+		// Nil pointers are written as null.
+	case IsEncrypted(fT):
+		prop = Encrypted(v.String())
+	case IsExternal(fT):
+		prop = External{Value: v.Interface()}
+	}
+	return prop, nil
+}
+
 func (s *Scope) registerNode(n *nodePattern) *member {
 	t := true
 	return s.register(n.data, false, &t)
@@ -536,6 +794,15 @@ func (s *Scope) Name(identifier any) string {
 	return s.lookupName(identifier)
 }
 
+// Param adds value as a parameter of the query, returning its name, i.e. $v1.
+// Values created with NamedParam keep their name.
+func (s *Scope) Param(value any) string {
+	if param, ok := value.(Param); ok {
+		return s.addParam(param)
+	}
+	return s.addParameter(reflect.ValueOf(value), "", true)
+}
+
 func (s *Scope) AddError(err error) {
 	if err == nil {
 		return
@@ -549,6 +816,8 @@ func (s *Scope) AddError(err error) {
 
 func (s *Scope) Error() error { return s.err }
 
+func (s *Scope) Escape(name string) string { return EscapeName(name) }
+
 func (s *Scope) lookupName(identifier any) string {
 	identifier, _, _ = s.unfoldIdentifier(identifier)
 	return s.names[reflect.ValueOf(identifier)]
@@ -561,11 +830,14 @@ func (s *Scope) propertyIdentifier(identifier any) func(v any) string {
 		if v == identifier && identifierName != "" {
 			return identifierName
 		}
+		if expr, _, ok := s.inlineExpr(v); ok {
+			return expr
+		}
 		if expr, ok := v.(Expr); ok {
 			return string(expr)
 		} else if str, strOk := v.(string); strOk && identifierName != "" {
 			// Consider strings as properties if identifier is known
-			return fmt.Sprintf("%s.%s", identifierName, str)
+			return fmt.Sprintf("%s.%s", identifierName, EscapeName(str))
 		} else if strOk {
 			// Otherwise, consider strings as literals
 			return str
@@ -586,6 +858,9 @@ func (s *Scope) propertyIdentifier(identifier any) func(v any) string {
 }
 
 func (s *Scope) valueIdentifier(v any) string {
+	if expr, _, ok := s.inlineExpr(v); ok {
+		return expr
+	}
 	vv := reflect.ValueOf(v)
 	switch vv.Kind() {
 	case reflect.Bool:
@@ -606,9 +881,9 @@ func (s *Scope) valueIdentifier(v any) string {
 		reflect.Array, reflect.Interface, reflect.Map,
 		reflect.Slice, reflect.Struct:
 		if param, ok := v.(Param); ok {
-			return s.addParameter(reflect.ValueOf(*param.Value), param.Name)
+			return s.addParam(param)
 		} else {
-			return s.addParameter(vv, "")
+			return s.addParameter(vv, "", true)
 		}
 	case reflect.Pointer:
 		ptr := vv.Pointer()
@@ -624,9 +899,156 @@ func (s *Scope) valueIdentifier(v any) string {
 	panic(fmt.Errorf("could not find a value-representation for %v", v))
 }
 
-func (s *Scope) addParameter(v reflect.Value, optName string) (name string) {
+// generateName returns a name for a variable starting with prefix, which is
+// not already bound.
+func (s *Scope) generateName(prefix string) string {
+	name := prefix
+	for i := 1; ; i++ {
+		if _, ok := s.bindings[name]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s%d", prefix, i)
+	}
+	s.generatedNames[name] = struct{}{}
+	return name
+}
+
+// inlineExpr returns the expression of identifiers which are written in place,
+// along with a prefix for naming their result.
+func (s *Scope) inlineExpr(identifier any) (expr string, prefix string, ok bool) {
+	switch v := identifier.(type) {
+	case *FuncCall:
+		name := v.Name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		return s.funcCall(v), toLowerCamel(name), true
+	case *CollectSubquery:
+		return s.collectSubquery(v), "collect", true
+	case *Fragment:
+		return s.fragment(v), "expr", true
+	case *Reduce:
+		return fmt.Sprintf(
+			"reduce(%s = %s, %s IN %s | %s)",
+			v.Accumulator, s.valueIdentifier(v.Init),
+			v.Variable, s.valueIdentifier(v.List),
+			s.valueIdentifier(v.Expr),
+		), v.Accumulator, true
+	}
+	return "", "", false
+}
+
+func (s *Scope) funcCall(f *FuncCall) string {
+	args := make([]string, len(f.Args))
+	for i, arg := range f.Args {
+		args[i] = s.valueIdentifier(arg)
+	}
+	if f.Distinct {
+		return f.Name + "(DISTINCT " + strings.Join(args, ", ") + ")"
+	}
+	return f.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (s *Scope) fragment(f *Fragment) string {
+	var args []string
+	for _, arg := range f.Args {
+		if p, ok := arg.(Param); ok && p.Name != "" {
+			s.valueIdentifier(p)
+			continue
+		}
+		args = append(args, s.valueIdentifier(arg))
+	}
+	var (
+		b    strings.Builder
+		used int
+	)
+	for i := 0; i < len(f.Format); i++ {
+		if f.Format[i] != '%' || i+1 == len(f.Format) {
+			b.WriteByte(f.Format[i])
+			continue
+		}
+		switch f.Format[i+1] {
+		case 's':
+			if used == len(args) {
+				panic(fmt.Errorf("%w: %q", errFragmentArgs, f.Format))
+			}
+			b.WriteString(args[used])
+			used++
+			i++
+		case '%':
+			b.WriteByte('%')
+			i++
+		default:
+			b.WriteByte('%')
+		}
+	}
+	if used != len(args) {
+		panic(fmt.Errorf("%w: %q", errFragmentArgs, f.Format))
+	}
+	return b.String()
+}
+
+func (s *Scope) collectSubquery(c *CollectSubquery) string {
+	child := NewCypherClient()
+	child.Parent = s
+	child.mergeParentScope(s)
+	runner := c.Subquery(child)
+	compiled, err := runner.Compile()
+	if err != nil {
+		panic(err)
+	}
+	// Only parameters are shared with the enclosing scope, as the variables of
+	// the subquery are not visible outside of it.
+	for k, v := range runner.parameters {
+		s.parameters[k] = v
+	}
+	for k, v := range runner.paramAddrs {
+		s.paramAddrs[k] = v
+	}
+	s.paramCounter = runner.paramCounter
+	s.namedParams = runner.namedParams
+	return "COLLECT {\n  " + strings.ReplaceAll(compiled.Cypher, "\n", "\n  ") + "\n}"
+}
+
+// SetProjections sets the properties returned for nodes of each type, which are
+// returned as map projections rather than whole nodes.
+func (s *Scope) SetProjections(projections map[reflect.Type][]string) {
+	s.projections = projections
+}
+
+// projection returns the map projection returning the properties of the node
+// bound to m, if a projection was set for its type.
+func (s *Scope) projection(m *member) (string, bool) {
+	if len(s.projections) == 0 || m.identifier == nil {
+		return "", false
+	}
+	t := reflect.TypeOf(m.identifier)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	fields, ok := s.projections[t]
+	if !ok {
+		return "", false
+	}
+	props := make([]string, len(fields))
+	for i, f := range fields {
+		props[i] = "." + EscapeName(f)
+	}
+	return fmt.Sprintf("%s {%s}", m.expr, strings.Join(props, ", ")), true
+}
+
+// SetParamNamer sets the namer used for the names of generated parameters.
+func (s *Scope) SetParamNamer(namer ParamNamer) {
+	s.paramNamer = namer
+}
+
+// addParameter adds v as a parameter named optName, or a name generated from
+// a counter if it is empty. Unless the name was explicitly given, i.e. through
+// NamedParam, generated is true and the name is passed to the ParamNamer.
+func (s *Scope) addParameter(v reflect.Value, optName string, generated bool) (name string) {
 	defer func() {
 		if v.IsValid() && v.CanInterface() {
+			s.collectDeprecatedWrites(v)
 			s.parameters[name] = v.Interface()
 		} else {
 			fmt.Printf("[WARNING] invalid parameter: %s\n", name)
@@ -645,6 +1067,12 @@ func (s *Scope) addParameter(v reflect.Value, optName string) (name string) {
 			s.paramAddrs[addr] = name
 		}()
 	}
+	if generated && s.paramNamer != nil {
+		defer func() {
+			s.namedParams++
+			name = s.paramNamer(name, s.namedParams)
+		}()
+	}
 	if optName != "" {
 		return optName
 	}
@@ -655,3 +1083,56 @@ func (s *Scope) addParameter(v reflect.Value, optName string) (name string) {
 	s.paramCounter++
 	return paramPrefix + strconv.Itoa(s.paramCounter)
 }
+
+func (s *Scope) addDeprecatedWrite(f DeprecatedField) {
+	for _, existing := range s.deprecatedWrites {
+		if existing == f {
+			return
+		}
+	}
+	s.deprecatedWrites = append(s.deprecatedWrites, f)
+}
+
+func (s *Scope) addLabel(label string) {
+	for _, existing := range s.labels {
+		if existing == label {
+			return
+		}
+	}
+	s.labels = append(s.labels, label)
+}
+
+func (s *Scope) sortedLabels() []string {
+	if len(s.labels) == 0 {
+		return nil
+	}
+	labels := append([]string(nil), s.labels...)
+	sort.Strings(labels)
+	return labels
+}
+
+// collectDeprecatedWrites records the non-zero deprecated fields of a struct
+// (or slice of structs) injected as a parameter.
+func (s *Scope) collectDeprecatedWrites(v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			s.collectDeprecatedWrites(v.Index(i))
+		}
+	case reflect.Struct:
+		for _, f := range DeprecatedFields(v.Type()) {
+			fv := v.FieldByName(f.Field)
+			if !fv.IsValid() || fv.IsZero() {
+				continue
+			}
+			f.Write = true
+			s.addDeprecatedWrite(f)
+		}
+	}
+}
@@ -33,3 +33,39 @@ func TestBindFields(t *testing.T) {
 		}, s.names)
 	})
 }
+
+func TestDeprecatedWrites(t *testing.T) {
+	type legacy struct {
+		Node     `neo4j:"Legacy"`
+		Name     string `json:"name"`
+		Nickname string `json:"nickname" neogo:"deprecated"`
+	}
+	want := []DeprecatedField{{
+		Type:     reflect.TypeOf(legacy{}),
+		Field:    "Nickname",
+		Property: "nickname",
+		Write:    true,
+	}}
+
+	t.Run("records non-zero deprecated fields injected as props", func(t *testing.T) {
+		cy, err := NewCypherClient().
+			Create(NewNode(&legacy{Name: "n", Nickname: "nick"})).
+			Compile()
+		require.NoError(t, err)
+		require.Equal(t, want, cy.DeprecatedWrites)
+	})
+
+	t.Run("records deprecated fields of parameters", func(t *testing.T) {
+		s := newScope()
+		s.addParameter(reflect.ValueOf([]legacy{{Nickname: "a"}, {Nickname: "b"}}), "", true)
+		require.Equal(t, want, s.deprecatedWrites)
+	})
+
+	t.Run("ignores zero-valued deprecated fields", func(t *testing.T) {
+		cy, err := NewCypherClient().
+			Create(NewNode(&legacy{Name: "n"})).
+			Compile()
+		require.NoError(t, err)
+		require.Empty(t, cy.DeprecatedWrites)
+	})
+}
@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const dbTag = "db"
+
+// TagConflict describes a field whose tags disagree on the name of its
+// property.
+type TagConflict struct {
+	// Type is the struct type declaring the field.
+	Type reflect.Type
+	// Field is the name of the Go struct field.
+	Field string
+	// Property is the name of the property the field is written as, from its
+	// json tag. It is empty if the field is not written.
+	Property string
+	// Reason describes the conflict.
+	Reason string
+}
+
+func (c TagConflict) Error() string {
+	return fmt.Sprintf("%s.%s: %s", c.Type.Name(), c.Field, c.Reason)
+}
+
+// TagConflicts are the fields of a struct whose tags disagree.
+type TagConflicts []TagConflict
+
+func (c TagConflicts) Error() string {
+	msgs := make([]string, len(c))
+	for i, conflict := range c {
+		msgs[i] = conflict.Error()
+	}
+	return "conflicting property tags: " + strings.Join(msgs, "; ")
+}
+
+// SetTypeCheck sets check to be called with the types bound in the query,
+// failing the query if it returns an error.
+func (s *Scope) SetTypeCheck(check func(reflect.Type) error) {
+	s.checkType = check
+}
+
+// CheckTags returns the fields of the struct type t, including those of
+// embedded structs, whose tags disagree on the name of their property:
+//
+//   - A db tag, as read by database/sql mappers sharing the struct, naming a
+//     different property than its json tag. Properties are only named by json
+//     tags, so the db tag is ignored.
+//   - A property differing only in case from that of another field.
+//     Properties are written by their exact name, but read case-insensitively,
+//     so reads of either may bind the other.
+//   - A validate tag with an unknown rule or a malformed parameter, which
+//     would otherwise only fail the first write of the field.
+func CheckTags(t reflect.Type) TagConflicts {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || IsDBType(t) {
+		return nil
+	}
+	var conflicts TagConflicts
+	seen := map[string]string{}
+	var walk func(st reflect.Type)
+	walk = func(st reflect.Type) {
+		for i := 0; i < st.NumField(); i++ {
+			f := st.Field(i)
+			name, ok := extractJSONFieldName(f)
+			if !ok && f.Anonymous {
+				ft := f.Type
+				for ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft)
+				}
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if ok && name == "" {
+				name = f.Name
+			}
+			if dbName, hasDB := f.Tag.Lookup(dbTag); hasDB {
+				dbName = strings.Split(dbName, ",")[0]
+				if dbName != "" && dbName != "-" && dbName != name {
+					reason := fmt.Sprintf("db tag names %q, but json tag names %q", dbName, name)
+					if !ok {
+						reason = fmt.Sprintf("db tag names %q, but it is not written as a property", dbName)
+					}
+					conflicts = append(conflicts, TagConflict{
+						Type:     st,
+						Field:    f.Name,
+						Property: name,
+						Reason:   reason,
+					})
+				}
+			}
+			if !ok {
+				continue
+			}
+			if tag, hasValidate := f.Tag.Lookup(validateTag); hasValidate {
+				for _, rule := range strings.Split(tag, ",") {
+					rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+					if rule == "" {
+						continue
+					}
+					if err := checkRule(rule, param); err != nil {
+						conflicts = append(conflicts, TagConflict{
+							Type:     st,
+							Field:    f.Name,
+							Property: name,
+							Reason:   err.Error(),
+						})
+					}
+				}
+			}
+			folded := strings.ToLower(name)
+			if other, dup := seen[folded]; dup && other != name {
+				conflicts = append(conflicts, TagConflict{
+					Type:     st,
+					Field:    f.Name,
+					Property: name,
+					Reason:   fmt.Sprintf("property %q differs from %q only in case", name, other),
+				})
+				continue
+			}
+			seen[folded] = name
+		}
+	}
+	walk(t)
+	return conflicts
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type taggedBase struct {
+	ID string `json:"id" db:"id"`
+}
+
+type taggedPerson struct {
+	taggedBase
+
+	Name     string `json:"name" db:"full_name"`
+	Email    string `json:"email,omitempty" db:"email,omitempty"`
+	Secret   string `json:"-" db:"secret"`
+	Nickname string `json:"Name"`
+	Notes    string `db:"-"`
+}
+
+func TestCheckTags(t *testing.T) {
+	t.Run("passes consistent tags", func(t *testing.T) {
+		assert.Empty(t, CheckTags(reflect.TypeOf(taggedBase{})))
+		assert.Empty(t, CheckTags(reflect.TypeOf("")))
+	})
+
+	t.Run("returns conflicting fields", func(t *testing.T) {
+		conflicts := CheckTags(reflect.TypeOf(&taggedPerson{}))
+		typ := reflect.TypeOf(taggedPerson{})
+		assert.Equal(t, TagConflicts{
+			{Type: typ, Field: "Name", Property: "name", Reason: `db tag names "full_name", but json tag names "name"`},
+			{Type: typ, Field: "Secret", Reason: `db tag names "secret", but it is not written as a property`},
+			{Type: typ, Field: "Nickname", Property: "Name", Reason: `property "Name" differs from "name" only in case`},
+		}, conflicts)
+		assert.EqualError(t, conflicts[:1], `conflicting property tags: taggedPerson.Name: db tag names "full_name", but json tag names "name"`)
+	})
+	t.Run("returns malformed validate tags", func(t *testing.T) {
+		type account struct {
+			Name string `json:"name" validate:"requird"`
+			Age  int    `json:"age" validate:"min=x"`
+		}
+		typ := reflect.TypeOf(account{})
+		assert.Equal(t, TagConflicts{
+			{Type: typ, Field: "Name", Property: "name", Reason: "unknown validation rule: requird"},
+			{Type: typ, Field: "Age", Property: "age", Reason: `invalid parameter of validation rule min: "x"`},
+		}, CheckTags(typ))
+	})
+}
@@ -6,7 +6,11 @@ import (
 	"strings"
 )
 
-const neo4jTag = "neo4j"
+const (
+	neo4jTag    = "neo4j"
+	neogoTag    = "neogo"
+	protobufTag = "protobuf"
+)
 
 func ExtractNodeLabels(i any) []string {
 	labels := extractNodeLabels(i)
@@ -140,10 +144,187 @@ func extractNeo4JName(instance any, fields ...string) ([]neo4jName, error) {
 	return tags, nil
 }
 
+// extractJSONFieldName returns the property name of field from its json tag,
+// falling back to its protobuf tag for protobuf-generated structs.
 func extractJSONFieldName(field reflect.StructField) (string, bool) {
 	jsTag, ok := field.Tag.Lookup("json")
 	if !ok {
+		return extractProtobufFieldName(field)
+	}
+	name := strings.Split(jsTag, ",")[0]
+	if name == "-" {
 		return "", false
 	}
-	return strings.Split(jsTag, ",")[0], true
+	return name, true
+}
+
+// PropertyTypes returns the types of the fields of the struct type t, including
+// those of embedded structs, keyed by property name.
+func PropertyTypes(t reflect.Type) map[string]reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	types := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := extractJSONFieldName(f)
+		if !ok {
+			if f.Anonymous {
+				for name, ft := range PropertyTypes(f.Type) {
+					types[name] = ft
+				}
+			}
+			continue
+		}
+		types[name] = f.Type
+	}
+	return types
+}
+
+// hasNeogoOption reports whether field has opt in its neogo tag, i.e.
+// `neogo:"deprecated"`.
+func hasNeogoOption(field reflect.StructField, opt string) bool {
+	tag, ok := field.Tag.Lookup(neogoTag)
+	if !ok {
+		return false
+	}
+	for _, o := range strings.Split(tag, ",") {
+		if strings.TrimSpace(o) == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// DeprecatedField describes a read or write of a struct field tagged with
+// `neogo:"deprecated"`.
+type DeprecatedField struct {
+	// Type is the struct type declaring the field.
+	Type reflect.Type
+	// Field is the name of the Go struct field.
+	Field string
+	// Property is the name of the property in Neo4J.
+	Property string
+	// Write is true if the field was written to the database, and false if it
+	// was read from it.
+	Write bool
+}
+
+// DeprecatedFields returns the deprecated fields of the struct type t,
+// including those of embedded structs, keyed by property name.
+func DeprecatedFields(t reflect.Type) map[string]DeprecatedField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields map[string]DeprecatedField
+	var walk func(reflect.Type)
+	walk = func(st reflect.Type) {
+		for i := 0; i < st.NumField(); i++ {
+			f := st.Field(i)
+			name, ok := extractJSONFieldName(f)
+			if !ok {
+				if f.Anonymous && f.Type.Kind() == reflect.Struct {
+					walk(f.Type)
+				}
+				continue
+			}
+			if !hasNeogoOption(f, "deprecated") {
+				continue
+			}
+			if fields == nil {
+				fields = map[string]DeprecatedField{}
+			}
+			fields[name] = DeprecatedField{
+				Type:     st,
+				Field:    f.Name,
+				Property: name,
+			}
+		}
+	}
+	walk(t)
+	return fields
+}
+
+// MergeStrategy is how a property is written when upserting an entity with
+// MERGE, configured with the neogo tag of its field.
+type MergeStrategy int
+
+const (
+	// MergeAlways writes the property both when the entity is created and
+	// when it is matched.
+	MergeAlways MergeStrategy = iota
+	// MergeOnCreate only writes the property when the entity is created, i.e.
+	// `neogo:"oncreate"`.
+	MergeOnCreate
+	// MergeOnMatch only writes the property when the entity is matched, i.e.
+	// `neogo:"onmatch"`.
+	MergeOnMatch
+	// MergeAppend writes the property when the entity is created, and appends
+	// to the existing list when it is matched, i.e. `neogo:"append"`.
+	MergeAppend
+)
+
+// UpsertField is a property written when upserting an entity.
+type UpsertField struct {
+	// Property is the name of the property, as marshalled to JSON.
+	Property string
+	// Index is the index sequence of the field, for [reflect.Value.FieldByIndex].
+	Index    []int
+	Strategy MergeStrategy
+}
+
+// UpsertFields returns the properties of the struct type t, including those of
+// embedded structs, along with their merge strategies.
+func UpsertFields(t reflect.Type) []UpsertField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []UpsertField
+	var walk func(st reflect.Type, index []int)
+	walk = func(st reflect.Type, index []int) {
+		for i := 0; i < st.NumField(); i++ {
+			f := st.Field(i)
+			fIndex := append(index[:len(index):len(index)], i)
+			jsTag, hasTag := f.Tag.Lookup("json")
+			if !hasTag && f.Anonymous && f.Type.Kind() == reflect.Struct {
+				walk(f.Type, fIndex)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			name := strings.Split(jsTag, ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			strategy := MergeAlways
+			switch {
+			case hasNeogoOption(f, "oncreate"):
+				strategy = MergeOnCreate
+			case hasNeogoOption(f, "onmatch"):
+				strategy = MergeOnMatch
+			case hasNeogoOption(f, "append"):
+				strategy = MergeAppend
+			}
+			fields = append(fields, UpsertField{
+				Property: name,
+				Index:    fIndex,
+				Strategy: strategy,
+			})
+		}
+	}
+	walk(t, nil)
+	return fields
 }
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -129,3 +130,45 @@ func TestExtractRelationshipType(t *testing.T) {
 		assert.Equal(t, "Friendship", ExtractRelationshipType(&[]*friendship{}))
 	})
 }
+
+type legacyPerson struct {
+	person
+	Name     string `json:"name"`
+	Nickname string `json:"nickname" neogo:"deprecated"`
+}
+
+type legacyEmployee struct {
+	legacyPerson
+	Employer string `json:"employer" neogo:"deprecated"`
+}
+
+func TestDeprecatedFields(t *testing.T) {
+	t.Run("nil when no fields are deprecated", func(t *testing.T) {
+		assert.Nil(t, DeprecatedFields(reflect.TypeOf(person{})))
+	})
+
+	t.Run("extracts deprecated fields", func(t *testing.T) {
+		assert.Equal(t, map[string]DeprecatedField{
+			"nickname": {
+				Type:     reflect.TypeOf(legacyPerson{}),
+				Field:    "Nickname",
+				Property: "nickname",
+			},
+		}, DeprecatedFields(reflect.TypeOf(&legacyPerson{})))
+	})
+
+	t.Run("extracts from embedded structs", func(t *testing.T) {
+		assert.Equal(t, map[string]DeprecatedField{
+			"nickname": {
+				Type:     reflect.TypeOf(legacyPerson{}),
+				Field:    "Nickname",
+				Property: "nickname",
+			},
+			"employer": {
+				Type:     reflect.TypeOf(legacyEmployee{}),
+				Field:    "Employer",
+				Property: "employer",
+			},
+		}, DeprecatedFields(reflect.TypeOf(legacyEmployee{})))
+	})
+}
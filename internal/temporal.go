@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// Options of the neogo tag which store a time.Time field as a temporal type
+// other than DateTime, i.e. `neogo:"date"`.
+const (
+	TemporalDate          = "date"
+	TemporalLocalTime     = "localtime"
+	TemporalLocalDateTime = "localdatetime"
+	TemporalTime          = "time"
+)
+
+var temporalOptions = []string{
+	TemporalDate,
+	TemporalLocalTime,
+	TemporalLocalDateTime,
+	TemporalTime,
+}
+
+// TemporalOf returns the temporal type the time.Time (or *time.Time) field f
+// is stored as, if it is tagged with one.
+func TemporalOf(f reflect.StructField) (string, bool) {
+	t := f.Type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != timeType {
+		return "", false
+	}
+	for _, opt := range temporalOptions {
+		if hasNeogoOption(f, opt) {
+			return opt, true
+		}
+	}
+	return "", false
+}
+
+// TemporalValue converts v, the value of the field f, to the temporal type f
+// is tagged with. The wall clock of v is kept, along with its offset for
+// `neogo:"time"`, to nanosecond precision.
+func TemporalValue(f reflect.StructField, v reflect.Value) (any, bool) {
+	temporal, ok := TemporalOf(f)
+	if !ok {
+		return nil, false
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	t := v.Interface().(time.Time)
+	switch temporal {
+	case TemporalDate:
+		return dbtype.Date(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)), true
+	case TemporalLocalTime:
+		return dbtype.LocalTime(t), true
+	case TemporalLocalDateTime:
+		return dbtype.LocalDateTime(t), true
+	default:
+		return dbtype.Time(t), true
+	}
+}
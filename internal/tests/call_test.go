@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/rlch/neogo/db"
 	"github.com/rlch/neogo/internal"
 )
@@ -566,4 +568,141 @@ func TestCallProcedure(t *testing.T) {
 			},
 		})
 	})
+	t.Run("Call a procedure with arguments and yield its outputs", func(t *testing.T) {
+		var (
+			movie Movie
+			score float64
+		)
+		query := "matrix"
+		c := internal.NewCypherClient()
+		cy, err := c.
+			CallProcedure(
+				db.Procedure("db.index.fulltext.queryNodes", "'titles'", db.NamedParam(query, "query")).
+					Yield(
+						db.Qual(&movie, "node", db.Name("movie")),
+						db.Qual(&score, "score"),
+					),
+			).
+			Return(&movie, &score).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					CALL db.index.fulltext.queryNodes('titles', $query)
+					YIELD node AS movie, score
+					RETURN movie, score
+					`,
+			Parameters: map[string]any{
+				"query": "matrix",
+			},
+			Bindings: map[string]reflect.Value{
+				"movie": reflect.ValueOf(&movie),
+				"score": reflect.ValueOf(&score),
+			},
+		})
+	})
+
+	t.Run("Call a procedure without yielding", func(t *testing.T) {
+		c := internal.NewCypherClient()
+		cy, err := c.
+			CallProcedure(db.Procedure("db.awaitIndexes", 300)).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					CALL db.awaitIndexes($v1)
+					`,
+			Parameters: map[string]any{
+				"v1": 300,
+			},
+		})
+	})
+
+	t.Run("Error on empty procedure name", func(t *testing.T) {
+		c := internal.NewCypherClient()
+		_, err := c.
+			CallProcedure(db.Procedure("")).
+			Compile()
+		require.Error(t, err)
+	})
+	t.Run("Query a full-text index", func(t *testing.T) {
+		var (
+			movie Movie
+			score float64
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			CallProcedure(db.FullTextSearch("titles", "matrix").Yield(&movie, &score)).
+			Return(&movie, &score).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					CALL db.index.fulltext.queryNodes("titles", $v1)
+					YIELD node, score
+					RETURN node, score
+					`,
+			Parameters: map[string]any{
+				"v1": "matrix",
+			},
+			Bindings: map[string]reflect.Value{
+				"node":  reflect.ValueOf(&movie),
+				"score": reflect.ValueOf(&score),
+			},
+		})
+	})
+
+	t.Run("Query a full-text index of relationships", func(t *testing.T) {
+		var (
+			actedIn ActedIn
+			score   float64
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			CallProcedure(db.FullTextSearchRelationships("roles", "neo").Yield(&actedIn, &score)).
+			Return(&actedIn, &score).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					CALL db.index.fulltext.queryRelationships("roles", $v1)
+					YIELD relationship, score
+					RETURN relationship, score
+					`,
+			Parameters: map[string]any{
+				"v1": "neo",
+			},
+			Bindings: map[string]reflect.Value{
+				"relationship": reflect.ValueOf(&actedIn),
+				"score":        reflect.ValueOf(&score),
+			},
+		})
+	})
+	t.Run("Query a vector index", func(t *testing.T) {
+		var (
+			movie Movie
+			score float64
+		)
+		embedding := []float32{0.1, 0.2}
+		c := internal.NewCypherClient()
+		cy, err := c.
+			CallProcedure(db.VectorSearch("plots", embedding, 5).Yield(&movie, &score)).
+			Return(&movie, &score).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					CALL db.index.vector.queryNodes("plots", 5, $v1)
+					YIELD node, score
+					RETURN node, score
+					`,
+			Parameters: map[string]any{
+				"v1": embedding,
+			},
+			Bindings: map[string]reflect.Value{
+				"node":  reflect.ValueOf(&movie),
+				"score": reflect.ValueOf(&score),
+			},
+		})
+	})
 }
@@ -1,14 +1,193 @@
 package tests
 
 import (
+	"context"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/stretchr/testify/require"
 
 	"github.com/rlch/neogo/db"
 	"github.com/rlch/neogo/internal"
 )
 
 func TestCreate(t *testing.T) {
+	t.Run("Validate entities before writing them", func(t *testing.T) {
+		type Account struct {
+			internal.Node `neo4j:"Account"`
+
+			Name string `json:"name" validate:"required,max=8"`
+		}
+		c := internal.NewCypherClient()
+		account := Account{Node: internal.Node{ID: "1"}, Name: "Spongebob"}
+		_, err := c.Create(db.Node(&account)).Compile()
+
+		var verrs internal.ValidationErrors
+		require.ErrorAs(t, err, &verrs)
+		require.Len(t, verrs, 1)
+		require.Equal(t, "name", verrs[0].Property)
+		require.Equal(t, "max", verrs[0].Rule)
+	})
+
+	t.Run("Validate zero entities when the query is run", func(t *testing.T) {
+		type Account struct {
+			internal.Node `neo4j:"Account"`
+
+			Name string `json:"name" validate:"required"`
+		}
+		c := internal.NewCypherClient()
+		var account Account
+		cy, err := c.Create(db.Node(db.Qual(&account, "a"))).Compile()
+		require.NoError(t, err)
+
+		var verrs internal.ValidationErrors
+		require.ErrorAs(t, cy.RunHooks(context.Background()), &verrs)
+		require.Len(t, verrs, 1)
+		require.Equal(t, "name", verrs[0].Property)
+		require.Equal(t, "required", verrs[0].Rule)
+	})
+
+	t.Run("Set defaults of entities when the query is run", func(t *testing.T) {
+		type Account struct {
+			internal.Node `neo4j:"Account"`
+
+			Name string `json:"name"`
+			Plan string `json:"plan" default:"free"`
+		}
+		c := internal.NewCypherClient()
+		account := Account{Name: "Spongebob"}
+		cy, err := c.Create(db.Node(db.Qual(&account, "a"))).Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+				CREATE (a:Account {name: $a_name, plan: $a_plan})
+				`,
+			Parameters: map[string]any{
+				"a_name": "Spongebob",
+				"a_plan": nil,
+			},
+		})
+		require.Empty(t, account.Plan, "defaults are not set when the query is built")
+
+		require.NoError(t, cy.RunHooks(context.Background()))
+		require.Equal(t, "free", account.Plan)
+		require.Equal(t, "free", cy.Parameters["a_plan"])
+	})
+
+	t.Run("Set defaults of zero entities when the query is run", func(t *testing.T) {
+		type Account struct {
+			internal.Node `neo4j:"Account"`
+
+			Key  string `json:"key" default:"ulid()"`
+			Plan string `json:"plan" default:"free"`
+		}
+		c := internal.NewCypherClient()
+		var account Account
+		cy, err := c.Create(db.Node(db.Qual(&account, "a"))).Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+				CREATE (a:Account {key: $a_key, plan: $a_plan})
+				`,
+			Parameters: map[string]any{
+				"a_key":  nil,
+				"a_plan": nil,
+			},
+		})
+
+		require.NoError(t, cy.RunHooks(context.Background()))
+		require.Len(t, account.Key, 26)
+		require.Equal(t, "free", account.Plan)
+		require.Equal(t, account.Key, cy.Parameters["a_key"])
+	})
+
+	t.Run("Fail to run queries whose defaults can't be generated", func(t *testing.T) {
+		type Account struct {
+			internal.Node `neo4j:"Account"`
+
+			Name string `json:"name"`
+			Plan string `json:"plan" default:"plan()"`
+		}
+		c := internal.NewCypherClient()
+		account := Account{Name: "Spongebob"}
+		cy, err := c.Create(db.Node(db.Qual(&account, "a"))).Compile()
+		require.NoError(t, err)
+
+		require.ErrorContains(t, cy.RunHooks(context.Background()), "unknown default function plan")
+	})
+
+	t.Run("Store tagged times as their temporal types", func(t *testing.T) {
+		type Shift struct {
+			internal.Node `neo4j:"Shift"`
+
+			Day   time.Time `json:"day" neogo:"date"`
+			Opens time.Time `json:"opens" neogo:"localtime"`
+		}
+		at := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+		c := internal.NewCypherClient()
+		shift := Shift{Day: at, Opens: at}
+		cy, err := c.Create(db.Node(db.Qual(&shift, "s"))).Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+				CREATE (s:Shift {day: $s_day, opens: $s_opens})
+				`,
+			Parameters: map[string]any{
+				"s_day":   dbtype.Date(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+				"s_opens": dbtype.LocalTime(at),
+			},
+		})
+	})
+
+	t.Run("Write zero fields tagged with keepzero", func(t *testing.T) {
+		type Flag struct {
+			internal.Node `neo4j:"Flag"`
+
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled" neogo:"keepzero"`
+			Rollout int    `json:"rollout"`
+		}
+		c := internal.NewCypherClient()
+		flag := Flag{Name: "beta"}
+		cy, err := c.Create(db.Node(db.Qual(&flag, "f"))).Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+				CREATE (f:Flag {enabled: $f_enabled, name: $f_name})
+				`,
+			Parameters: map[string]any{
+				"f_enabled": false,
+				"f_name":    "beta",
+			},
+		})
+	})
+
+	t.Run("Don't match zero fields tagged with keepzero", func(t *testing.T) {
+		type Flag struct {
+			internal.Node `neo4j:"Flag"`
+
+			Enabled bool `json:"enabled" neogo:"keepzero"`
+		}
+		c := internal.NewCypherClient()
+		flag := Flag{Node: internal.Node{ID: "f1"}}
+		cy, err := c.Match(db.Node(db.Qual(&flag, "f"))).Return(&flag).Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+				MATCH (f:Flag {id: $f_id})
+				RETURN f
+				`,
+			Parameters: map[string]any{
+				"f_id": "f1",
+			},
+			Bindings: map[string]reflect.Value{
+				"f": reflect.ValueOf(&flag),
+			},
+		})
+	})
+
 	t.Run("Create nodes", func(t *testing.T) {
 		t.Run("Create single node", func(t *testing.T) {
 			c := internal.NewCypherClient()
@@ -243,6 +422,39 @@ func TestCreate(t *testing.T) {
 			})
 		})
 
+		t.Run("Create a path with parameters for the properties of each entity", func(t *testing.T) {
+			c := internal.NewCypherClient()
+			a := Person{Name: "Keanu"}
+			r := ActedIn{Role: "Neo"}
+			m := Movie{Title: "The Matrix"}
+			var p any
+			cy, err := c.
+				Create(db.Path(db.Node(db.Qual(&a, "a")).To(db.Qual(&r, "r"), db.Qual(&m, "m")), "p")).
+				Set(db.SetPropValue(&m.Released, 1999)).
+				Return(&a, &r, &m, db.Qual(&p, "p")).
+				Compile()
+
+			Check(t, cy, err, internal.CompiledCypher{
+				Cypher: `
+					CREATE p = (a:Person {name: $a_name})-[r:ACTED_IN {role: $r_role}]->(m:Movie {title: $m_title})
+					SET m.released = $v1
+					RETURN a, r, m, p
+					`,
+				Parameters: map[string]any{
+					"a_name":  a.Name,
+					"r_role":  r.Role,
+					"m_title": m.Title,
+					"v1":      1999,
+				},
+				Bindings: map[string]reflect.Value{
+					"a": reflect.ValueOf(&a),
+					"r": reflect.ValueOf(&r),
+					"m": reflect.ValueOf(&m),
+					"p": reflect.ValueOf(&p),
+				},
+			})
+		})
+
 		t.Run("Create multiple nodes with a parameter for their properties", func(t *testing.T) {
 			c := internal.NewCypherClient()
 			people := []Person{
@@ -28,3 +28,61 @@ func TestCypher(t *testing.T) {
 		},
 	})
 }
+
+func TestEscapedNames(t *testing.T) {
+	type (
+		Part struct {
+			internal.Node `neo4j:"Spare Part"`
+
+			SerialNo string `json:"serial-no"`
+		}
+		HasPart struct {
+			internal.Relationship `neo4j:"HAS-PART"`
+
+			Since int `json:"since"`
+		}
+	)
+
+	t.Run("Escape labels, types and properties of structs", func(t *testing.T) {
+		var (
+			p Part
+			h HasPart
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node("n").To(db.Qual(&h, "h"), db.Qual(&p, "p"))).
+			Where(db.Cond(&p.SerialNo, "=", db.String("A-1"))).
+			Return(&p.SerialNo).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (n)-[h:` + "`HAS-PART`" + `]->(p:` + "`Spare Part`" + `)
+					WHERE p.` + "`serial-no`" + ` = "A-1"
+					RETURN p.` + "`serial-no`" + `
+					`,
+			Bindings: map[string]reflect.Value{
+				"p.`serial-no`": reflect.ValueOf(&p.SerialNo),
+			},
+		})
+	})
+
+	t.Run("Escape property names in patterns", func(t *testing.T) {
+		p := Part{SerialNo: "A-1"}
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Create(db.Node(db.Qual(&p, "p"))).
+			Merge(db.Node(db.Var("q", db.Props{"first name": db.String("Ada")}))).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					CREATE (p:` + "`Spare Part`" + ` {` + "`serial-no`" + `: $p_serial_no})
+					MERGE (q {` + "`first name`" + `: "Ada"})
+					`,
+			Parameters: map[string]any{
+				"p_serial_no": "A-1",
+			},
+		})
+	})
+}
@@ -1,9 +1,12 @@
 package tests
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/rlch/neogo/db"
 	"github.com/rlch/neogo/internal"
 )
@@ -419,6 +422,108 @@ func TestMerge(t *testing.T) {
 		})
 	})
 
+	t.Run("Upsert properties with merge strategies", func(t *testing.T) {
+		type article struct {
+			internal.Node `neo4j:"Article"`
+
+			Title     string   `json:"title"`
+			Views     int      `json:"views" neogo:"oncreate"`
+			UpdatedAt string   `json:"updatedAt" neogo:"onmatch"`
+			Tags      []string `json:"tags" neogo:"append"`
+		}
+		update := article{
+			Title:     "Graphs",
+			UpdatedAt: "2024-01-02",
+			Tags:      []string{"neo4j"},
+		}
+		update.ID = "a1"
+		var a article
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Merge(
+				db.Node(db.Qual(&a, "a", db.Props{"id": db.Param(update.ID)})),
+				db.Upsert(&a, update),
+			).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MERGE (a:Article {id: $v1})
+					ON CREATE
+					  SET a += $v2
+					ON MATCH
+					  SET
+					    a += $v3,
+					    a.tags = coalesce(a.tags, []) + $v4
+					`,
+			Parameters: map[string]any{
+				"v1": "a1",
+				"v2": map[string]any{
+					"id":    "a1",
+					"title": "Graphs",
+					"tags":  []string{"neo4j"},
+				},
+				"v3": map[string]any{
+					"id":        "a1",
+					"title":     "Graphs",
+					"updatedAt": "2024-01-02",
+				},
+				"v4": []string{"neo4j"},
+			},
+		})
+	})
+
+	t.Run("Upsert properties as written by patterns", func(t *testing.T) {
+		type article struct {
+			internal.Node `neo4j:"Article"`
+
+			Title  string `json:"title"`
+			Views  int    `json:"views" neogo:"keepzero"`
+			Status string `json:"status" default:"draft"`
+			Body   string `json:"body" neogo:"external"`
+		}
+		update := article{Title: "Graphs", Body: "..."}
+		update.ID = "a1"
+		var a article
+		cy, err := internal.NewCypherClient().
+			Merge(
+				db.Node(db.Qual(&a, "a", db.Props{"id": db.Param(update.ID)})),
+				db.Upsert(&a, update),
+			).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MERGE (a:Article {id: $v1})
+					ON CREATE
+					  SET a += $v2
+					ON MATCH
+					  SET a += $v3
+					`,
+			Parameters: map[string]any{
+				"v1": "a1",
+				"v2": map[string]any{
+					"id":    "a1",
+					"title": "Graphs",
+					"views": 0,
+					"body":  internal.External{Value: "..."},
+				},
+				"v3": map[string]any{
+					"id":    "a1",
+					"title": "Graphs",
+					"views": 0,
+					"body":  internal.External{Value: "..."},
+				},
+			},
+		})
+		require.Empty(t, update.Status, "defaults are not written to the entity")
+
+		require.NoError(t, cy.RunHooks(context.Background()))
+		require.Equal(t, "draft", cy.Parameters["v2"].(map[string]any)["status"])
+		require.NotContains(t, cy.Parameters["v3"], "status")
+		require.Empty(t, update.Status, "defaults are not written to the entity")
+	})
+
 	t.Run("Using node property uniqueness constraints with MERGE", func(t *testing.T) {
 		// TODO:
 	})
@@ -99,4 +99,30 @@ func TestRemove(t *testing.T) {
 			},
 		})
 	})
+
+	t.Run("Remove a property and a label in one clause", func(t *testing.T) {
+		var n Person
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&n, "n", db.Props{"name": "'Peter'"}))).
+			Remove(
+				db.RemoveProp(&n.Age),
+				db.RemoveLabels(&n, "German"),
+			).
+			Return(&n).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (n:Person {name: 'Peter'})
+					REMOVE
+					  n.age,
+					  n:German
+					RETURN n
+					`,
+			Bindings: map[string]reflect.Value{
+				"n": reflect.ValueOf(&n),
+			},
+		})
+	})
 }
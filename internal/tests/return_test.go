@@ -3,6 +3,9 @@ package tests
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 
 	"github.com/rlch/neogo/db"
 	"github.com/rlch/neogo/internal"
@@ -144,4 +147,170 @@ func TestReturn(t *testing.T) {
 			},
 		})
 	})
+	t.Run("Return function calls", func(t *testing.T) {
+		at := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+		var day time.Time
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Return(
+				db.Qual(db.Bind(db.DateTimeTruncate("day", at), &day), "day"),
+				db.Qual(db.DateTime(nil), "now"),
+			).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					RETURN datetime.truncate("day", $v1) AS day, datetime() AS now
+					`,
+			Parameters: map[string]any{
+				"v1": at,
+			},
+			Bindings: map[string]reflect.Value{
+				"day": reflect.ValueOf(&day),
+			},
+		})
+	})
+	t.Run("Return aggregations", func(t *testing.T) {
+		var (
+			p      Person
+			m      Movie
+			titles []string
+			people int
+			p90    float64
+			stDev  float64
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p")).To(db.Var(nil, db.Label("ACTED_IN")), db.Qual(&m, "m"))).
+			Return(
+				db.CollectDistinct(&m.Title, &titles),
+				db.Qual(db.CountDistinct(&p, &people), "people"),
+				db.PercentileCont(&m.Released, 0.9, &p90),
+				db.StDev(&m.Released, &stDev),
+			).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)-[:ACTED_IN]->(m:Movie)
+					RETURN collect(DISTINCT m.title) AS collect, count(DISTINCT p) AS people, percentileCont(m.released, 0.9) AS percentileCont, stDev(m.released) AS stDev
+					`,
+			Bindings: map[string]reflect.Value{
+				"collect":        reflect.ValueOf(&titles),
+				"people":         reflect.ValueOf(&people),
+				"percentileCont": reflect.ValueOf(&p90),
+				"stDev":          reflect.ValueOf(&stDev),
+			},
+		})
+	})
+
+	t.Run("Return COLLECT subqueries", func(t *testing.T) {
+		var (
+			p      Person
+			m      Movie
+			titles []string
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(
+				&p.Name,
+				db.Qual(db.CollectSubquery(func(c *internal.CypherClient) *internal.CypherRunner {
+					return c.
+						Match(db.Node("p").To(db.Var(nil, db.Label("ACTED_IN")), db.Qual(&m, "m"))).
+						Where(db.Cond(&m.Released, ">", db.Param(2000))).
+						Return(&m.Title)
+				}, &titles), "titles"),
+			).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					RETURN p.name, COLLECT {
+					  MATCH (p)-[:ACTED_IN]->(m:Movie)
+					  WHERE m.released > $v1
+					  RETURN m.title
+					} AS titles
+					`,
+			Parameters: map[string]any{
+				"v1": 2000,
+			},
+			Bindings: map[string]reflect.Value{
+				"p.name": reflect.ValueOf(&p.Name),
+				"titles": reflect.ValueOf(&titles),
+			},
+		})
+	})
+	t.Run("Return reduce expressions", func(t *testing.T) {
+		var (
+			m       Movie
+			titles  []string
+			longest int
+			joined  string
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&m, "m"))).
+			With(db.Qual(db.CollectDistinct(&m.Title, &titles), "titles")).
+			Return(
+				db.Bind(db.Reduce("longest", 0, "t", &titles, "CASE WHEN size(t) > longest THEN size(t) ELSE longest END"), &longest),
+				db.Qual(db.Bind(db.Reduce("s", db.String(""), "t", &titles, `s + t + ","`), &joined), "joined"),
+			).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (m:Movie)
+					WITH collect(DISTINCT m.title) AS titles
+					RETURN reduce(longest = $v1, t IN titles | CASE WHEN size(t) > longest THEN size(t) ELSE longest END) AS longest, reduce(s = "", t IN titles | s + t + ",") AS joined
+					`,
+			Parameters: map[string]any{
+				"v1": 0,
+			},
+			Bindings: map[string]reflect.Value{
+				"longest": reflect.ValueOf(&longest),
+				"joined":  reflect.ValueOf(&joined),
+			},
+		})
+	})
+	t.Run("Return raw fragments", func(t *testing.T) {
+		var (
+			p    Person
+			name string
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Where(db.Exprf("%s % 2 = 0 AND %s <> %s", "size(p.name)", &p.Surname, db.Param("White"))).
+			Return(
+				db.Qual(db.Bind(db.Exprf("coalesce(%s, $fallback)", &p.Name, db.NamedParam("Anonymous", "fallback")), &name), "name"),
+			).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					WHERE size(p.name) % 2 = 0 AND p.surname <> $v1
+					RETURN coalesce(p.name, $fallback) AS name
+					`,
+			Parameters: map[string]any{
+				"v1":       "White",
+				"fallback": "Anonymous",
+			},
+			Bindings: map[string]reflect.Value{
+				"name": reflect.ValueOf(&name),
+			},
+		})
+	})
+
+	t.Run("Return raw fragments with mismatched arguments", func(t *testing.T) {
+		var p Person
+		c := internal.NewCypherClient()
+		_, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(db.Exprf("coalesce(%s, %s)", &p.Name)).
+			Compile()
+		require.Error(t, err)
+	})
 }
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/rlch/neogo/db"
 	"github.com/rlch/neogo/internal"
 )
@@ -187,6 +189,28 @@ func TestSet(t *testing.T) {
 		})
 	})
 
+	t.Run("Mutate specific properties using a map parameter and +=", func(t *testing.T) {
+		var p Person
+		props := map[string]any{"age": 38, "position": "Entrepreneur"}
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(
+				db.Node(db.Qual(&p, "p", db.Props{"name": "'Peter'"})),
+			).
+			Set(db.SetMerge(&p, db.NamedParam(props, "props"))).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person {name: 'Peter'})
+					SET p += $props
+					`,
+			Parameters: map[string]any{
+				"props": props,
+			},
+		})
+	})
+
 	t.Run("Set multiple properties using one SET clause", func(t *testing.T) {
 		var n Person
 		c := internal.NewCypherClient()
@@ -320,4 +344,40 @@ func TestSet(t *testing.T) {
 			},
 		})
 	})
+
+	t.Run("Escape labels known at runtime", func(t *testing.T) {
+		var n Person
+		add := []string{"Team Lead", "Admin`) DETACH DELETE n //", "`Admin` DETACH DELETE n //`"}
+		remove := []string{"Swedish", "Ex-Employee"}
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&n, "n"))).
+			Set(db.SetLabels(&n, add...)).
+			Remove(db.RemoveLabels(&n, remove...)).
+			Return(&n).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (n:Person)
+					SET n:` + "`Team Lead`:`Admin``) DETACH DELETE n //`:```Admin`` DETACH DELETE n //```" + `
+					REMOVE n:Swedish:` + "`Ex-Employee`" + `
+					RETURN n
+					`,
+			Bindings: map[string]reflect.Value{
+				"n": reflect.ValueOf(&n),
+			},
+		})
+	})
+
+	t.Run("Error on empty label", func(t *testing.T) {
+		var n Person
+		c := internal.NewCypherClient()
+		_, err := c.
+			Match(db.Node(db.Qual(&n, "n"))).
+			Set(db.SetLabels(&n, "")).
+			Return(&n).
+			Compile()
+		require.Error(t, err)
+	})
 }
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+)
+
+func TestUsing(t *testing.T) {
+	t.Run("Index hint", func(t *testing.T) {
+		var p Person
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Using(db.UsingIndex(&p.Name)).
+			Where(db.Cond(&p.Name, "=", "'Tom'")).
+			Return(&p).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					USING INDEX p:Person(name)
+					WHERE p.name = 'Tom'
+					RETURN p
+					`,
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(&p),
+			},
+		})
+	})
+
+	t.Run("Index hint on a relationship", func(t *testing.T) {
+		var (
+			p Person
+			r ActedIn
+			m Movie
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p")).To(db.Qual(&r, "r"), db.Qual(&m, "m"))).
+			Using(db.UsingIndex(&r.Role)).
+			Return(&r).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)-[r:ACTED_IN]->(m:Movie)
+					USING INDEX r:ACTED_IN(role)
+					RETURN r
+					`,
+			Bindings: map[string]reflect.Value{
+				"r": reflect.ValueOf(&r),
+			},
+		})
+	})
+
+	t.Run("Scan and join hints", func(t *testing.T) {
+		var (
+			p Person
+			m Movie
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p")).To(nil, db.Qual(&m, "m"))).
+			Using(
+				db.UsingScan(&p),
+				db.UsingIndex(&m.Title, &m.Released),
+				db.UsingJoinOn(&p, &m),
+			).
+			Return(&m).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)-->(m:Movie)
+					USING SCAN p:Person
+					USING INDEX m:Movie(title, released)
+					USING JOIN ON p, m
+					RETURN m
+					`,
+			Bindings: map[string]reflect.Value{
+				"m": reflect.ValueOf(&m),
+			},
+		})
+	})
+
+	t.Run("Error on index hint across variables", func(t *testing.T) {
+		var (
+			p Person
+			m Movie
+		)
+		c := internal.NewCypherClient()
+		_, err := c.
+			Match(db.Node(db.Qual(&p, "p")).To(nil, db.Qual(&m, "m"))).
+			Using(db.UsingIndex(&p.Name, &m.Title)).
+			Return(&m).
+			Compile()
+		require.Error(t, err)
+	})
+	t.Run("Hints under a namespace", func(t *testing.T) {
+		var p Person
+		c := internal.NewCypherClient()
+		c.SetNamespace("Billing")
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Using(db.UsingScan(&p), db.UsingIndex(&p.Name)).
+			Return(&p).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person:Billing)
+					USING SCAN p:Billing
+					USING INDEX p:Billing(name)
+					RETURN p
+					`,
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(&p),
+			},
+		})
+	})
+
+	t.Run("Error on hints not following a MATCH clause", func(t *testing.T) {
+		var p Person
+		c := internal.NewCypherClient()
+		_, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Where(db.Cond(&p.Name, "=", "'Tom'")).
+			Using(db.UsingIndex(&p.Name)).
+			Return(&p).
+			Compile()
+		require.ErrorContains(t, err, "hints must directly follow a MATCH")
+
+		c = internal.NewCypherClient()
+		_, err = c.
+			Create(db.Node(db.Qual(&p, "p"))).
+			Using(db.UsingScan(&p)).
+			Return(&p).
+			Compile()
+		require.ErrorContains(t, err, "hints must directly follow a MATCH")
+	})
+}
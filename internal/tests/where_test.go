@@ -3,9 +3,14 @@ package tests
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/require"
 
 	"github.com/rlch/neogo/db"
 	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
 )
 
 func TestWhere(t *testing.T) {
@@ -474,6 +479,39 @@ func TestWhere(t *testing.T) {
 			})
 		})
 
+		t.Run("Filter on patterns as EXISTS subqueries", func(t *testing.T) {
+			var (
+				peter  Person
+				person Person
+			)
+			c := internal.NewCypherClient()
+			c.SetFeatureFlags(internal.FeatureFlags{internal.FeatureExistsSubqueries: true})
+			cy, err := c.
+				Match(
+					db.Patterns(
+						db.Node(db.Qual(&person, "person")),
+						db.Node(db.Qual(&peter, "peter", db.Props{"name": "'Peter'"})),
+					),
+				).
+				Where(db.Not(
+					db.Node(&person).To(nil, &peter),
+				)).
+				Return(&person.Name).Compile()
+
+			Check(t, cy, err, internal.CompiledCypher{
+				Cypher: `
+				MATCH
+				  (person:Person),
+				  (peter:Person {name: 'Peter'})
+				WHERE NOT EXISTS { (person)-->(peter) }
+				RETURN person.name
+				`,
+				Bindings: map[string]reflect.Value{
+					"person.name": reflect.ValueOf(&person.Name),
+				},
+			})
+		})
+
 		t.Run("Filter on patterns with properties", func(t *testing.T) {
 			var n Person
 			c := internal.NewCypherClient()
@@ -727,4 +765,288 @@ func TestWhere(t *testing.T) {
 			})
 		})
 	})
+	t.Run("Spatial functions", func(t *testing.T) {
+		type Store struct {
+			internal.Node `neo4j:"Store"`
+
+			Location neo4j.Point2D `json:"location"`
+		}
+		origin := neo4j.Point2D{X: 12.5, Y: 55.7, SpatialRefId: 4326}
+		lowerLeft := neo4j.Point2D{X: 12, Y: 55, SpatialRefId: 4326}
+		upperRight := neo4j.Point2D{X: 13, Y: 56, SpatialRefId: 4326}
+
+		var s Store
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&s, "s"))).
+			Where(db.And(
+				db.Cond(db.PointDistance(&s.Location, origin), "<", "1000"),
+				db.PointWithinBBox(&s.Location, lowerLeft, upperRight),
+			)).
+			Return(&s).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (s:Store)
+					WHERE point.distance(s.location, $v1) < 1000 AND point.withinBBox(s.location, $v2, $v3)
+					RETURN s
+					`,
+			Parameters: map[string]any{
+				"v1": origin,
+				"v2": lowerLeft,
+				"v3": upperRight,
+			},
+			Bindings: map[string]reflect.Value{
+				"s": reflect.ValueOf(&s),
+			},
+		})
+	})
+	t.Run("Temporal functions", func(t *testing.T) {
+		type Event struct {
+			internal.Node `neo4j:"Event"`
+
+			StartsAt time.Time `json:"startsAt"`
+		}
+		since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		var e Event
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&e, "e"))).
+			Where(db.And(
+				db.Cond(&e.StartsAt, ">=", db.DateTime(since)),
+				db.Cond(db.DurationBetween(db.DateTime(nil), &e.StartsAt), "<", db.Duration(90*time.Minute)),
+				db.Cond(db.DateTruncate("month", &e.StartsAt), "=", db.Date("date()")),
+			)).
+			Return(&e).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (e:Event)
+					WHERE e.startsAt >= datetime($v1) AND duration.between(datetime(), e.startsAt) < $v2 AND date.truncate("month", e.startsAt) = date(date())
+					RETURN e
+					`,
+			Parameters: map[string]any{
+				"v1": since,
+				"v2": neo4j.DurationOf(0, 0, 5400, 0),
+			},
+			Bindings: map[string]reflect.Value{
+				"e": reflect.ValueOf(&e),
+			},
+		})
+	})
+	t.Run("Conditional filters", func(t *testing.T) {
+		compile := func(name string, limit string) (*internal.CompiledCypher, error, *Person) {
+			var p Person
+			cy, err := internal.NewCypherClient().
+				Match(db.Node(db.Qual(&p, "p"))).
+				When(name != "", func(c *internal.CypherQuerier) *internal.CypherQuerier {
+					return c.Where(db.Cond(&p.Name, "=", db.NamedParam(name, "name")))
+				}).
+				When(limit != "", func(c *internal.CypherQuerier) *internal.CypherQuerier {
+					return c.With(db.With(&p, db.Limit(limit)))
+				}).
+				Return(&p).
+				Compile()
+			return cy, err, &p
+		}
+
+		cy, err, p := compile("", "")
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					RETURN p
+					`,
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(p),
+			},
+		})
+
+		cy, err, p = compile("Jessie", "10")
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					WHERE p.name = $name
+					WITH p
+					LIMIT 10
+					RETURN p
+					`,
+			Parameters: map[string]any{
+				"name": "Jessie",
+			},
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(p),
+			},
+		})
+	})
+	t.Run("Filter from struct", func(t *testing.T) {
+		type PersonFilter struct {
+			Name    *string   `filter:"p.name"`
+			MinAge  *int      `filter:"p.age,>="`
+			Names   *[]string `filter:"p.name,IN"`
+			Surname *string   `filter:"p.surname,STARTS WITH"`
+			Token   *string   `filter:"-"`
+		}
+		var (
+			p      Person
+			minAge = 18
+			names  = []string{"Jessie", "Alex"}
+			prefix = "Ca"
+			token  = "secret"
+		)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Where(db.FilterFromStruct(&PersonFilter{
+				MinAge:  &minAge,
+				Names:   &names,
+				Surname: &prefix,
+				Token:   &token,
+			})).
+			Return(&p).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					WHERE p.age >= $v1 AND p.name IN $v2 AND p.surname STARTS WITH $v3
+					RETURN p
+					`,
+			Parameters: map[string]any{
+				"v1": minAge,
+				"v2": names,
+				"v3": prefix,
+			},
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(&p),
+			},
+		})
+
+		c = internal.NewCypherClient()
+		cy, err = c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Where(db.FilterFromStruct(PersonFilter{})).
+			Return(&p).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					WHERE true
+					RETURN p
+					`,
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(&p),
+			},
+		})
+	})
+	t.Run("Keyset pagination", func(t *testing.T) {
+		first, err := db.Paginate("", 2, "p.age")
+		require.NoError(t, err)
+		rows := []Person{{Age: 20}, {Age: 31}}
+		cursor, err := db.NextCursor(first, rows, func(p Person) any { return p.Age })
+		require.NoError(t, err)
+		require.NotEmpty(t, cursor)
+
+		var (
+			p      Person
+			people []Person
+		)
+		page, err := db.Paginate(cursor, 2, &p.Age)
+		require.NoError(t, err)
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Where(page.Descending()).
+			Return(db.Return(db.Bind(&p, &people), page)).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					WHERE p.age < $v1
+					RETURN p
+					ORDER BY p.age DESC
+					LIMIT 2
+					`,
+			Parameters: map[string]any{
+				"v1": int64(31),
+			},
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(&people),
+			},
+		})
+
+		last, err := db.NextCursor(page, rows[:1], func(p Person) any { return p.Age })
+		require.NoError(t, err)
+		require.Empty(t, last)
+
+		_, err = db.Paginate("not a cursor", 2, &p.Age)
+		require.ErrorIs(t, err, db.ErrInvalidCursor)
+	})
+	t.Run("String and null predicates", func(t *testing.T) {
+		var p Person
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Where(db.Or(
+				db.And(db.StartsWith(&p.Name, db.Param("Je")), db.EndsWith(&p.Surname, db.Param("man"))),
+				db.And(db.Matches(&p.Position, db.Param("(?i)chem.*")), db.IsNull(&p.Belt)),
+				db.Contains(&p.Name, &p.Surname),
+			)).
+			Return(&p).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					WHERE (p.name STARTS WITH $v1 AND p.surname ENDS WITH $v2) OR (p.position =~ $v3 AND p.belt IS NULL) OR p.name CONTAINS p.surname
+					RETURN p
+					`,
+			Parameters: map[string]any{
+				"v1": "Je",
+				"v2": "man",
+				"v3": "(?i)chem.*",
+			},
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(&p),
+			},
+		})
+	})
+	t.Run("Membership in slice parameters", func(t *testing.T) {
+		var p Person
+		ids := []string{"a", "b"}
+		tuples := []map[string]any{
+			{"name": "Spongebob", "surname": "Squarepants"},
+		}
+		c := internal.NewCypherClient()
+		cy, err := c.
+			Match(db.Node(db.Qual(&p, "p"))).
+			Where(db.And(
+				db.In(&p.ID, ids),
+				db.InTuples(map[string]query.PropertyIdentifier{
+					"surname": &p.Surname,
+					"name":    &p.Name,
+				}, tuples),
+			)).
+			Return(&p).
+			Compile()
+
+		Check(t, cy, err, internal.CompiledCypher{
+			Cypher: `
+					MATCH (p:Person)
+					WHERE p.id IN $v1 AND {name: p.name, surname: p.surname} IN $v2
+					RETURN p
+					`,
+			Parameters: map[string]any{
+				"v1": ids,
+				"v2": tuples,
+			},
+			Bindings: map[string]reflect.Value{
+				"p": reflect.ValueOf(&p),
+			},
+		})
+	})
 }
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"reflect"
+	"time"
+)
+
+// SetClock enables timestamps, which are read from now. See [Stamp].
+func (s *Scope) SetClock(now func() time.Time) {
+	s.clock = now
+}
+
+// Stamp sets the timestamps of the entity identifier refers to to now. Fields
+// of type time.Time named CreatedAt, or tagged with `neogo:"createdAt"`, are
+// set if created is true and they are zero; fields named UpdatedAt, or tagged
+// with `neogo:"updatedAt"`, are always set.
+func Stamp(identifier any, now time.Time, created bool) {
+	entity := lifecycleEntity(identifier)
+	if entity == nil {
+		return
+	}
+	rv := reflect.ValueOf(entity).Elem()
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	var walk func(st reflect.Value)
+	walk = func(st reflect.Value) {
+		t := st.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fv := st.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				walk(fv)
+				continue
+			}
+			switch c, u := timestampOf(f); {
+			case u:
+				fv.Set(reflect.ValueOf(now))
+			case c:
+				if created && fv.IsZero() {
+					fv.Set(reflect.ValueOf(now))
+				}
+			}
+		}
+	}
+	walk(rv)
+}
+
+// timestampOf reports whether f is the creation or the update timestamp of
+// its entity. See [Stamp].
+func timestampOf(f reflect.StructField) (created, updated bool) {
+	if !f.IsExported() || f.Type != timeType {
+		return false, false
+	}
+	updated = f.Name == "UpdatedAt" || hasNeogoOption(f, "updatedAt")
+	created = !updated && (f.Name == "CreatedAt" || hasNeogoOption(f, "createdAt"))
+	return created, updated
+}
+
+// hasTimestamps reports whether entities of type t have timestamps.
+func hasTimestamps(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && hasTimestamps(f.Type) {
+			return true
+		}
+		if c, u := timestampOf(f); c || u {
+			return true
+		}
+	}
+	return false
+}
+
+// stamp sets the timestamps of the entity identifier refers to, if timestamps
+// are enabled.
+func (cy *cypher) stamp(identifier any, created bool) {
+	if cy.clock == nil {
+		return
+	}
+	Stamp(identifier, cy.clock(), created)
+}
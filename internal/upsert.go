@@ -0,0 +1,83 @@
+package internal
+
+import "reflect"
+
+// UpsertProps is the value of a [SetItem] merging the properties of Entity
+// into an entity bound by a MERGE clause, as written by its ON CREATE or ON
+// MATCH actions depending on Created. The properties are read from Entity
+// when the clause is written, and again when the query is run, once Entity is
+// prepared, so they are written as those of entities in patterns are.
+type UpsertProps struct {
+	Entity  any
+	Created bool
+}
+
+// upsertProps returns the parameter of the properties of u.Entity, which is
+// reread, given defaults and stamped when the query is run.
+func (cy *cypher) upsertProps(u *UpsertProps) Param {
+	value, err := cy.readUpsertProps(u, false)
+	if err != nil {
+		panic(err)
+	}
+	param := Param{Value: &value}
+	_, saver := u.Entity.(BeforeSaver)
+	if saver || cy.clock != nil || u.Created && hasDefaults(reflect.TypeOf(u.Entity)) {
+		param.refresh = func() (any, error) { return cy.readUpsertProps(u, true) }
+	}
+	return param
+}
+
+// readUpsertProps returns the properties of u.Entity written when its entity
+// is created, or matched, according to the merge strategies of its fields.
+// Zero fields are omitted unless the scope keeps them. If prepare is set, the
+// entity is given its defaults when created, and stamped, without modifying
+// u.Entity.
+func (cy *cypher) readUpsertProps(u *UpsertProps, prepare bool) (any, error) {
+	v := reflect.ValueOf(u.Entity)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	entity := reflect.New(v.Type())
+	entity.Elem().Set(v)
+	if prepare && u.Created {
+		if err := ApplyDefaults(entity.Interface(), cy.defaultFuncs); err != nil {
+			return nil, err
+		}
+	}
+	if prepare {
+		cy.stamp(entity.Interface(), u.Created)
+	}
+	v = entity.Elem()
+
+	props := map[string]any{}
+	for _, f := range UpsertFields(v.Type()) {
+		switch f.Strategy {
+		case MergeOnCreate, MergeAppend:
+			if !u.Created {
+				continue
+			}
+		case MergeOnMatch:
+			if u.Created {
+				continue
+			}
+		}
+		field := v.Type().FieldByIndex(f.Index)
+		fv := v.FieldByIndex(f.Index)
+		if fv.IsZero() && !cy.keepZero(field, true) {
+			continue
+		}
+		prop := fv.Interface()
+		if temporal, ok := TemporalValue(field, fv); ok {
+			prop = temporal
+		}
+		switch iv := reflect.Indirect(fv); {
+		case !iv.IsValid():
+		case IsEncrypted(field):
+			prop = Encrypted(iv.String())
+		case IsExternal(field):
+			prop = External{Value: iv.Interface()}
+		}
+		props[f.Property] = prop
+	}
+	return props, nil
+}
@@ -0,0 +1,279 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	validateTag = "validate"
+	enumTag     = "enum"
+)
+
+// FieldError describes a field of an entity failing one of the rules of its
+// validate tag.
+type FieldError struct {
+	// Type is the struct type declaring the field.
+	Type reflect.Type
+	// Field is the name of the Go struct field.
+	Field string
+	// Property is the name of the property in Neo4J.
+	Property string
+	// Rule is the rule the field failed, i.e. max.
+	Rule string
+	// Param is the parameter of the rule, i.e. 255 for max=255.
+	Param string
+	// Value is the value of the field.
+	Value any
+}
+
+func (e FieldError) Error() string {
+	rule := e.Rule
+	if e.Param != "" {
+		rule += "=" + e.Param
+	}
+	return fmt.Sprintf("%s.%s (%s) failed %s", e.Type.Name(), e.Field, e.Property, rule)
+}
+
+// ValidationErrors are the fields of an entity failing validation.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the fields of the struct v, including those of embedded
+// structs, against the rules of their validate tags, returning
+// [ValidationErrors] if any fail. Rules are separated by commas:
+//
+//   - required: the field must not be zero.
+//   - min=n, max=n: the length of strings (in runes), slices and maps, or the
+//     value of numbers, must be at least or at most n.
+//   - oneof=a b c: the field must be one of the space-separated values.
+//
+// Rules other than required are skipped for nil pointers. Non-empty string
+// fields with an enum tag, i.e. `enum:"draft,published"`, must also be one of
+// its comma-separated values. Unknown rules and malformed parameters fail
+// with an error other than [ValidationErrors].
+func Validate(v any) error {
+	return validate(v, true)
+}
+
+// ValidateEnums checks the non-empty string fields of the struct v, including
+// those of embedded structs, against their enum tags, returning
+// [ValidationErrors] if any aren't one of their values.
+func ValidateEnums(v any) error {
+	return validate(v, false)
+}
+
+func validate(v any, rules bool) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs ValidationErrors
+	var walk func(st reflect.Value) error
+	walk = func(st reflect.Value) error {
+		t := st.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fv := st.Field(i)
+			name, ok := extractJSONFieldName(f)
+			if !ok {
+				if f.Anonymous && f.Type.Kind() == reflect.Struct {
+					if err := walk(fv); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if values, ok := f.Tag.Lookup(enumTag); ok && !validateEnum(values, fv) {
+				errs = append(errs, FieldError{
+					Type:     t,
+					Field:    f.Name,
+					Property: name,
+					Rule:     "enum",
+					Param:    values,
+					Value:    fv.Interface(),
+				})
+			}
+			tag, ok := f.Tag.Lookup(validateTag)
+			if !ok || !rules {
+				continue
+			}
+			for _, rule := range strings.Split(tag, ",") {
+				rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+				if rule == "" {
+					continue
+				}
+				passed, err := validateRule(rule, param, fv)
+				if err != nil {
+					return fmt.Errorf("invalid validate tag of %s.%s: %w", t.Name(), f.Name, err)
+				}
+				if passed {
+					continue
+				}
+				errs = append(errs, FieldError{
+					Type:     t,
+					Field:    f.Name,
+					Property: name,
+					Rule:     rule,
+					Param:    param,
+					Value:    fv.Interface(),
+				})
+			}
+		}
+		return nil
+	}
+	if err := walk(rv); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateEnum reports whether v, if it is a non-empty string, is one of the
+// comma-separated values.
+func validateEnum(values string, v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String || v.String() == "" {
+		return true
+	}
+	for _, value := range strings.Split(values, ",") {
+		if strings.TrimSpace(value) == v.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRule reports whether v passes rule with param, or returns an error
+// if the rule is unknown or its parameter is malformed.
+func validateRule(rule, param string, v reflect.Value) (bool, error) {
+	if err := checkRule(rule, param); err != nil {
+		return false, err
+	}
+	if rule == "required" {
+		return !v.IsZero(), nil
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return true, nil
+		}
+		v = v.Elem()
+	}
+	switch rule {
+	case "min", "max":
+		limit, _ := strconv.ParseFloat(param, 64)
+		n, ok := measure(v)
+		if !ok {
+			return true, nil
+		}
+		if rule == "min" {
+			return n >= limit, nil
+		}
+		return n <= limit, nil
+	}
+	s := fmt.Sprint(v.Interface())
+	for _, allowed := range strings.Fields(param) {
+		if s == allowed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkRule returns an error if rule is unknown or param is malformed.
+func checkRule(rule, param string) error {
+	switch rule {
+	case "required", "oneof":
+		return nil
+	case "min", "max":
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return fmt.Errorf("invalid parameter of validation rule %s: %q", rule, param)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown validation rule: %s", rule)
+}
+
+// CheckValidateTags returns an error if the validate tags of the fields of the
+// struct type t, including those of embedded structs, have unknown rules or
+// malformed parameters, which would otherwise only fail the first write.
+func CheckValidateTags(t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs []error
+	var walk func(st reflect.Type)
+	walk = func(st reflect.Type) {
+		for i := 0; i < st.NumField(); i++ {
+			f := st.Field(i)
+			if _, ok := extractJSONFieldName(f); !ok {
+				if f.Anonymous && f.Type.Kind() == reflect.Struct {
+					walk(f.Type)
+				}
+				continue
+			}
+			tag, ok := f.Tag.Lookup(validateTag)
+			if !ok || !f.IsExported() {
+				continue
+			}
+			for _, rule := range strings.Split(tag, ",") {
+				rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+				if rule == "" {
+					continue
+				}
+				if err := checkRule(rule, param); err != nil {
+					errs = append(errs, fmt.Errorf("invalid validate tag of %s.%s: %w", st.Name(), f.Name, err))
+				}
+			}
+		}
+	}
+	walk(t)
+	return errors.Join(errs...)
+}
+
+// measure returns the length or value of v compared by the min and max rules.
+func measure(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type validatedBase struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type validatedPerson struct {
+	validatedBase
+
+	Name     string   `json:"name" validate:"required,max=5"`
+	Role     string   `json:"role" validate:"oneof=admin member"`
+	Age      int      `json:"age" validate:"min=0,max=150"`
+	Tags     []string `json:"tags" validate:"max=2"`
+	Nickname *string  `json:"nickname" validate:"min=2"`
+	Notes    string   `json:"notes"`
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("passes valid entities", func(t *testing.T) {
+		require.NoError(t, Validate(&validatedPerson{
+			validatedBase: validatedBase{ID: "1"},
+			Name:          "Zoë",
+			Role:          "admin",
+			Age:           36,
+		}))
+		require.NoError(t, Validate((*validatedPerson)(nil)))
+	})
+
+	t.Run("returns the fields failing rules", func(t *testing.T) {
+		nickname := "x"
+		err := Validate(validatedPerson{
+			Name:     "Alexander",
+			Role:     "owner",
+			Age:      -1,
+			Tags:     []string{"a", "b", "c"},
+			Nickname: &nickname,
+		})
+		var verrs ValidationErrors
+		require.True(t, errors.As(err, &verrs))
+		var failed []string
+		for _, f := range verrs {
+			failed = append(failed, fmt.Sprintf("%s %s %s", f.Property, f.Rule, f.Param))
+		}
+		require.Equal(t, []string{
+			"id required ",
+			"name max 5",
+			"role oneof admin member",
+			"age min 0",
+			"tags max 2",
+			"nickname min 2",
+		}, failed)
+		require.Equal(t, "validatedBase.ID (id) failed required", verrs[0].Error())
+	})
+
+	t.Run("returns errors for unknown rules", func(t *testing.T) {
+		type account struct {
+			Email string `json:"email" validate:"email"`
+		}
+		require.EqualError(t, Validate(account{}), "invalid validate tag of account.Email: unknown validation rule: email")
+		require.EqualError(t, CheckValidateTags(reflect.TypeOf(&account{})), "invalid validate tag of account.Email: unknown validation rule: email")
+		require.NoError(t, CheckValidateTags(reflect.TypeOf(validatedPerson{})))
+	})
+}
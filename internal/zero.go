@@ -0,0 +1,34 @@
+package internal
+
+import "reflect"
+
+// SetIncludeZero sets whether the zero fields of the structs bound in the
+// patterns of CREATE and MERGE clauses, and upserted, are written as
+// properties, rather than omitted. Those of MATCH patterns are always
+// omitted, so they aren't matched against. Either way, zero fields tagged with
+// `neogo:"keepzero"` are written, but not matched against, and zero fields
+// tagged with `neogo:"omitzero"` never are. Structs which are entirely zero
+// have no properties.
+func (s *Scope) SetIncludeZero(include bool) {
+	s.includeZero = include
+}
+
+// keepZero reports whether the field f is written when it is zero. written
+// reports whether its property is written, rather than matched against.
+func (s *Scope) keepZero(f reflect.StructField, written bool) bool {
+	switch {
+	case hasNeogoOption(f, "keepzero"):
+		return written
+	case hasNeogoOption(f, "omitzero"):
+		return false
+	}
+	return s.includeZero && written
+}
+
+// writePatterns calls write, registering patterns whose properties are
+// written rather than matched against.
+func (s *Scope) writePatterns(write func()) {
+	s.writing = true
+	defer func() { s.writing = false }()
+	write()
+}
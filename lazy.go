@@ -0,0 +1,281 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/goccy/go-json"
+
+	"github.com/rlch/neogo/db"
+)
+
+// ErrLazyDetached is returned when loading a [Lazy] association which was not
+// read from the database, and so has no driver to load it with.
+var ErrLazyDetached = errors.New("lazy association is not attached to a driver")
+
+// Lazy is an association to nodes of type T which are only fetched when they
+// are needed. Only the IDs of the related nodes are read with the node
+// holding the association, and the nodes themselves are fetched by Get.
+//
+// IDs are read from a list, for instance by projecting them into the node:
+//
+//	type Person struct {
+//		neogo.Node `neo4j:"Person"`
+//
+//		Friends neogo.Lazy[Person] `json:"friends"`
+//	}
+//
+//	MATCH (p:Person)
+//	RETURN p {.*, friends: [(p)-[:FRIENDS_WITH]->(f:Person) | f.id]} AS p
+//
+// A Lazy is not safe for concurrent use.
+type Lazy[T any] struct {
+	ids    []string
+	driver Driver
+	values []T
+	loaded bool
+}
+
+var _ Valuer[[]any] = (*Lazy[Node])(nil)
+
+// NewLazy creates an association to the nodes with the given IDs, which are
+// loaded with d.
+func NewLazy[T any](d Driver, ids ...string) Lazy[T] {
+	return Lazy[T]{ids: ids, driver: d}
+}
+
+// IDs returns the IDs of the related nodes.
+func (l *Lazy[T]) IDs() []string { return l.ids }
+
+// Loaded reports whether the related nodes have been fetched.
+func (l *Lazy[T]) Loaded() bool { return l.loaded }
+
+// Get returns the related nodes, in the order of their IDs, fetching them
+// with the driver which read the association the first time it is called.
+// Nodes which no longer exist are omitted.
+func (l *Lazy[T]) Get(ctx context.Context) ([]T, error) {
+	if l.loaded {
+		return l.values, nil
+	}
+	if l.driver == nil {
+		return nil, ErrLazyDetached
+	}
+	return l.Load(ctx, l.driver)
+}
+
+// Load fetches the related nodes with d, replacing any previously loaded.
+func (l *Lazy[T]) Load(ctx context.Context, d Driver) ([]T, error) {
+	if len(l.ids) == 0 {
+		l.values, l.loaded = nil, true
+		return nil, nil
+	}
+	var nodes []T
+	err := d.Exec().
+		Match(db.Node(db.Qual(&nodes, "n"))).
+		Where(db.Cond("n.id", "IN", db.Param(l.ids))).
+		Return(&nodes).
+		Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load lazy association: %w", err)
+	}
+	l.fill(l.index(&nodes))
+	return l.values, nil
+}
+
+// newNodes returns a pointer to a slice the related nodes can be read into.
+func (l *Lazy[T]) newNodes() any { return &[]T{} }
+
+// index maps nodes, a pointer to a slice of the related nodes, by their IDs.
+func (l *Lazy[T]) index(nodes any) any {
+	ns := *nodes.(*[]T)
+	byID := make(map[string]T, len(ns))
+	for _, n := range ns {
+		if node, ok := any(&n).(INode); ok {
+			byID[node.GetID()] = n
+		}
+	}
+	return byID
+}
+
+// fill sets the related nodes from byID, returned by index.
+func (l *Lazy[T]) fill(byID any) {
+	nodes := byID.(map[string]T)
+	l.values = make([]T, 0, len(l.ids))
+	for _, id := range l.ids {
+		if n, ok := nodes[id]; ok {
+			l.values = append(l.values, n)
+		}
+	}
+	l.loaded = true
+}
+
+func (l *Lazy[T]) Marshal() (*[]any, error) {
+	ids := make([]any, len(l.ids))
+	for i, id := range l.ids {
+		ids[i] = id
+	}
+	return &ids, nil
+}
+
+func (l *Lazy[T]) Unmarshal(ids *[]any) error {
+	l.ids = make([]string, len(*ids))
+	for i, id := range *ids {
+		s, ok := id.(string)
+		if !ok {
+			return fmt.Errorf("expected string ID, got %T", id)
+		}
+		l.ids[i] = s
+	}
+	l.values, l.loaded = nil, false
+	return nil
+}
+
+func (l Lazy[T]) MarshalJSON() ([]byte, error) {
+	if l.ids == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l.ids)
+}
+
+func (l *Lazy[T]) UnmarshalJSON(data []byte) error {
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return err
+	}
+	l.ids = ids
+	l.values, l.loaded = nil, false
+	return nil
+}
+
+func (l *Lazy[T]) attach(d Driver) {
+	if l.driver == nil {
+		l.driver = d
+	}
+}
+
+type lazyAttacher interface {
+	attach(d Driver)
+}
+
+type lazyLoader interface {
+	lazyAttacher
+	IDs() []string
+	newNodes() any
+	index(nodes any) any
+	fill(byID any)
+}
+
+// PreloadInto loads the [Lazy] association named field of each of parents, a
+// slice of structs or pointers to structs, with a single query run with d. It
+// is a batched alternative to calling [Lazy.Get] on each parent, for
+// associations which weren't loaded up front.
+//
+//	err := neogo.PreloadInto(ctx, d, people, "Friends")
+func PreloadInto(ctx context.Context, d Driver, parents any, field string) error {
+	v := reflect.ValueOf(parents)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("cannot preload %s: parents must be a slice, got %T", field, parents)
+	}
+	var (
+		lazies []lazyLoader
+		ids    []string
+		seen   = map[string]struct{}{}
+	)
+	for i := 0; i < v.Len(); i++ {
+		parent := v.Index(i)
+		for parent.Kind() == reflect.Ptr || parent.Kind() == reflect.Interface {
+			parent = parent.Elem()
+		}
+		if !parent.IsValid() {
+			continue
+		}
+		if parent.Kind() != reflect.Struct {
+			return fmt.Errorf("cannot preload %s: parents must be structs, got %s", field, parent.Type())
+		}
+		f := parent.FieldByName(field)
+		if !f.IsValid() {
+			return fmt.Errorf("cannot preload %s: %s has no such field", field, parent.Type())
+		}
+		if !f.CanAddr() {
+			return fmt.Errorf("cannot preload %s: parents must be addressable", field)
+		}
+		lazy, ok := f.Addr().Interface().(lazyLoader)
+		if !ok {
+			return fmt.Errorf("cannot preload %s: %s.%s is not a Lazy association", field, parent.Type(), field)
+		}
+		lazies = append(lazies, lazy)
+		for _, id := range lazy.IDs() {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(lazies) == 0 {
+		return nil
+	}
+	nodes := lazies[0].newNodes()
+	if len(ids) > 0 {
+		err := d.Exec().
+			Unwind(db.Param(ids), "id").
+			Match(db.Node(db.Qual(nodes, "n", db.Props{"id": "id"}))).
+			Return(nodes).
+			Run(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot preload %s: %w", field, err)
+		}
+	}
+	byID := lazies[0].index(nodes)
+	for _, lazy := range lazies {
+		lazy.attach(d)
+		lazy.fill(byID)
+	}
+	return nil
+}
+
+var (
+	rLazyAttacher = reflect.TypeOf((*lazyAttacher)(nil)).Elem()
+	// lazyFields caches the indices of the Lazy fields of each struct type.
+	lazyFields sync.Map
+)
+
+// attachLazy attaches the driver reading v to the [Lazy] associations of v, so
+// they can be loaded on demand.
+func (r *registry) attachLazy(v reflect.Value) {
+	if r.lazyDriver == nil {
+		return
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !v.CanAddr() {
+		return
+	}
+	for _, i := range lazyFieldsOf(v.Type()) {
+		v.Field(i).Addr().Interface().(lazyAttacher).attach(r.lazyDriver)
+	}
+}
+
+func lazyFieldsOf(t reflect.Type) []int {
+	if fields, ok := lazyFields.Load(t); ok {
+		return fields.([]int)
+	}
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.IsExported() && reflect.PointerTo(f.Type).Implements(rLazyAttacher) {
+			fields = append(fields, i)
+		}
+	}
+	lazyFields.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,105 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+type lazyPerson struct {
+	Node `neo4j:"Person"`
+
+	Name    string             `json:"name"`
+	Friends Lazy[tests.Person] `json:"friends"`
+}
+
+func TestLazy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loads associations on demand", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{
+			"p": neo4j.Node{
+				Labels: []string{"Person"},
+				Props: map[string]any{
+					"id":      "1",
+					"name":    "Jessie",
+					"friends": []any{"3", "2"},
+				},
+			},
+		})
+		var p lazyPerson
+		require.NoError(t, m.Exec().
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Run(ctx))
+		assert.Equal(t, []string{"3", "2"}, p.Friends.IDs())
+		assert.False(t, p.Friends.Loaded())
+
+		m.BindRecords([]map[string]any{
+			{"n": neo4j.Node{Labels: []string{"Person"}, Props: map[string]any{"id": "2", "name": "Walter"}}},
+			{"n": neo4j.Node{Labels: []string{"Person"}, Props: map[string]any{"id": "3", "name": "Saul"}}},
+		})
+		friends, err := p.Friends.Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, friends, 2)
+		assert.Equal(t, "Saul", friends[0].Name)
+		assert.Equal(t, "Walter", friends[1].Name)
+		assert.True(t, p.Friends.Loaded())
+
+		// Loaded associations are not fetched again.
+		friends, err = p.Friends.Get(ctx)
+		require.NoError(t, err)
+		assert.Len(t, friends, 2)
+	})
+
+	t.Run("preloads associations of many parents at once", func(t *testing.T) {
+		m, params := newParamsMock(nil)
+		people := []*lazyPerson{{}, {}, {}}
+		require.NoError(t, people[0].Friends.UnmarshalJSON([]byte(`["2", "3"]`)))
+		require.NoError(t, people[1].Friends.UnmarshalJSON([]byte(`["3"]`)))
+		// The friends are bound in place of the empty record.
+		m.Clear()
+		m.BindRecords([]map[string]any{
+			{"n": neo4j.Node{Labels: []string{"Person"}, Props: map[string]any{"id": "2", "name": "Walter"}}},
+			{"n": neo4j.Node{Labels: []string{"Person"}, Props: map[string]any{"id": "3", "name": "Saul"}}},
+		})
+		require.NoError(t, PreloadInto(ctx, m, people, "Friends"))
+		assert.Equal(t, []any{"2", "3"}, params()["v1"])
+
+		for _, p := range people {
+			assert.True(t, p.Friends.Loaded())
+		}
+		friends, err := people[0].Friends.Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, friends, 2)
+		assert.Equal(t, "Walter", friends[0].Name)
+		assert.Equal(t, "Saul", friends[1].Name)
+		friends, err = people[1].Friends.Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, friends, 1)
+		assert.Equal(t, "Saul", friends[0].Name)
+		friends, err = people[2].Friends.Get(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, friends)
+	})
+
+	t.Run("errors when preloading a field which is not lazy", func(t *testing.T) {
+		m := NewMock()
+		err := PreloadInto(ctx, m, []lazyPerson{{}}, "Name")
+		assert.ErrorContains(t, err, "is not a Lazy association")
+	})
+
+	t.Run("errors when detached", func(t *testing.T) {
+		var p lazyPerson
+		require.NoError(t, p.Friends.UnmarshalJSON([]byte(`["2"]`)))
+		_, err := p.Friends.Get(ctx)
+		assert.ErrorIs(t, err, ErrLazyDetached)
+	})
+}
@@ -0,0 +1,189 @@
+package neogo
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// TxEventKind is the kind of a [TxEvent].
+type TxEventKind int
+
+const (
+	// TxBegin is emitted before the first statement of a transaction runs.
+	TxBegin TxEventKind = iota
+	// TxCommit is emitted once a transaction has been committed.
+	TxCommit
+	// TxRollback is emitted once a transaction has been rolled back, either
+	// explicitly or because it failed.
+	TxRollback
+)
+
+func (k TxEventKind) String() string {
+	switch k {
+	case TxBegin:
+		return "begin"
+	case TxCommit:
+		return "commit"
+	case TxRollback:
+		return "rollback"
+	}
+	return "unknown"
+}
+
+// TxEvent describes a change in the state of a transaction, received by a
+// [TxListener].
+type TxEvent struct {
+	Kind       TxEventKind
+	AccessMode neo4j.AccessMode
+	// Duration is the time elapsed since the transaction began, which is zero
+	// for TxBegin.
+	Duration time.Duration
+	// Statements is the number of statements run in the transaction, which is
+	// zero for TxBegin.
+	Statements int
+	// Labels are the node labels referenced by the statements run in the
+	// transaction, in sorted order, which are empty for TxBegin.
+	Labels []string
+	// ParamBytes estimates the size of the parameters sent by the statements
+	// run in the transaction, which is zero for TxBegin.
+	ParamBytes int64
+	// RecordBytes estimates the size of the records read by the statements
+	// run in the transaction, which is zero for TxBegin. Large values point to
+	// queries returning more properties than they need.
+	RecordBytes int64
+	// Err is the error the transaction failed with, if any.
+	Err error
+	// CorrelationID is the correlation ID of the context the transaction
+	// began with. See [WithCorrelationID].
+	CorrelationID string
+	// Preflight is set for the transactions neogo runs before a query rather
+	// than for it, i.e. to explain the queries guarded by [WithRowGuard].
+	Preflight bool
+}
+
+// TxListener receives the events of every transaction run by a driver,
+// registered with [WithTxListener]. Listeners are called synchronously, and
+// should hand off any slow work.
+type TxListener interface {
+	OnTxEvent(ctx context.Context, e TxEvent)
+}
+
+// TxListenerFunc adapts a function to a [TxListener].
+type TxListenerFunc func(ctx context.Context, e TxEvent)
+
+func (f TxListenerFunc) OnTxEvent(ctx context.Context, e TxEvent) { f(ctx, e) }
+
+// txStats accumulates the statements run in a transaction being listened to.
+type txStats struct {
+	listener    TxListener
+	accessMode  neo4j.AccessMode
+	start       time.Time
+	statements  int
+	labels      map[string]struct{}
+	paramBytes  int64
+	recordBytes int64
+	// correlationID is the correlation ID of the transaction.
+	correlationID string
+	// preflight is set if the transaction is run before a query.
+	preflight bool
+}
+
+// beginTx notifies the transaction listener, if any, that a transaction has
+// begun, returning the stats to record its statements in.
+func (d *driver) beginTx(ctx context.Context, accessMode neo4j.AccessMode) *txStats {
+	if d == nil || d.txListener == nil {
+		return nil
+	}
+	t := &txStats{
+		listener:      d.txListener,
+		accessMode:    accessMode,
+		start:         time.Now(),
+		correlationID: d.correlationID(ctx),
+	}
+	t.preflight, _ = ctx.Value(preflightKey{}).(bool)
+	t.listener.OnTxEvent(ctx, TxEvent{
+		Kind:          TxBegin,
+		AccessMode:    accessMode,
+		CorrelationID: t.correlationID,
+		Preflight:     t.preflight,
+	})
+	return t
+}
+
+// record records the statement cy.
+func (t *txStats) record(cy *internal.CompiledCypher) {
+	if t == nil {
+		return
+	}
+	t.statements++
+	if len(cy.Labels) > 0 && t.labels == nil {
+		t.labels = make(map[string]struct{}, len(cy.Labels))
+	}
+	for _, label := range cy.Labels {
+		t.labels[label] = struct{}{}
+	}
+	t.paramBytes += payloadSize(cy.Parameters)
+}
+
+// recordRows records the records read by a statement.
+func (t *txStats) recordRows(records ...*neo4j.Record) {
+	if t == nil {
+		return
+	}
+	for _, record := range records {
+		t.recordBytes += payloadSize(record)
+	}
+}
+
+// reset discards the statements recorded, when a managed transaction is
+// retried.
+func (t *txStats) reset() {
+	if t == nil {
+		return
+	}
+	t.statements = 0
+	t.labels = nil
+	t.paramBytes = 0
+	t.recordBytes = 0
+}
+
+// end notifies the listener that the transaction has ended with kind.
+func (t *txStats) end(ctx context.Context, kind TxEventKind, err error) {
+	if t == nil {
+		return
+	}
+	var labels []string
+	if len(t.labels) > 0 {
+		labels = make([]string, 0, len(t.labels))
+		for label := range t.labels {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+	}
+	t.listener.OnTxEvent(ctx, TxEvent{
+		Kind:          kind,
+		AccessMode:    t.accessMode,
+		Duration:      time.Since(t.start),
+		Statements:    t.statements,
+		Labels:        labels,
+		ParamBytes:    t.paramBytes,
+		RecordBytes:   t.recordBytes,
+		Err:           err,
+		CorrelationID: t.correlationID,
+		Preflight:     t.preflight,
+	})
+}
+
+// finish ends the transaction with a commit, or a rollback if err is non-nil.
+func (t *txStats) finish(ctx context.Context, err error) {
+	if err != nil {
+		t.end(ctx, TxRollback, err)
+	} else {
+		t.end(ctx, TxCommit, nil)
+	}
+}
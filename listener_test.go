@@ -0,0 +1,108 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+func TestTxListener(t *testing.T) {
+	newDriver := func() (mockDriver, *[]TxEvent) {
+		var events []TxEvent
+		m := NewMock()
+		m.(*mockDriverImpl).txListener = TxListenerFunc(func(ctx context.Context, e TxEvent) {
+			e.Duration = 0
+			events = append(events, e)
+		})
+		return m, &events
+	}
+
+	t.Run("emits events for a single query", func(t *testing.T) {
+		d, events := newDriver()
+		d.Bind(map[string]any{})
+		var p tests.Person
+		err := d.Exec().Create(db.Node(&p)).Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []TxEvent{
+			{Kind: TxBegin, AccessMode: neo4j.AccessModeWrite},
+			{Kind: TxCommit, AccessMode: neo4j.AccessModeWrite, Statements: 1, Labels: []string{"Person"}},
+		}, *events)
+	})
+
+	t.Run("counts statements of a transaction", func(t *testing.T) {
+		d, events := newDriver()
+		d.Bind(map[string]any{})
+		d.Bind(map[string]any{})
+		ctx := context.Background()
+		sess := d.WriteSession(ctx)
+		defer sess.Close(ctx)
+		err := sess.WriteTransaction(ctx, func(begin func() Query) error {
+			var (
+				p tests.Person
+				m tests.Movie
+			)
+			if err := begin().Create(db.Node(&p)).Run(ctx); err != nil {
+				return err
+			}
+			return begin().Merge(db.Node(&m)).Run(ctx)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []TxEvent{
+			{Kind: TxBegin, AccessMode: neo4j.AccessModeWrite},
+			{Kind: TxCommit, AccessMode: neo4j.AccessModeWrite, Statements: 2, Labels: []string{"Movie", "Person"}},
+		}, *events)
+	})
+
+	t.Run("estimates the size of parameters and records", func(t *testing.T) {
+		d, events := newDriver()
+		d.BindRecords([]map[string]any{
+			{"name": "Spongebob"},
+			{"name": "Patrick"},
+		})
+		var names []string
+		err := d.Exec().
+			Match(db.Node(db.Var("p", db.Label("Person")))).
+			Where(db.Cond("p.surname", "=", db.Param("Star"))).
+			Return(db.Qual(&names, "p.name", db.Name("name"))).
+			Run(context.Background())
+		require.NoError(t, err)
+		require.Len(t, *events, 2)
+		assert.Equal(t, int64(len("v1")+len("Star")), (*events)[1].ParamBytes)
+		assert.Equal(t, int64(len("Spongebob")+len("Patrick")), (*events)[1].RecordBytes)
+	})
+
+	t.Run("emits rollback when work fails", func(t *testing.T) {
+		d, events := newDriver()
+		ctx := context.Background()
+		sess := d.ReadSession(ctx)
+		defer sess.Close(ctx)
+		errWork := errors.New("work failed")
+		err := sess.ReadTransaction(ctx, func(begin func() Query) error {
+			return errWork
+		})
+		require.ErrorIs(t, err, errWork)
+		assert.Equal(t, []TxEvent{
+			{Kind: TxBegin, AccessMode: neo4j.AccessModeRead},
+			{Kind: TxRollback, AccessMode: neo4j.AccessModeRead, Err: errWork},
+		}, *events)
+	})
+}
+
+func TestPayloadSize(t *testing.T) {
+	assert.Equal(t, int64(1), payloadSize(nil))
+	assert.Equal(t, int64(3+8+1), payloadSize([]any{"abc", 42, true}))
+	assert.Equal(t, int64(len("name")+len("Ada")), payloadSize(map[string]any{"name": "Ada"}))
+	assert.Equal(t, int64(len("Person")+len("name")+len("Ada")+len("4:a:1")), payloadSize(neo4j.Node{
+		Labels:    []string{"Person"},
+		Props:     map[string]any{"name": "Ada"},
+		ElementId: "4:a:1",
+	}))
+	assert.Equal(t, int64(len("Name")+len("Ada")), payloadSize(struct{ Name string }{"Ada"}))
+}
@@ -0,0 +1,65 @@
+package neogo
+
+import (
+	"bytes"
+
+	"github.com/goccy/go-json"
+)
+
+// Maybe is a property which tells apart being absent from being null when it
+// is read, i.e. for PATCH-style workflows which need to know whether a value
+// was ever set.
+//
+//	type Person struct {
+//		neogo.Node `neo4j:"Person"`
+//
+//		Nickname neogo.Maybe[string] `json:"nickname"`
+//	}
+//
+// Reading a Person without a nickname property leaves Present false, whereas
+// a null nickname sets Present without Valid. When written, absent Maybes are
+// left out of the properties of patterns, and null ones are written as null.
+type Maybe[T any] struct {
+	// Value is the value of the property, if it is Valid.
+	Value T
+	// Valid is true if the property is non-null.
+	Valid bool
+	// Present is true if the property was read, even if it was null.
+	Present bool
+}
+
+// Some returns a present, non-null Maybe of v.
+func Some[T any](v T) Maybe[T] {
+	return Maybe[T]{Value: v, Valid: true, Present: true}
+}
+
+// Null returns a present, null Maybe.
+func Null[T any]() Maybe[T] {
+	return Maybe[T]{Present: true}
+}
+
+// Get returns the value of m and whether it is non-null.
+func (m Maybe[T]) Get() (T, bool) {
+	return m.Value, m.Valid
+}
+
+// UnmarshalJSON is only called for properties which are present.
+func (m *Maybe[T]) UnmarshalJSON(b []byte) error {
+	var zero T
+	m.Value, m.Valid, m.Present = zero, false, true
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(b, &m.Value); err != nil {
+		return err
+	}
+	m.Valid = true
+	return nil
+}
+
+func (m Maybe[T]) MarshalJSON() ([]byte, error) {
+	if !m.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(m.Value)
+}
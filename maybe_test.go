@@ -0,0 +1,67 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+)
+
+type patchedPerson struct {
+	Node `neo4j:"Person"`
+
+	Name     string        `json:"name"`
+	Nickname Maybe[string] `json:"nickname"`
+	Age      Maybe[int]    `json:"age"`
+}
+
+func TestMaybe(t *testing.T) {
+	t.Run("tells apart absent and null properties", func(t *testing.T) {
+		d := NewMock()
+		d.Bind(map[string]any{"p": neo4j.Node{
+			Labels: []string{"Person"},
+			Props:  map[string]any{"name": "Ada", "nickname": nil},
+		}})
+		var p patchedPerson
+		err := d.Exec().
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, Null[string](), p.Nickname)
+		assert.Equal(t, Maybe[int]{}, p.Age)
+
+		d.Bind(map[string]any{"p": neo4j.Node{
+			Labels: []string{"Person"},
+			Props:  map[string]any{"name": "Ada", "age": int64(36)},
+		}})
+		p = patchedPerson{}
+		err = d.Exec().
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Run(context.Background())
+		require.NoError(t, err)
+		age, ok := p.Age.Get()
+		assert.True(t, ok)
+		assert.Equal(t, 36, age)
+		assert.False(t, p.Nickname.Present)
+	})
+
+	t.Run("writes only present properties", func(t *testing.T) {
+		p := patchedPerson{Name: "Ada", Nickname: Null[string](), Age: Some(36)}
+		cy, err := (&session{}).newClient(internal.NewCypherClient()).
+			Create(db.Node(db.Qual(&p, "p"))).(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, "CREATE (p:Person {age: $p_age, name: $p_name, nickname: $p_nickname})", cy.Cypher)
+
+		canon, err := canonicalizeParams(cy.Parameters)
+		require.NoError(t, err)
+		assert.Nil(t, canon["p_nickname"])
+		assert.EqualValues(t, 36, canon["p_age"])
+	})
+}
@@ -0,0 +1,150 @@
+package neogo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// MergeRelationship writes MERGE clauses upserting rel from the node from to
+// the node to. Each node is merged by the properties named by mergeKeys, which
+// default to its key properties if it is [Keyed], such as a [TenantNode], or
+// its ID otherwise, and created with all of its properties if it doesn't
+// exist. The relationship is merged between them, and created with all of its
+// properties if it doesn't exist.
+//
+//	neogo.MergeRelationship(d.Exec(), &person, &actedIn, &movie).
+//		Return(&person, &actedIn, &movie)
+//
+//	MERGE (person:Person {id: $v1})
+//	ON CREATE
+//	  SET person += $v2
+//	MERGE (movie:Movie {id: $v3})
+//	ON CREATE
+//	  SET movie += $v4
+//	MERGE (person)-[actedIn:ACTED_IN]->(movie)
+//	ON CREATE
+//	  SET actedIn += $v5
+//	RETURN person, actedIn, movie
+//
+// Fields tagged `neogo:"onmatch"` are not written when creating entities. The
+// hooks of entities implementing [BeforeSaver] are called when the query is
+// run, and their properties are read again afterwards. from, rel and to are
+// bound to the nodes and relationship, so they can be used in subsequent
+// clauses. Entities failing their validate tags, and merge keys naming no
+// property, fail the query when it is compiled or run.
+func MergeRelationship[F, R, T any](c Query, from *F, rel *R, to *T, mergeKeys ...string) query.Querier {
+	// Entities are bound zeroed below, so they are validated here.
+	for _, entity := range []any{from, rel, to} {
+		if err := internal.ValidateSaved(entity); err != nil {
+			return failQuery(c, err)
+		}
+	}
+	fromKeys, fromProps, err := mergeKeyProps(from, keysOf(from, mergeKeys))
+	if err != nil {
+		return failQuery(c, err)
+	}
+	toKeys, toProps, err := mergeKeyProps(to, keysOf(to, mergeKeys))
+	if err != nil {
+		return failQuery(c, err)
+	}
+	_, relProps, _ := mergeKeyProps(rel, nil)
+
+	var q query.Querier
+	// Entities are bound while zeroed, so that their properties aren't injected
+	// into the patterns, which only match their keys.
+	bindZeroed(func() {
+		q = c.
+			Merge(
+				db.Node(db.Var(from, fromKeys)),
+				db.OnCreate(db.SetMerge(from, fromProps)),
+			).
+			Merge(
+				db.Node(db.Var(to, toKeys)),
+				db.OnCreate(db.SetMerge(to, toProps)),
+			).
+			Merge(
+				db.Node(from).To(rel, to),
+				db.OnCreate(db.SetMerge(rel, relProps)),
+			)
+	}, from, rel, to)
+	return q
+}
+
+// keysOf returns the properties entity is merged by: keys if given, or its key
+// properties.
+func keysOf(entity any, keys []string) []string {
+	if len(keys) > 0 {
+		return keys
+	}
+	if k, ok := entity.(Keyed); ok {
+		return k.KeyProperties()
+	}
+	return []string{"id"}
+}
+
+// failQuery returns c failing with err when it is compiled or run.
+func failQuery(c Query, err error) query.Querier {
+	return c.Eval(failedExpr{err})
+}
+
+// failedExpr adds its error to the query it is evaluated in, writing nothing.
+type failedExpr struct{ err error }
+
+func (e failedExpr) Compile(s query.Scope, _ *strings.Builder) { s.AddError(e.err) }
+
+// mergeKeyProps returns the properties of entity named by keys, and the
+// parameter of those written when it is created. Both are read again once the
+// BeforeSave hook of entity is called. It fails if entity has no property
+// named by one of keys.
+func mergeKeyProps(entity any, keys []string) (db.Props, internal.Param, error) {
+	v := reflect.ValueOf(entity).Elem()
+	fields := internal.UpsertFields(v.Type())
+	byProperty := make(map[string]reflect.Value, len(fields))
+	for _, f := range fields {
+		byProperty[f.Property] = v.FieldByIndex(f.Index)
+	}
+	for _, key := range keys {
+		if _, ok := byProperty[key]; !ok {
+			return nil, internal.Param{}, fmt.Errorf("MergeRelationship: %s has no property %q", v.Type(), key)
+		}
+	}
+	readKeys := func() map[string]any {
+		keyValues := make(map[string]any, len(keys))
+		for _, key := range keys {
+			keyValues[key] = byProperty[key].Interface()
+		}
+		return internal.EncryptProps(v.Type(), keyValues)
+	}
+	readProps := func() any {
+		props := make(map[string]any, len(fields))
+		for _, f := range fields {
+			if f.Strategy != internal.MergeOnMatch {
+				props[f.Property] = byProperty[f.Property].Interface()
+			}
+		}
+		return props
+	}
+	keyProps := make(db.Props, len(keys))
+	for key, value := range readKeys() {
+		keyProps[key] = internal.EntityParam(entity, value, func() any { return readKeys()[key] })
+	}
+	return keyProps, internal.EntityParam(entity, readProps(), readProps), nil
+}
+
+// bindZeroed calls bind while the values pointed to by ptrs are zeroed,
+// restoring them afterwards.
+func bindZeroed(bind func(), ptrs ...any) {
+	for _, ptr := range ptrs {
+		v := reflect.ValueOf(ptr).Elem()
+		saved := reflect.New(v.Type()).Elem()
+		saved.Set(v)
+		v.SetZero()
+		defer v.Set(saved)
+	}
+	bind()
+}
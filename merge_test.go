@@ -0,0 +1,124 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+func TestMergeRelationship(t *testing.T) {
+	person := tests.Person{Name: "Keanu"}
+	person.ID = "p1"
+	movie := tests.Movie{Title: "The Matrix"}
+	movie.ID = "m1"
+	actedIn := tests.ActedIn{Role: "Neo"}
+
+	cy, err := MergeRelationship((&session{}).newClient(internal.NewCypherClient()), &person, &actedIn, &movie).
+		Return(&person, &actedIn, &movie).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, `MERGE (person:Person {id: $v1})
+ON CREATE
+  SET person += $v2
+MERGE (movie:Movie {id: $v3})
+ON CREATE
+  SET movie += $v4
+MERGE (person)-[actedIn:ACTED_IN]->(movie)
+ON CREATE
+  SET actedIn += $v5
+RETURN person, actedIn, movie`, cy.Cypher)
+	require.Equal(t, "p1", cy.Parameters["v1"])
+	require.Equal(t, "m1", cy.Parameters["v3"])
+	require.Equal(t, map[string]any{"role": "Neo"}, cy.Parameters["v5"])
+	require.Equal(t, "Keanu", cy.Parameters["v2"].(map[string]any)["name"])
+	require.Equal(t, "Keanu", person.Name, "entities are restored after binding")
+
+	_, err = MergeRelationship((&session{}).newClient(internal.NewCypherClient()), &person, &actedIn, &movie, "handle").(baseRunner).GetRunner().Compile()
+	require.ErrorContains(t, err, `MergeRelationship: tests.Person has no property "handle"`)
+}
+
+type tenantInvoice struct {
+	TenantNode `neo4j:"Invoice"`
+
+	Total int `json:"total"`
+}
+
+type tenantCustomer struct {
+	TenantNode `neo4j:"Customer"`
+
+	Name string `json:"name"`
+}
+
+type billedTo struct {
+	Relationship `neo4j:"BILLED_TO"`
+}
+
+func TestMergeRelationshipByTenant(t *testing.T) {
+	invoice := tenantInvoice{Total: 100}
+	invoice.ID, invoice.TenantID = "i1", "acme"
+	customer := tenantCustomer{Name: "Ada"}
+	customer.ID, customer.TenantID = "c1", "acme"
+	assert.Equal(t, []string{"Invoice"}, internal.ExtractNodeLabels(&invoice))
+
+	q := MergeRelationship((&session{}).newClient(internal.NewCypherClient()), &invoice, &billedTo{}, &customer)
+	cy, err := q.(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, `MERGE (invoice:Invoice {id: $v1, tenantId: $v2})
+ON CREATE
+  SET invoice += $v3
+MERGE (customer:Customer {id: $v4, tenantId: $v5})
+ON CREATE
+  SET customer += $v6
+MERGE (invoice)-[billedTo:BILLED_TO]->(customer)
+ON CREATE
+  SET billedTo += $v7`, cy.Cypher)
+	require.Equal(t, "acme", cy.Parameters["v2"])
+	require.Equal(t, "acme", cy.Parameters["v3"].(map[string]any)["tenantId"])
+}
+
+func TestMergeRelationshipHooks(t *testing.T) {
+	m, params := newParamsMock(nil)
+	ada := hookedPerson{Name: "Ada"}
+	grace := hookedPerson{Name: "Grace"}
+
+	// The hooks set the slugs the nodes are merged by when the query is run.
+	ctx := context.WithValue(context.Background(), slugSuffixKey{}, "1")
+	require.NoError(t, MergeRelationship(m.Exec(), &ada, &tests.ActedIn{Role: "Neo"}, &grace, "slug").Run(ctx))
+	assert.Equal(t, "ada-1", params()["v1"])
+	assert.Equal(t, "ada-1", params()["v2"].(map[string]any)["slug"])
+	assert.Equal(t, "grace-1", params()["v3"])
+	assert.Equal(t, 1, ada.saves)
+}
+
+func TestMergeRelationshipErrors(t *testing.T) {
+	type validatedPerson struct {
+		Node `neo4j:"Person"`
+
+		Name string `json:"name" validate:"required"`
+	}
+	ctx := context.Background()
+	movie := tests.Movie{Title: "The Matrix"}
+	movie.ID = "m1"
+
+	t.Run("fails the query with invalid entities", func(t *testing.T) {
+		m := NewMock()
+		p := validatedPerson{Node: Node{ID: "p1"}}
+		err := MergeRelationship(m.Exec(), &p, &tests.ActedIn{}, &movie).
+			Return(&p).
+			Run(ctx)
+		var verrs ValidationErrors
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, "name", verrs[0].Property)
+	})
+
+	t.Run("fails the query with unknown merge keys", func(t *testing.T) {
+		m := NewMock()
+		p := validatedPerson{Node: Node{ID: "p1"}, Name: "Keanu"}
+		err := MergeRelationship(m.Exec(), &p, &tests.ActedIn{}, &movie, "handle").Run(ctx)
+		assert.ErrorContains(t, err, `has no property "handle"`)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/url"
+	"reflect"
 
 	"github.com/goccy/go-json"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
@@ -15,20 +16,28 @@ import (
 // NewMock creates a mock neogo [Driver] for testing.
 func NewMock() mockDriver {
 	m := &mockBindings{}
+	d := &driver{
+		db: &mockNeo4jDriver{
+			mockBindings: m,
+		},
+		sessionSemaphore: semaphore.NewWeighted(100), // Default semaphore for testing
+		bookmarks:        newBookmarkCache(),
+	}
+	d.lazyDriver = d
 	return &mockDriverImpl{
 		mockBindings: m,
-		driver: &driver{
-			db: &mockNeo4jDriver{
-				mockBindings: m,
-			},
-			sessionSemaphore: semaphore.NewWeighted(100), // Default semaphore for testing
-		},
+		driver:       d,
 	}
 }
 
 type (
 	mockBindings struct {
 		Current *mockBindingsNode
+		// Commits and Rollbacks count the explicit transactions which were
+		// committed and rolled back.
+		Commits, Rollbacks int
+		// Runs are the contexts the statements were run with.
+		Runs []context.Context
 	}
 	mockBindingsNode struct {
 		Single  map[string]any
@@ -58,6 +67,9 @@ type (
 		*mockBindings
 		neo4j.ManagedTransaction
 	}
+	mockNeo4jExplicitTx struct {
+		mockNeo4jTx
+	}
 	mockNeo4jResult struct {
 		neo4j.ResultWithContext
 		records []*neo4j.Record
@@ -143,17 +155,29 @@ func (s *mockNeo4jSession) LastBookmarks() neo4j.Bookmarks {
 }
 
 func (s *mockNeo4jSession) BeginTransaction(ctx context.Context, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ExplicitTransaction, error) {
-	panic(errors.New("not implemented"))
+	return &mockNeo4jExplicitTx{mockNeo4jTx{mockBindings: s.mockBindings}}, nil
+}
+
+func (t *mockNeo4jExplicitTx) Commit(ctx context.Context) error {
+	t.Commits++
+	return nil
+}
+
+func (t *mockNeo4jExplicitTx) Rollback(ctx context.Context) error {
+	t.Rollbacks++
+	return nil
+}
+
+func (t *mockNeo4jExplicitTx) Close(ctx context.Context) error {
+	return nil
 }
 
 func (s *mockNeo4jSession) ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (any, error) {
-	_, err := work(&mockNeo4jTx{mockBindings: s.mockBindings})
-	return nil, err
+	return work(&mockNeo4jTx{mockBindings: s.mockBindings})
 }
 
 func (s *mockNeo4jSession) ExecuteWrite(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (any, error) {
-	_, err := work(&mockNeo4jTx{mockBindings: s.mockBindings})
-	return nil, err
+	return work(&mockNeo4jTx{mockBindings: s.mockBindings})
 }
 
 func (s *mockNeo4jSession) Run(ctx context.Context, cypher string, params map[string]any, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ResultWithContext, error) {
@@ -165,6 +189,7 @@ func (s *mockNeo4jSession) Close(ctx context.Context) error {
 }
 
 func (t *mockNeo4jTx) Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error) {
+	t.Runs = append(t.Runs, ctx)
 	r := &mockNeo4jResult{}
 	toRecord := func(m map[string]any) (*neo4j.Record, error) {
 		n := len(m)
@@ -186,6 +211,7 @@ func (t *mockNeo4jTx) Run(ctx context.Context, cypher string, params map[string]
 				if err != nil {
 					return nil, err
 				}
+				props = internal.EncodeProtobuf(reflect.TypeOf(v), props)
 				rec.Values[i] = neo4j.Node{
 					Labels: labels,
 					Props:  props,
@@ -201,6 +227,7 @@ func (t *mockNeo4jTx) Run(ctx context.Context, cypher string, params map[string]
 				if err != nil {
 					return nil, err
 				}
+				props = internal.EncodeProtobuf(reflect.TypeOf(v), props)
 				rec.Values[i] = neo4j.Relationship{
 					Type:  typ,
 					Props: props,
@@ -290,9 +317,24 @@ func (r *mockNeo4jResult) Single(ctx context.Context) (*neo4j.Record, error) {
 }
 
 func (r *mockNeo4jResult) Consume(ctx context.Context) (neo4j.ResultSummary, error) {
-	panic(errors.New("not implemented"))
+	return mockNeo4jSummary{}, nil
+}
+
+// mockNeo4jSummary is the summary of a mocked query, which makes no updates.
+type mockNeo4jSummary struct {
+	neo4j.ResultSummary
 }
 
+func (mockNeo4jSummary) Counters() neo4j.Counters { return mockNeo4jCounters{} }
+
+func (mockNeo4jSummary) Plan() neo4j.Plan { return nil }
+
+type mockNeo4jCounters struct {
+	neo4j.Counters
+}
+
+func (mockNeo4jCounters) ContainsUpdates() bool { return false }
+
 func (r *mockNeo4jResult) IsOpen() bool {
 	return true
 }
@@ -29,6 +29,26 @@ func newHybridDriver(t *testing.T, ctx context.Context) (d Driver, m mockDriver)
 	return
 }
 
+// newParamsMock returns a mock driver, set up by configure if not nil, which
+// binds an empty record to its first query, and a function returning the
+// parameters sent by its last query.
+func newParamsMock(configure func(d *driver)) (mockDriver, func() map[string]any) {
+	m := NewMock()
+	impl := m.(*mockDriverImpl)
+	if configure != nil {
+		configure(impl.driver)
+	}
+	var params map[string]any
+	impl.paramTransformers = append(impl.paramTransformers, ParamTransformerFunc(
+		func(_ context.Context, p map[string]any) (map[string]any, error) {
+			params = p
+			return p, nil
+		},
+	))
+	m.Bind(map[string]any{})
+	return m, func() map[string]any { return params }
+}
+
 func TestMockDriver(t *testing.T) {
 	ctx := context.Background()
 
@@ -57,3 +77,51 @@ func TestMockDriver(t *testing.T) {
 		require.Equal(t, "value", result)
 	})
 }
+
+type countingDriver struct {
+	DriverWrapper
+	execs int
+}
+
+func (d *countingDriver) Exec(configurers ...ExecOption) Query {
+	d.execs++
+	return d.DriverWrapper.Exec(configurers...)
+}
+
+func TestDriverWrapper(t *testing.T) {
+	ctx := context.Background()
+	m := NewMock()
+	m.Bind(map[string]any{"n": 1})
+	d := &countingDriver{DriverWrapper: DriverWrapper{m}}
+
+	var base BaseDriver = d
+	var n int
+	require.NoError(t, base.Exec().Return(db.Qual(&n, "1", db.Name("n"))).Run(ctx))
+	require.Equal(t, 1, n)
+	require.Equal(t, 1, d.execs)
+
+	var _ Driver = d
+	require.Equal(t, Driver(m), d.Unwrap())
+	require.NoError(t, base.Close(ctx))
+}
+
+func TestDriverFunctions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unwraps decorated drivers", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{"version": "5.12"})
+		d := &countingDriver{DriverWrapper: DriverWrapper{m}}
+		require.NoError(t, RequireSchema(ctx, d, RequireAPOC("5.12")))
+		require.Equal(t, 1, d.execs, "queries are run with the decorator")
+		require.Same(t, driverOf(m), driverOf(d))
+	})
+
+	t.Run("fails on other drivers", func(t *testing.T) {
+		var d struct{ Driver }
+		require.Nil(t, driverOf(d))
+		require.True(t, IsDirty(d, &struct{}{}))
+		require.ErrorIs(t, RequireSchema(ctx, d), ErrUnsupportedDriver)
+		require.ErrorIs(t, WaitForIndexes(ctx, d, 0), ErrUnsupportedDriver)
+	})
+}
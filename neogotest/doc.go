@@ -0,0 +1,16 @@
+/*
+Package neogotest provides assertions for testing the queries built with
+neogo.
+
+[AssertParams] compares the parameters generated for a query with those
+expected, normalizing both as neogo does when it runs the query, so tests can
+state their intent rather than the exact parameter values:
+
+	var p Person
+	q := d.Exec().Create(db.Node(db.Qual(&p, "p")))
+	neogotest.AssertParams(t, q, map[string]any{"p_name": "Ada", "p_age": 36})
+
+Parameters missing from either side are differences, unless [IgnoreZero] is
+given and the other side is zero.
+*/
+package neogotest
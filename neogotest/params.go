@@ -0,0 +1,231 @@
+package neogotest
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+type compilable interface {
+	GetRunner() *internal.CypherRunner
+}
+
+type (
+	// Option configures how [AssertParams] compares parameters.
+	Option func(*comparer)
+
+	comparer struct {
+		ignoreZero bool
+	}
+)
+
+// IgnoreZero is an [Option] for [AssertParams] under which zero values equal
+// missing parameters and properties, so want needn't state whether zero
+// fields are written.
+func IgnoreZero() Option {
+	return func(c *comparer) {
+		c.ignoreZero = true
+	}
+}
+
+// AssertParams compiles runner without running it, and reports an error to t
+// unless its parameters equal want, returning whether they do. Both are
+// normalized before they are compared:
+//
+//   - Pointers are dereferenced, so &name equals name, and named string, bool
+//     and number types are compared as their underlying values.
+//   - Structs are compared as the properties they are written as, named by
+//     their json tags.
+//   - Integers and integral floats are compared by value, so int32(1) equals
+//     int64(1) and 1.0.
+//
+// Temporal and spatial values are compared as they are.
+func AssertParams(t testing.TB, runner query.Runner, want map[string]any, opts ...Option) bool {
+	t.Helper()
+	var c comparer
+	for _, opt := range opts {
+		opt(&c)
+	}
+	r, ok := runner.(compilable)
+	if !ok {
+		t.Errorf("cannot compile %T", runner)
+		return false
+	}
+	cy, err := r.GetRunner().Compile()
+	if err != nil {
+		t.Errorf("cannot compile query: %v", err)
+		return false
+	}
+	if diff := c.diffParams(normalize(cy.Parameters), normalize(want)); diff != "" {
+		t.Errorf("parameters differ (-want +got):\n%s", diff)
+		return false
+	}
+	return true
+}
+
+// diffParams returns a line for each parameter which differs between want and
+// got, in sorted order, or an empty string if they are equal.
+func (c comparer) diffParams(got, want any) string {
+	gotParams, _ := got.(map[string]any)
+	wantParams, _ := want.(map[string]any)
+	keys := make([]string, 0, len(gotParams)+len(wantParams))
+	for k := range wantParams {
+		keys = append(keys, k)
+	}
+	for k := range gotParams {
+		if _, ok := wantParams[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		w, inWant := wantParams[k]
+		g, inGot := gotParams[k]
+		switch {
+		case c.equal(w, g):
+		case !inGot:
+			fmt.Fprintf(&b, "  -%s: %#v\n", k, w)
+		case !inWant:
+			fmt.Fprintf(&b, "  +%s: %#v\n", k, g)
+		default:
+			fmt.Fprintf(&b, "  -%s: %#v\n  +%s: %#v\n", k, w, k, g)
+		}
+	}
+	return b.String()
+}
+
+// normalize returns v as it would be written as a parameter, for comparison.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case internal.Encrypted:
+		return string(v)
+	case internal.External:
+		return normalize(v.Value)
+	case internal.Param:
+		return normalize(v.Value)
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if internal.IsDBType(rv.Type()) {
+		return rv.Interface()
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() <= math.MaxInt64 {
+			return int64(rv.Uint())
+		}
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return int64(f)
+		}
+		return f
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface()
+		}
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = normalize(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return rv.Interface()
+		}
+		out := make(map[string]any, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			out[iter.Key().String()] = normalize(iter.Value().Interface())
+		}
+		return out
+	case reflect.Struct:
+		b, err := json.Marshal(rv.Interface())
+		if err != nil {
+			return rv.Interface()
+		}
+		var props map[string]any
+		if err := json.Unmarshal(b, &props); err != nil {
+			return rv.Interface()
+		}
+		return normalize(props)
+	}
+	return rv.Interface()
+}
+
+// equal reports whether the normalized values want and got are equal, where
+// missing properties equal zero ones under [IgnoreZero].
+func (c comparer) equal(want, got any) bool {
+	if c.ignoreZero && isZero(want) && isZero(got) {
+		return true
+	}
+	switch want := want.(type) {
+	case map[string]any:
+		got, ok := got.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, w := range want {
+			g, ok := got[k]
+			if !ok && !c.ignoreZero {
+				return false
+			}
+			if !c.equal(w, g) {
+				return false
+			}
+		}
+		for k, g := range got {
+			if _, ok := want[k]; !ok && (!c.ignoreZero || !isZero(g)) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		got, ok := got.([]any)
+		if !ok || len(want) != len(got) {
+			return false
+		}
+		for i := range want {
+			if !c.equal(want[i], got[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// isZero reports whether the normalized value v is zero.
+func isZero(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
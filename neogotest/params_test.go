@@ -0,0 +1,85 @@
+package neogotest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rlch/neogo"
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertParams(t *testing.T) {
+	d := neogo.NewMock()
+	belt := "black"
+
+	t.Run("passes equivalent parameters", func(t *testing.T) {
+		p := tests.Person{Name: "Ada", Age: 36, Belt: &belt}
+		q := d.Exec().
+			Create(db.Node(db.Qual(&p, "p"))).
+			Set(db.SetPropValue(&p.Surname, int32(1)))
+		r := &recorder{TB: t}
+		assert.True(t, AssertParams(r, q, map[string]any{
+			"p_name": "Ada",
+			"p_age":  int64(36),
+			"p_belt": belt,
+			"v1":     1.0,
+		}))
+		assert.Empty(t, r.errors)
+	})
+
+	t.Run("ignores zero values when asked", func(t *testing.T) {
+		p := tests.Person{Name: "Ada"}
+		q := d.Exec().Create(db.Node(db.Qual(&p, "p")))
+		r := &recorder{TB: t}
+		want := map[string]any{"p_name": "Ada", "p_email": ""}
+		assert.False(t, AssertParams(r, q, want))
+		assert.Equal(t, []string{"parameters differ (-want +got):\n  -p_email: \"\"\n"}, r.errors)
+
+		r = &recorder{TB: t}
+		assert.True(t, AssertParams(r, q, want, IgnoreZero()))
+		assert.Empty(t, r.errors)
+	})
+
+	t.Run("compares structs by their properties", func(t *testing.T) {
+		m := tests.Movie{Title: "Heat", Released: 1995}
+		q := d.Exec().
+			Unwind(db.NamedParam([]tests.Movie{m}, "movies"), "m").
+			Return("m")
+		r := &recorder{TB: t}
+		assert.True(t, AssertParams(r, q, map[string]any{
+			"movies": []map[string]any{{"title": "Heat", "released": 1995}},
+		}, IgnoreZero()))
+		assert.Empty(t, r.errors)
+	})
+
+	t.Run("reports differing parameters", func(t *testing.T) {
+		p := tests.Person{Name: "Ada", Position: "engineer"}
+		q := d.Exec().Create(db.Node(db.Qual(&p, "p")))
+		r := &recorder{TB: t}
+		assert.False(t, AssertParams(r, q, map[string]any{
+			"p_name": "Grace",
+			"p_age":  36,
+		}))
+		assert.Equal(t, []string{
+			"parameters differ (-want +got):\n" +
+				"  -p_age: 36\n" +
+				"  -p_name: \"Grace\"\n" +
+				"  +p_name: \"Ada\"\n" +
+				"  +p_position: \"engineer\"\n",
+		}, r.errors)
+	})
+}
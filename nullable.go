@@ -0,0 +1,94 @@
+package neogo
+
+import (
+	"bytes"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/goccy/go-json"
+)
+
+// Nullable is a property which may be null, for when the zero value of T is
+// meaningful and a pointer is unwieldy. It is the same as [sql.Null], so
+// types can be shared with database/sql code.
+//
+//	type Person struct {
+//		neogo.Node `neo4j:"Person"`
+//
+//		Age neogo.Nullable[int] `json:"age"`
+//	}
+//
+// Like the other types implementing [database/sql/driver.Valuer] and
+// [sql.Scanner], i.e. [sql.NullString] and [sql.NullInt64], it is written as
+// its value or null, and read from either.
+type Nullable[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NullableOf returns a non-null Nullable of v.
+func NullableOf[T any](v T) Nullable[T] {
+	return Nullable[T]{V: v, Valid: true}
+}
+
+// Get returns the value of n and whether it is non-null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.V, n.Valid
+}
+
+func (n Nullable[T]) Value() (sqldriver.Value, error) {
+	return sql.Null[T](n).Value()
+}
+
+func (n *Nullable[T]) Scan(src any) error {
+	return (*sql.Null[T])(n).Scan(src)
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	var zero T
+	n.V, n.Valid = zero, false
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+var (
+	rValuer  = reflect.TypeOf((*sqldriver.Valuer)(nil)).Elem()
+	rScanner = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// sqlConverter returns the converter of t if it implements
+// [database/sql/driver.Valuer] and its pointer [sql.Scanner], i.e.
+// [sql.NullString] or [Nullable].
+func sqlConverter(t reflect.Type) (TypeConverter, bool) {
+	if t.Kind() == reflect.Ptr || !t.Implements(rValuer) || !reflect.PointerTo(t).Implements(rScanner) {
+		return TypeConverter{}, false
+	}
+	return TypeConverter{
+		Stored: emptyInterface,
+		ToDB: func(v any) (any, error) {
+			return v.(sqldriver.Valuer).Value()
+		},
+		FromDB: func(stored any) (any, error) {
+			v := reflect.New(t)
+			if err := v.Interface().(sql.Scanner).Scan(stored); err != nil {
+				return nil, fmt.Errorf("cannot scan %T into %s: %w", stored, t, err)
+			}
+			return v.Elem().Interface(), nil
+		},
+	}, true
+}
@@ -0,0 +1,78 @@
+package neogo
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+)
+
+type nullablePerson struct {
+	Node `neo4j:"Person"`
+
+	Nickname sql.NullString `json:"nickname"`
+	Visits   sql.NullInt64  `json:"visits"`
+	Age      Nullable[int]  `json:"age"`
+	Retired  Nullable[bool] `json:"retired"`
+}
+
+func TestNullable(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes values of nullable fields", func(t *testing.T) {
+		m, params := newParamsMock(nil)
+		p := nullablePerson{
+			Nickname: sql.NullString{String: "Ada", Valid: true},
+			Visits:   sql.NullInt64{Int64: 0, Valid: true},
+			Age:      NullableOf(0),
+		}
+		require.NoError(t, m.Exec().Create(db.Node(db.Qual(&p, "p"))).Run(ctx))
+		require.Equal(t, "Ada", params()["p_nickname"])
+		require.Equal(t, int64(0), params()["p_visits"])
+		require.Equal(t, int64(0), params()["p_age"])
+		require.NotContains(t, params(), "p_retired")
+	})
+
+	t.Run("writes null for invalid fields of struct parameters", func(t *testing.T) {
+		r := &registry{}
+		params, err := r.convertParams(context.Background(), map[string]any{
+			"p": nullablePerson{Age: NullableOf(42)},
+		}, false)
+		require.NoError(t, err)
+		props := params["p"].(map[string]any)
+		require.Nil(t, props["nickname"])
+		require.Nil(t, props["retired"])
+		require.Equal(t, int64(42), props["age"])
+	})
+
+	t.Run("reads nullable fields", func(t *testing.T) {
+		r := &registry{}
+		var p nullablePerson
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Person"},
+			Props: map[string]any{
+				"nickname": "Ada",
+				"visits":   int64(3),
+				"age":      int64(0),
+				"retired":  nil,
+			},
+		}, reflect.ValueOf(&p))
+		require.NoError(t, err)
+		require.Equal(t, sql.NullString{String: "Ada", Valid: true}, p.Nickname)
+		require.Equal(t, sql.NullInt64{Int64: 3, Valid: true}, p.Visits)
+		require.Equal(t, NullableOf(0), p.Age)
+		require.False(t, p.Retired.Valid)
+	})
+
+	t.Run("reads nullable values", func(t *testing.T) {
+		r := &registry{}
+		var age Nullable[int]
+		require.NoError(t, r.bindValue(context.Background(), int64(7), reflect.ValueOf(&age)))
+		require.Equal(t, NullableOf(7), age)
+	})
+}
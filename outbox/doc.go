@@ -0,0 +1,15 @@
+/*
+Package outbox publishes messages written to a transactional outbox to a
+message broker.
+
+Messages are written as [Message] nodes within the same transaction as the
+change they describe, so they are only visible once it commits. A [Poller]
+reads pending messages, passes them to a [Publisher], and marks them as
+published, or deletes them, in a separate transaction. Delivery is therefore
+at-least-once, and messages of the same aggregate are published in order of
+their sequence.
+
+	p := outbox.NewPoller(d, publisher, outbox.WithInterval(time.Second))
+	go p.Run(ctx)
+*/
+package outbox
@@ -0,0 +1,211 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rlch/neogo"
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+)
+
+type (
+	// Message is a message in the outbox. It should be created within the
+	// transaction of the change it describes, with an ID generated by
+	// GenerateID.
+	Message struct {
+		neogo.Node `neo4j:"OutboxMessage"`
+
+		// Aggregate identifies the entity the message describes. Messages of the
+		// same aggregate are published in order of Sequence.
+		Aggregate string `json:"aggregate"`
+		Sequence  int64  `json:"sequence"`
+		Topic     string `json:"topic"`
+		Payload   []byte `json:"payload"`
+		// CreatedAt is when the message was written.
+		CreatedAt time.Time `json:"createdAt"`
+
+		// Attempts is the number of times publishing the message has failed.
+		Attempts    int        `json:"attempts"`
+		LastError   string     `json:"lastError"`
+		PublishedAt *time.Time `json:"publishedAt"`
+		FailedAt    *time.Time `json:"failedAt"`
+	}
+
+	// Publisher publishes messages to a message broker.
+	Publisher interface {
+		Publish(ctx context.Context, m *Message) error
+	}
+
+	// PublisherFunc is a function implementing [Publisher].
+	PublisherFunc func(ctx context.Context, m *Message) error
+
+	// Poller publishes the pending messages in the outbox.
+	Poller struct {
+		driver      neogo.Driver
+		publisher   Publisher
+		batchSize   int
+		interval    time.Duration
+		maxAttempts int
+		delete      bool
+	}
+
+	// Option configures a [Poller].
+	Option func(*Poller)
+)
+
+func (f PublisherFunc) Publish(ctx context.Context, m *Message) error { return f(ctx, m) }
+
+// WithBatchSize sets the maximum number of messages read by each poll. Defaults
+// to 100.
+func WithBatchSize(n int) Option {
+	return func(p *Poller) {
+		p.batchSize = n
+	}
+}
+
+// WithInterval sets the time [Poller.Run] waits between polls. Defaults to 1
+// second.
+func WithInterval(d time.Duration) Option {
+	return func(p *Poller) {
+		p.interval = d
+	}
+}
+
+// WithMaxAttempts sets the number of times publishing a message is attempted
+// before it is marked as failed, and no longer retried. Defaults to 10.
+func WithMaxAttempts(n int) Option {
+	return func(p *Poller) {
+		p.maxAttempts = n
+	}
+}
+
+// WithDelete deletes messages once they are published, rather than marking
+// them as published.
+func WithDelete() Option {
+	return func(p *Poller) {
+		p.delete = true
+	}
+}
+
+// NewPoller creates a [Poller] publishing the messages in the outbox of d
+// through publisher.
+func NewPoller(d neogo.Driver, publisher Publisher, opts ...Option) *Poller {
+	p := &Poller{
+		driver:      d,
+		publisher:   publisher,
+		batchSize:   100,
+		interval:    time.Second,
+		maxAttempts: 10,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Run polls the outbox until ctx is done.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := p.Poll(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll publishes a batch of pending messages, returning the number which were
+// published. If publishing a message fails, the remaining messages of its
+// aggregate are left for the next poll, so they are not published out of
+// order. Once a message is marked as failed, no further messages of its
+// aggregate are published until it is deleted, or its failedAt is cleared.
+func (p *Poller) Poll(ctx context.Context) (published int, err error) {
+	var messages []*Message
+	err = p.driver.Exec().
+		Cypher(`
+			MATCH (m:OutboxMessage)
+			WHERE m.publishedAt IS NULL AND m.failedAt IS NULL
+			  AND NOT EXISTS {
+			    MATCH (dead:OutboxMessage {aggregate: m.aggregate})
+			    WHERE dead.failedAt IS NOT NULL
+			  }
+			WITH m
+			ORDER BY m.aggregate, m.sequence
+			LIMIT $limit`).
+		Return(db.Qual(&messages, "m")).
+		RunWithParams(ctx, map[string]any{"limit": p.batchSize})
+	if err != nil {
+		return 0, fmt.Errorf("cannot read outbox: %w", err)
+	}
+
+	blocked := map[string]struct{}{}
+	var errs []error
+	for _, m := range messages {
+		if _, ok := blocked[m.Aggregate]; ok {
+			continue
+		}
+		if pubErr := p.publisher.Publish(ctx, m); pubErr != nil {
+			blocked[m.Aggregate] = struct{}{}
+			if err := p.fail(ctx, m, pubErr); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := p.complete(ctx, m); err != nil {
+			// The message will be published again, so later messages of its
+			// aggregate must wait.
+			blocked[m.Aggregate] = struct{}{}
+			errs = append(errs, err)
+			continue
+		}
+		published++
+	}
+	return published, errors.Join(errs...)
+}
+
+// complete marks m as published, or deletes it.
+func (p *Poller) complete(ctx context.Context, m *Message) error {
+	match := &Message{}
+	match.ID = m.ID
+	q := p.driver.Exec().Match(db.Node(db.Qual(match, "m")))
+	var err error
+	if p.delete {
+		err = q.Delete(match).Run(ctx)
+	} else {
+		err = q.Set(db.SetPropValue(&match.PublishedAt, db.DateTime(nil))).Run(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot complete outbox message %s: %w", m.ID, err)
+	}
+	return nil
+}
+
+// fail records a failed attempt at publishing m, marking it as failed once it
+// has been attempted the maximum number of times.
+func (p *Poller) fail(ctx context.Context, m *Message, pubErr error) error {
+	match := &Message{}
+	match.ID = m.ID
+	items := []internal.SetItem{
+		db.SetPropValue(&match.Attempts, db.Param(m.Attempts+1)),
+		db.SetPropValue(&match.LastError, db.Param(pubErr.Error())),
+	}
+	if m.Attempts+1 >= p.maxAttempts {
+		items = append(items, db.SetPropValue(&match.FailedAt, db.DateTime(nil)))
+	}
+	err := p.driver.Exec().
+		Match(db.Node(db.Qual(match, "m"))).
+		Set(items...).
+		Run(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot record failure of outbox message %s: %w", m.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo"
+	"github.com/rlch/neogo/db"
+)
+
+func message(id, aggregate string, sequence int64) Message {
+	m := Message{Aggregate: aggregate, Sequence: sequence, Topic: "orders"}
+	m.ID = id
+	return m
+}
+
+func TestMessage(t *testing.T) {
+	d := neogo.NewMock()
+	d.Bind(nil)
+	m := message("1", "order-1", 1)
+	m.Payload = []byte(`{"total":42}`)
+	err := d.Exec().Create(db.Node(db.Qual(&m, "m"))).Run(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPoll(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publishes pending messages", func(t *testing.T) {
+		d := neogo.NewMock()
+		d.BindRecords([]map[string]any{
+			{"m": message("1", "order-1", 1)},
+			{"m": message("2", "order-1", 2)},
+		})
+		d.Bind(nil)
+		d.Bind(nil)
+
+		var published []string
+		p := NewPoller(d, PublisherFunc(func(_ context.Context, m *Message) error {
+			published = append(published, m.ID)
+			return nil
+		}))
+		n, err := p.Poll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []string{"1", "2"}, published)
+	})
+
+	t.Run("preserves ordering per aggregate on failure", func(t *testing.T) {
+		d := neogo.NewMock()
+		d.BindRecords([]map[string]any{
+			{"m": message("1", "order-1", 1)},
+			{"m": message("2", "order-2", 1)},
+			{"m": message("3", "order-1", 2)},
+		})
+		// Recording the failure of 1, and completing 2.
+		d.Bind(nil)
+		d.Bind(nil)
+
+		var published []string
+		p := NewPoller(d, PublisherFunc(func(_ context.Context, m *Message) error {
+			if m.ID == "1" {
+				return errors.New("broker unavailable")
+			}
+			published = append(published, m.ID)
+			return nil
+		}))
+		n, err := p.Poll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []string{"2"}, published)
+	})
+}
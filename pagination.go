@@ -0,0 +1,83 @@
+package neogo
+
+import (
+	"strconv"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/query"
+)
+
+// Page is a page of items of a list, along with the total number of items in
+// the list, populated by [PaginateOffset].
+type Page[T any] struct {
+	// Number is the page number, starting at 1.
+	Number int
+	// Size is the maximum number of items in the page.
+	Size int
+
+	Items      []T
+	TotalCount int
+}
+
+// TotalPages returns the number of pages needed to list every item.
+func (p *Page[T]) TotalPages() int {
+	if p.Size <= 0 {
+		return 0
+	}
+	return (p.TotalCount + p.Size - 1) / p.Size
+}
+
+// HasNext reports whether there is a page after p.
+func (p *Page[T]) HasNext() bool {
+	return p.Number < p.TotalPages()
+}
+
+// PaginateOffset writes a query populating the items of page, and the total
+// number of items matched by match, in a single round trip. match is called
+// twice, once to count every item and once to list the items of the page,
+// ordered by opts.
+//
+//	page := neogo.Page[Person]{Number: 2, Size: 20}
+//	err := neogo.PaginateOffset(d.Exec(), &page, func(c query.Reader, p *Person) query.Querier {
+//		return c.Match(db.Node(db.Qual(p, "p")))
+//	}, db.OrderBy("name", true)).Run(ctx)
+//
+//	CALL {
+//	  MATCH (p:Person)
+//	  RETURN count(p) AS total
+//	}
+//	CALL {
+//	  MATCH (p:Person)
+//	  WITH p
+//	  ORDER BY p.name
+//	  SKIP 20
+//	  LIMIT 20
+//	  RETURN collect(p) AS items
+//	}
+//	RETURN items, total
+func PaginateOffset[T any](
+	c Query,
+	page *Page[T],
+	match func(c query.Reader, item *T) query.Querier,
+	opts ...internal.ProjectionBodyOption,
+) query.Runner {
+	number := max(page.Number, 1)
+	return c.
+		Subquery(func(c Query) query.Runner {
+			var item T
+			return match(c, &item).
+				Return(db.Qual(db.Bind(db.Exprf("count(%s)", &item), &page.TotalCount), "total"))
+		}).
+		Subquery(func(c Query) query.Runner {
+			var item T
+			opts := append(opts[:len(opts):len(opts)],
+				db.Skip(strconv.Itoa((number-1)*page.Size)),
+				db.Limit(strconv.Itoa(page.Size)),
+			)
+			return match(c, &item).
+				With(db.With(&item, opts...)).
+				Return(db.Qual(db.Bind(db.Exprf("collect(%s)", &item), &page.Items), "items"))
+		}).
+		Return(&page.Items, &page.TotalCount)
+}
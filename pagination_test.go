@@ -0,0 +1,59 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
+	"github.com/rlch/neogo/internal/tests"
+	"github.com/rlch/neogo/query"
+)
+
+func TestPaginateOffset(t *testing.T) {
+	matchPeople := func(c query.Reader, p *tests.Person) query.Querier {
+		return c.Match(db.Node(db.Qual(p, "p")))
+	}
+
+	t.Run("compiles count and page subqueries", func(t *testing.T) {
+		s := &session{}
+		page := Page[tests.Person]{Number: 3, Size: 10}
+		runner := PaginateOffset(s.newClient(internal.NewCypherClient()), &page, matchPeople, db.OrderBy("name", true))
+		cy, err := runner.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, `CALL {
+  MATCH (p:Person)
+  RETURN count(p) AS total
+}
+CALL {
+  MATCH (p:Person)
+  WITH p
+  ORDER BY p.name
+  SKIP 20
+  LIMIT 10
+  RETURN collect(p) AS items
+}
+RETURN items, total`, cy.Cypher)
+	})
+
+	t.Run("binds items and total count", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{
+			"items": []any{
+				map[string]any{"id": "1", "name": "Jessie"},
+				map[string]any{"id": "2", "name": "Alex"},
+			},
+			"total": 12,
+		})
+		page := Page[tests.Person]{Number: 2, Size: 10}
+		require.NoError(t, PaginateOffset(m.Exec(), &page, matchPeople).Run(context.Background()))
+		assert.Len(t, page.Items, 2)
+		assert.Equal(t, "Alex", page.Items[1].Name)
+		assert.Equal(t, 12, page.TotalCount)
+		assert.Equal(t, 2, page.TotalPages())
+		assert.False(t, page.HasNext())
+	})
+}
@@ -0,0 +1,77 @@
+package neogo
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// ParamNamer returns the name of a parameter generated when compiling a query,
+// given its default name and its 1-based position amongst the generated
+// parameters of the query. Parameters named with [db.NamedParam] or passed to
+// RunWithParams are never renamed. See [WithParamNaming].
+type ParamNamer = internal.ParamNamer
+
+// CounterParamNames names generated parameters by their position within the
+// query, i.e. $p1, $p2, ... for the prefix "p".
+func CounterParamNames(prefix string) ParamNamer {
+	return func(_ string, index int) string {
+		return prefix + strconv.Itoa(index)
+	}
+}
+
+// HashedParamNames names generated parameters by a short hash of their default
+// name, i.e. $p_5c1e6a2b, which keeps names compact regardless of how deeply
+// nested the property they're derived from is.
+func HashedParamNames() ParamNamer {
+	return func(name string, _ int) string {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(name))
+		return "p_" + strconv.FormatUint(uint64(h.Sum32()), 16)
+	}
+}
+
+// PrefixedParamNames prefixes the default names of generated parameters with
+// prefix, i.e. $app_v1 or $app_n_name for the prefix "app_", so they can't
+// collide with parameters written by hand.
+func PrefixedParamNames(prefix string) ParamNamer {
+	return func(name string, _ int) string {
+		return prefix + name
+	}
+}
+
+// ParamTransformer transforms the parameters of every query after they have
+// been canonicalized, just before they are sent to Neo4J. It is registered
+// with [WithParamTransformers], and can apply cross-cutting conversions such
+// as formatting decimals as strings, without changing the types being
+// written.
+type ParamTransformer interface {
+	// TransformParams returns the parameters to send in place of params,
+	// which it may modify in place.
+	TransformParams(ctx context.Context, params map[string]any) (map[string]any, error)
+}
+
+// ParamTransformerFunc adapts a function to a [ParamTransformer].
+type ParamTransformerFunc func(ctx context.Context, params map[string]any) (map[string]any, error)
+
+func (f ParamTransformerFunc) TransformParams(ctx context.Context, params map[string]any) (map[string]any, error) {
+	return f(ctx, params)
+}
+
+// transformParams applies the parameter transformers of the driver to params,
+// in the order they were registered.
+func (d *driver) transformParams(ctx context.Context, params map[string]any) (map[string]any, error) {
+	if d == nil {
+		return params, nil
+	}
+	for _, t := range d.paramTransformers {
+		var err error
+		if params, err = t.TransformParams(ctx, params); err != nil {
+			return nil, fmt.Errorf("cannot transform parameters: %w", err)
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,78 @@
+package neogo
+
+import (
+	"reflect"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// payloadSize estimates the number of bytes v takes up on the wire. Strings and
+// byte slices count their length, and other scalars a fixed number of bytes,
+// so the estimate is meant for comparing queries rather than exact.
+func payloadSize(v any) int64 {
+	switch v := v.(type) {
+	case nil:
+		return 1
+	case neo4j.Node:
+		return payloadSize(v.Labels) + payloadSize(v.Props) + int64(len(v.ElementId))
+	case neo4j.Relationship:
+		return int64(len(v.Type)) + payloadSize(v.Props) +
+			int64(len(v.ElementId)+len(v.StartElementId)+len(v.EndElementId))
+	case neo4j.Path:
+		return payloadSize(v.Nodes) + payloadSize(v.Relationships)
+	case *neo4j.Record:
+		if v == nil {
+			return 0
+		}
+		return payloadSize(v.Values)
+	}
+	return reflectPayloadSize(reflect.ValueOf(v))
+}
+
+func reflectPayloadSize(v reflect.Value) int64 {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return 1
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return 1
+	case reflect.Int16, reflect.Uint16:
+		return 2
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 4
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return int64(v.Len())
+		}
+		var size int64
+		for i := 0; i < v.Len(); i++ {
+			size += payloadSize(v.Index(i).Interface())
+		}
+		return size
+	case reflect.Map:
+		var size int64
+		for iter := v.MapRange(); iter.Next(); {
+			size += payloadSize(iter.Key().Interface()) + payloadSize(iter.Value().Interface())
+		}
+		return size
+	case reflect.Struct:
+		if internal.IsDBType(v.Type()) {
+			return 16
+		}
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.IsExported() {
+				size += int64(len(f.Name)) + payloadSize(v.Field(i).Interface())
+			}
+		}
+		return size
+	}
+	return 8
+}
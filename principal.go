@@ -0,0 +1,107 @@
+package neogo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// PrincipalState is the state a driver keeps for the sessions of a principal,
+// returned by [PrincipalStateOf]. Sessions impersonating a user, or
+// authenticated with their own token, run as that user; other sessions run as
+// the principal "", the user of the driver's credentials.
+type PrincipalState struct {
+	// Bookmarks are the bookmarks of the last writes of the principal, by the
+	// key returned by the function given to [WithCausalConsistency].
+	Bookmarks map[string]neo4j.Bookmarks
+}
+
+// principalOf returns the principal sessions configured with sc run as.
+func principalOf(sc *neo4j.SessionConfig) string {
+	if sc == nil {
+		return ""
+	}
+	if sc.ImpersonatedUser != "" {
+		return sc.ImpersonatedUser
+	}
+	if sc.Auth != nil {
+		if principal, ok := sc.Auth.Tokens["principal"].(string); ok {
+			return principal
+		}
+	}
+	return ""
+}
+
+type bookmarkKey struct {
+	principal string
+	key       string
+}
+
+// bookmarkCache stores the bookmarks used for causal consistency by principal
+// and key, so that the bookmarks of one principal are never used by another.
+type bookmarkCache struct {
+	mu        sync.Mutex
+	bookmarks map[bookmarkKey]neo4j.Bookmarks
+}
+
+func newBookmarkCache() *bookmarkCache {
+	return &bookmarkCache{bookmarks: map[bookmarkKey]neo4j.Bookmarks{}}
+}
+
+func (c *bookmarkCache) get(principal, key string) neo4j.Bookmarks {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bookmarks[bookmarkKey{principal, key}]
+}
+
+// add combines bookmarks with those stored for principal and key, which are
+// discarded once ctx is done.
+func (c *bookmarkCache) add(ctx context.Context, principal, key string, bookmarks neo4j.Bookmarks) {
+	if c == nil {
+		return
+	}
+	k := bookmarkKey{principal, key}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cur, ok := c.bookmarks[k]; ok {
+		c.bookmarks[k] = neo4j.CombineBookmarks(cur, bookmarks)
+		return
+	}
+	c.bookmarks[k] = bookmarks
+	go func() {
+		<-ctx.Done()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.bookmarks, k)
+	}()
+}
+
+func (c *bookmarkCache) principal(principal string) map[string]neo4j.Bookmarks {
+	out := map[string]neo4j.Bookmarks{}
+	if c == nil {
+		return out
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, bookmarks := range c.bookmarks {
+		if k.principal == principal && bookmarks != nil {
+			out[k.key] = bookmarks
+		}
+	}
+	return out
+}
+
+// PrincipalStateOf returns the state d keeps for the sessions running as
+// principal, i.e. a user impersonated with
+// [neo4j.SessionConfig.ImpersonatedUser].
+func PrincipalStateOf(d Driver, principal string) PrincipalState {
+	impl := driverOf(d)
+	if impl == nil {
+		return PrincipalState{}
+	}
+	return PrincipalState{Bookmarks: impl.bookmarks.principal(principal)}
+}
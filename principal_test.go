@@ -0,0 +1,48 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipalOf(t *testing.T) {
+	assert.Equal(t, "", principalOf(nil))
+	assert.Equal(t, "", principalOf(&neo4j.SessionConfig{}))
+	assert.Equal(t, "alice", principalOf(&neo4j.SessionConfig{ImpersonatedUser: "alice"}))
+	basic := neo4j.BasicAuth("bob", "secret", "")
+	assert.Equal(t, "bob", principalOf(&neo4j.SessionConfig{Auth: &basic}))
+}
+
+func TestBookmarksByPrincipal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &driver{
+		bookmarks:            newBookmarkCache(),
+		causalConsistencyKey: func(context.Context) string { return "request" },
+	}
+	d.bookmarks.add(ctx, "alice", "request", neo4j.Bookmarks{"a1"})
+	d.bookmarks.add(ctx, "alice", "request", neo4j.Bookmarks{"a2"})
+	d.bookmarks.add(ctx, "bob", "request", neo4j.Bookmarks{"b1"})
+
+	alice := neo4j.SessionConfig{ImpersonatedUser: "alice"}
+	d.ensureCausalConsistency(ctx, &alice)
+	assert.ElementsMatch(t, neo4j.Bookmarks{"a1", "a2"}, alice.Bookmarks)
+
+	carol := neo4j.SessionConfig{ImpersonatedUser: "carol"}
+	d.ensureCausalConsistency(ctx, &carol)
+	assert.Nil(t, carol.Bookmarks)
+
+	assert.Equal(t, PrincipalState{
+		Bookmarks: map[string]neo4j.Bookmarks{"request": {"b1"}},
+	}, PrincipalStateOf(d, "bob"))
+
+	cancel()
+	require.Eventually(t, func() bool {
+		return len(PrincipalStateOf(d, "alice").Bookmarks) == 0 &&
+			len(PrincipalStateOf(d, "bob").Bookmarks) == 0
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,214 @@
+package neogo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rlch/neogo/internal"
+)
+
+// Processor post-processes the values read by queries, after they have been
+// unmarshalled. Processors are registered for a type with
+// [WithTypeProcessors], or for the values of a query with [WithProcessors].
+type Processor struct {
+	// Name identifies the processor in errors, and to [UnregisterProcessor].
+	Name string
+	// Priority orders processors: those with lower priorities run first, and
+	// those with equal priorities in the order they were registered.
+	Priority int
+	// Process processes v, a pointer to a value read by a query, in place.
+	Process func(ctx context.Context, v any) error
+}
+
+// AfterLoader is implemented by entities which are notified after they have
+// been read by a query, i.e. to compute unexported fields. It is called before
+// any [Processor], with the context of the query; returning an error fails
+// the query.
+type AfterLoader interface {
+	AfterLoad(ctx context.Context) error
+}
+
+// processors maps types to the processors run on their values.
+type processors map[reflect.Type][]Processor
+
+// sortProcessors orders processors by priority, keeping the order in which
+// processors of equal priority were registered.
+func sortProcessors(processors []Processor) {
+	sort.SliceStable(processors, func(i, j int) bool {
+		return processors[i].Priority < processors[j].Priority
+	})
+}
+
+// MapStrings returns a processor replacing each string s within a value, such
+// as the string fields of a struct and the elements of string slices, with
+// fn(s). It can, for instance, normalize the Unicode form of strings:
+//
+//	neogo.MapStrings("nfc", norm.NFC.String)
+func MapStrings(name string, fn func(s string) string) Processor {
+	return Processor{
+		Name: name,
+		Process: func(_ context.Context, v any) error {
+			mapStrings(reflect.ValueOf(v), fn)
+			return nil
+		},
+	}
+}
+
+// TrimStrings returns a processor trimming the leading and trailing white
+// space of each string within a value.
+func TrimStrings() Processor {
+	return MapStrings("trim", strings.TrimSpace)
+}
+
+// RedactFields returns a processor zeroing the given fields of structs, named
+// by their Go field names or property names, unless allow reports that the
+// context, such as the role of the user making the request, may see them.
+func RedactFields(allow func(ctx context.Context) bool, fields ...string) Processor {
+	redacted := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		redacted[f] = struct{}{}
+	}
+	return Processor{
+		Name: "redact",
+		Process: func(ctx context.Context, v any) error {
+			if allow != nil && allow(ctx) {
+				return nil
+			}
+			redactFields(reflect.ValueOf(v), redacted)
+			return nil
+		},
+	}
+}
+
+// Derive returns a processor computing fields of values of type T from their
+// other fields, such as a full name from a first and last name. Values of
+// other types are left as-is.
+func Derive[T any](name string, fn func(ctx context.Context, v *T) error) Processor {
+	return Processor{
+		Name: name,
+		Process: func(ctx context.Context, v any) error {
+			if v, ok := v.(*T); ok {
+				return fn(ctx, v)
+			}
+			return nil
+		},
+	}
+}
+
+// process calls the AfterLoad hooks of the values bound by cy, and runs the
+// processors of the query and those registered for their types on them.
+func (s *session) process(ctx context.Context, cy *internal.CompiledCypher) error {
+	noProcessors := !s.hooks.hasProcessors() && len(s.execConfig.processors) == 0
+	for _, binding := range cy.Bindings {
+		if noProcessors && !loadsAfter(binding.Type()) {
+			continue
+		}
+		if err := s.processValue(ctx, binding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var afterLoaderType = reflect.TypeOf((*AfterLoader)(nil)).Elem()
+
+// loadsAfter reports whether the values of t, or its elements, implement
+// [AfterLoader].
+func loadsAfter(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return reflect.PointerTo(t).Implements(afterLoaderType)
+}
+
+func (s *session) processValue(ctx context.Context, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			if err := s.processValue(ctx, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !v.CanAddr() {
+		return nil
+	}
+	ptr := v.Addr().Interface()
+	if l, ok := ptr.(AfterLoader); ok {
+		if err := l.AfterLoad(ctx); err != nil {
+			return fmt.Errorf("AfterLoad of %s failed: %w", v.Type(), err)
+		}
+	}
+	procs := slices.Concat(s.hooks.processorsOf(v.Type()), s.execConfig.processors)
+	for _, p := range procs {
+		if err := p.Process(ctx, ptr); err != nil {
+			return fmt.Errorf("processor %s failed on %s: %w", p.Name, v.Type(), err)
+		}
+	}
+	return nil
+}
+
+func mapStrings(v reflect.Value, fn func(string) string) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(fn(v.String()))
+		}
+	case reflect.Struct:
+		if internal.IsDBType(v.Type()) {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				mapStrings(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			mapStrings(v.Index(i), fn)
+		}
+	}
+}
+
+func redactFields(v reflect.Value, fields map[string]struct{}) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			redactFields(v.Field(i), fields)
+			continue
+		}
+		_, byName := fields[f.Name]
+		_, byProp := fields[strings.Split(f.Tag.Get("json"), ",")[0]]
+		if byName || byProp {
+			v.Field(i).SetZero()
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+func TestProcessors(t *testing.T) {
+	type roleKey struct{}
+	isAdmin := func(ctx context.Context) bool {
+		return ctx.Value(roleKey{}) == "admin"
+	}
+	newDriver := func() mockDriver {
+		m := NewMock()
+		m.(*mockDriverImpl).hooks = newHookSet(nil, processors{
+			reflect.TypeOf(tests.Person{}): {
+				TrimStrings(),
+				Derive("position", func(ctx context.Context, p *tests.Person) error {
+					if p.Position == "" {
+						p.Position = "Unknown"
+					}
+					return nil
+				}),
+			},
+		})
+		return m
+	}
+	person := tests.Person{Name: "  Spongebob ", Surname: "Squarepants\n"}
+
+	t.Run("runs type and query processors in order", func(t *testing.T) {
+		d := newDriver()
+		d.BindRecords([]map[string]any{{"p": person}, {"p": person}})
+		var people []tests.Person
+		err := d.Exec(WithProcessors(RedactFields(isAdmin, "surname"))).
+			Match(db.Node(db.Qual(&people, "p"))).
+			Return(&people).
+			Run(context.Background())
+		require.NoError(t, err)
+		require.Len(t, people, 2)
+		for _, p := range people {
+			assert.Equal(t, "Spongebob", p.Name)
+			assert.Equal(t, "", p.Surname)
+			assert.Equal(t, "Unknown", p.Position)
+		}
+	})
+
+	t.Run("skips redaction when allowed", func(t *testing.T) {
+		d := newDriver()
+		d.Bind(map[string]any{"p": person})
+		var p tests.Person
+		ctx := context.WithValue(context.Background(), roleKey{}, "admin")
+		err := d.Exec(WithProcessors(RedactFields(isAdmin, "Surname"))).
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "Squarepants", p.Surname)
+	})
+
+	t.Run("returns errors of processors", func(t *testing.T) {
+		d := newDriver()
+		d.Bind(map[string]any{"p": person})
+		errInvalid := errors.New("invalid")
+		var p tests.Person
+		err := d.Exec(WithProcessors(Processor{
+			Name:    "validate",
+			Process: func(context.Context, any) error { return errInvalid },
+		})).
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Run(context.Background())
+		require.ErrorIs(t, err, errInvalid)
+		assert.ErrorContains(t, err, "processor validate failed on tests.Person")
+	})
+}
+
+type hookedPerson struct {
+	Node `neo4j:"Person"`
+
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	greeting string
+	saves    int
+}
+
+type slugSuffixKey struct{}
+
+func (p *hookedPerson) BeforeSave(ctx context.Context) error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	p.saves++
+	p.Slug = strings.ToLower(p.Name)
+	if suffix, ok := ctx.Value(slugSuffixKey{}).(string); ok {
+		p.Slug += "-" + suffix
+	}
+	return nil
+}
+
+func (p *hookedPerson) BeforeDelete(ctx context.Context) error {
+	if p.Name == "admin" {
+		return errors.New("cannot delete admin")
+	}
+	return nil
+}
+
+func (p *hookedPerson) AfterLoad(ctx context.Context) error {
+	p.greeting = "Hello " + p.Name
+	return nil
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	ctx := context.WithValue(context.Background(), slugSuffixKey{}, "1")
+
+	t.Run("calls BeforeSave once when the query is run", func(t *testing.T) {
+		d, params := newParamsMock(nil)
+		p := hookedPerson{Name: "Ada"}
+		r := d.Exec().Create(db.Node(db.Qual(&p, "p")))
+		cy, err := r.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, "CREATE (p:Person {id: $p_id, name: $p_name, slug: $p_slug})", cy.Cypher)
+		assert.Equal(t, map[string]any{"p_id": nil, "p_name": "Ada", "p_slug": nil}, cy.Parameters)
+		_ = r.DebugString()
+		assert.Zero(t, p.saves)
+
+		require.NoError(t, r.Run(ctx))
+		assert.Equal(t, 1, p.saves)
+		assert.Equal(t, "ada-1", params()["p_slug"])
+	})
+
+	t.Run("leaves entities unchanged on EXPLAIN", func(t *testing.T) {
+		d, params := newParamsMock(nil)
+		p := hookedPerson{Name: "Ada"}
+		_, err := d.Exec().Create(db.Node(db.Qual(&p, "p"))).Explain(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, hookedPerson{Name: "Ada"}, p)
+		assert.Nil(t, params()["p_slug"])
+	})
+
+	t.Run("calls BeforeSave on values set", func(t *testing.T) {
+		d, params := newParamsMock(nil)
+		var n hookedPerson
+		p := hookedPerson{Name: "Ada"}
+		err := d.Exec().
+			Match(db.Node(db.Qual(&n, "n"))).
+			Set(db.SetPropValue(&n, db.Param(&p))).
+			Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "ada-1", p.Slug)
+		assert.Equal(t, "ada-1", params()["v1"].(map[string]any)["slug"])
+	})
+
+	t.Run("rereads upserted entities", func(t *testing.T) {
+		d, params := newParamsMock(nil)
+		var n hookedPerson
+		p := hookedPerson{Node: Node{ID: "1"}, Name: "Ada"}
+		err := d.Exec().
+			Merge(db.Node(db.Qual(&n, "n", db.Props{"id": "'1'"})), db.Upsert(&n, &p)).
+			Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.saves)
+		assert.Equal(t, "ada-1", params()["v1"].(map[string]any)["slug"])
+		assert.Equal(t, "ada-1", params()["v2"].(map[string]any)["slug"])
+	})
+
+	t.Run("calls BeforeSave on zero entities", func(t *testing.T) {
+		d, _ := newParamsMock(nil)
+		var p hookedPerson
+		err := d.Exec().Merge(db.Node(db.Qual(&p, "p"))).Run(ctx)
+		assert.ErrorContains(t, err, "BeforeSave of *neogo.hookedPerson failed: name is required")
+	})
+
+	t.Run("fails the query when a hook fails", func(t *testing.T) {
+		d, _ := newParamsMock(nil)
+		p := hookedPerson{Node: Node{ID: "1"}}
+		r := d.Exec().Create(db.Node(&p))
+		_, err := r.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		err = r.Run(ctx)
+		assert.ErrorContains(t, err, "BeforeSave of *neogo.hookedPerson failed: name is required")
+
+		admin := hookedPerson{Name: "admin"}
+		err = d.Exec().
+			Match(db.Node(db.Qual(&admin, "a"))).
+			Delete(&admin).
+			Run(ctx)
+		assert.ErrorContains(t, err, "BeforeDelete of *neogo.hookedPerson failed: cannot delete admin")
+	})
+
+	t.Run("calls AfterLoad after reading entities", func(t *testing.T) {
+		d := NewMock()
+		d.BindRecords([]map[string]any{
+			{"p": hookedPerson{Name: "Ada"}},
+			{"p": hookedPerson{Name: "Grace"}},
+		})
+		var people []*hookedPerson
+		err := d.Exec().
+			Match(db.Node(db.Qual(&people, "p"))).
+			Return(&people).
+			Run(context.Background())
+		require.NoError(t, err)
+		require.Len(t, people, 2)
+		assert.Equal(t, "Hello Ada", people[0].greeting)
+		assert.Equal(t, "Hello Grace", people[1].greeting)
+	})
+}
+
+func TestProcessorPriorities(t *testing.T) {
+	named := func(name string, priority int) Processor {
+		return Processor{Name: name, Priority: priority, Process: func(context.Context, any) error { return nil }}
+	}
+	names := func(processors []Processor) (out []string) {
+		for _, p := range processors {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	cfg := &Config{}
+	for _, c := range []Configurer{
+		WithTypeProcessors[tests.Person](named("audit", 10), named("trim", 0)),
+		WithTypeProcessors[tests.Person](named("locale", 0), named("redact", -5)),
+		WithTypeProcessors[tests.Movie](named("locale", 0)),
+		UnregisterProcessor("locale"),
+	} {
+		c(cfg)
+	}
+	assert.Equal(t, []string{"redact", "trim", "audit"}, names(cfg.Processors[reflect.TypeOf(tests.Person{})]))
+	assert.NotContains(t, cfg.Processors, reflect.TypeOf(tests.Movie{}))
+
+	ec := &execConfig{}
+	WithProcessors(named("last", 1), named("first", 0))(ec)
+	assert.Equal(t, []string{"first", "last"}, names(ec.processors))
+}
+
+type stampedPost struct {
+	Node `neo4j:"Post"`
+
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	EditedAt  time.Time `json:"editedAt" neogo:"updatedAt"`
+}
+
+func TestTimestamps(t *testing.T) {
+	built := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	run := built.Add(time.Minute)
+	ctx := context.Background()
+	newDriver := func(now *time.Time) (mockDriver, func() map[string]any) {
+		return newParamsMock(func(d *driver) {
+			d.clock = func() time.Time { return *now }
+		})
+	}
+
+	t.Run("stamps created entities when the query is run", func(t *testing.T) {
+		now := built
+		d, params := newDriver(&now)
+		p := stampedPost{Title: "Hello"}
+		r := d.Exec().Create(db.Node(db.Qual(&p, "p")))
+		cy, err := r.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, stampedPost{Title: "Hello"}, p)
+		assert.Nil(t, cy.Parameters["p_createdAt"])
+
+		now = run
+		require.NoError(t, r.Run(ctx))
+		assert.Equal(t, run, p.CreatedAt)
+		assert.Equal(t, run, p.UpdatedAt)
+		assert.Equal(t, run, p.EditedAt)
+		assert.Equal(t, run.Format(time.RFC3339Nano), params()["p_createdAt"])
+	})
+
+	t.Run("stamps zero entities", func(t *testing.T) {
+		now := run
+		d, params := newDriver(&now)
+		var p stampedPost
+		r := d.Exec().Create(db.Node(db.Qual(&p, "p")))
+		cy, err := r.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, "CREATE (p:Post {createdAt: $p_createdAt, editedAt: $p_editedAt, updatedAt: $p_updatedAt})", cy.Cypher)
+
+		require.NoError(t, r.Run(ctx))
+		assert.Equal(t, run, p.CreatedAt)
+		assert.Equal(t, run, p.UpdatedAt)
+		assert.Equal(t, run.Format(time.RFC3339Nano), params()["p_createdAt"])
+		assert.Equal(t, run.Format(time.RFC3339Nano), params()["p_updatedAt"])
+	})
+
+	t.Run("fails queries with malformed validate tags", func(t *testing.T) {
+		type misspeltPost struct {
+			Node `neo4j:"Post"`
+
+			Title     string    `json:"title" validate:"requird"`
+			CreatedAt time.Time `json:"createdAt"`
+		}
+		now := run
+		d, _ := newDriver(&now)
+		p := misspeltPost{Title: "Hello"}
+		var err error
+		require.NotPanics(t, func() {
+			err = d.Exec().Create(db.Node(&p)).Run(ctx)
+		})
+		assert.ErrorContains(t, err, "unknown validation rule: requird")
+	})
+
+	t.Run("keeps creation time of set entities", func(t *testing.T) {
+		now := run
+		d, _ := newDriver(&now)
+		created := built.Add(-time.Hour)
+		var n stampedPost
+		p := stampedPost{Title: "Hello", CreatedAt: created}
+		err := d.Exec().
+			Match(db.Node(db.Qual(&n, "n"))).
+			Set(db.SetPropValue(&n, db.Param(&p))).
+			Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, created, p.CreatedAt)
+		assert.Equal(t, run, p.UpdatedAt)
+	})
+
+	t.Run("skips merged entities", func(t *testing.T) {
+		now := run
+		d, _ := newDriver(&now)
+		p := stampedPost{Title: "Hello"}
+		r := d.Exec().Merge(db.Node(db.Qual(&p, "p")))
+		cy, err := r.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, "MERGE (p:Post {title: $p_title})", cy.Cypher)
+		require.NoError(t, r.Run(ctx))
+		assert.True(t, p.UpdatedAt.IsZero())
+	})
+
+	t.Run("is disabled by default", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{})
+		p := stampedPost{Title: "Hello"}
+		require.NoError(t, m.Exec().Create(db.Node(db.Qual(&p, "p"))).Run(ctx))
+		assert.True(t, p.CreatedAt.IsZero())
+	})
+}
+
+type auditedPost struct {
+	Node `neo4j:"Post"`
+
+	Title     string `json:"title"`
+	CreatedBy string `json:"createdBy"`
+	UpdatedBy string `json:"updatedBy"`
+	Editor    string `json:"editor" neogo:"updatedBy"`
+}
+
+func TestAuditActor(t *testing.T) {
+	type actorKey struct{}
+	newDriver := func() (mockDriver, func() map[string]any) {
+		return newParamsMock(func(d *driver) {
+			d.actor = func(ctx context.Context) string {
+				actor, _ := ctx.Value(actorKey{}).(string)
+				return actor
+			}
+		})
+	}
+	ctx := context.WithValue(context.Background(), actorKey{}, "alice")
+
+	t.Run("sets actor fields of created entities", func(t *testing.T) {
+		d, params := newDriver()
+		p := auditedPost{Title: "Hello"}
+		err := d.Exec().
+			Create(db.Node(db.Qual(&p, "p"))).
+			Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", p.CreatedBy)
+		assert.Equal(t, "alice", p.UpdatedBy)
+		assert.Equal(t, "alice", p.Editor)
+		assert.Equal(t, "alice", params()["p_createdBy"])
+		assert.Equal(t, "alice", params()["p_editor"])
+	})
+
+	t.Run("keeps creator of set entities", func(t *testing.T) {
+		d, params := newDriver()
+		var n auditedPost
+		p := auditedPost{Title: "Hello", CreatedBy: "bob"}
+		err := d.Exec().
+			Match(db.Node(db.Qual(&n, "n"))).
+			Set(db.SetPropValue(&n, db.Param(&p))).
+			Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", p.CreatedBy)
+		assert.Equal(t, "alice", p.UpdatedBy)
+		require.IsType(t, map[string]any{}, params()["v1"])
+		assert.Equal(t, "alice", params()["v1"].(map[string]any)["updatedBy"])
+	})
+
+	t.Run("writes null without an actor", func(t *testing.T) {
+		d, params := newDriver()
+		p := auditedPost{Title: "Hello"}
+		err := d.Exec().
+			Create(db.Node(db.Qual(&p, "p"))).
+			Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "", p.CreatedBy)
+		assert.Contains(t, params(), "p_createdBy")
+		assert.Nil(t, params()["p_createdBy"])
+	})
+}
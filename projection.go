@@ -0,0 +1,62 @@
+package neogo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnknownProjection is returned when running a query with a projection
+// which was not registered with [WithProjections].
+var ErrUnknownProjection = errors.New("unknown projection")
+
+// Projection is a named subset of the properties of a node type, such as the
+// few properties rendered by a list view. Queries run with [WithProjection]
+// return nodes of the type as map projections of those properties, so large
+// properties which aren't needed are never fetched.
+type Projection struct {
+	// Name identifies the projection, and may be shared by projections of
+	// different types.
+	Name string
+	// Type is the node type the projection applies to.
+	Type reflect.Type
+	// Fields are the names of the properties returned.
+	Fields []string
+}
+
+// NewProjection creates a projection named name, returning the given
+// properties of nodes of type N.
+//
+//	neogo.NewProjection[Article]("list", "id", "title", "publishedAt")
+func NewProjection[N any](name string, fields ...string) Projection {
+	return Projection{
+		Name:   name,
+		Type:   reflect.TypeOf((*N)(nil)).Elem(),
+		Fields: fields,
+	}
+}
+
+// projections indexes projections by name, then by node type.
+type projections map[string]map[reflect.Type][]string
+
+func newProjections(ps []Projection) projections {
+	if len(ps) == 0 {
+		return nil
+	}
+	out := projections{}
+	for _, p := range ps {
+		if out[p.Name] == nil {
+			out[p.Name] = map[reflect.Type][]string{}
+		}
+		out[p.Name][p.Type] = p.Fields
+	}
+	return out
+}
+
+func (p projections) get(name string) (map[reflect.Type][]string, error) {
+	fields, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProjection, name)
+	}
+	return fields, nil
+}
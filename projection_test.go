@@ -0,0 +1,67 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+func TestProjection(t *testing.T) {
+	newDriver := func() *driver {
+		d := NewMock().(*mockDriverImpl).driver
+		d.projections = newProjections([]Projection{
+			NewProjection[tests.Person]("list", "id", "name"),
+			NewProjection[tests.Movie]("list", "title"),
+		})
+		return d
+	}
+
+	t.Run("returns map projections of registered types", func(t *testing.T) {
+		var (
+			p tests.Person
+			m tests.Movie
+		)
+		runner := newDriver().Exec(WithProjection("list")).
+			Match(db.Node(db.Qual(&p, "p")).To(db.Var(nil, db.Label("ACTED_IN")), db.Qual(&m, "m"))).
+			Return(&p, &m, &p.Surname)
+		cy, err := runner.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, "MATCH (p:Person)-[:ACTED_IN]->(m:Movie)\nRETURN p {.id, .name} AS p, m {.title} AS m, p.surname", cy.Cypher)
+	})
+
+	t.Run("binds projected properties", func(t *testing.T) {
+		d := newDriver()
+		d.db.(*mockNeo4jDriver).Bind(map[string]any{
+			"p": map[string]any{"id": "1", "name": "Jessie"},
+		})
+		var p tests.Person
+		require.NoError(t, d.Exec(WithProjection("list")).
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Run(context.Background()))
+		assert.Equal(t, "1", p.ID)
+		assert.Equal(t, "Jessie", p.Name)
+	})
+
+	t.Run("leaves queries without a projection unchanged", func(t *testing.T) {
+		var p tests.Person
+		runner := newDriver().Exec().Match(db.Node(db.Qual(&p, "p"))).Return(&p)
+		cy, err := runner.(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		assert.Equal(t, "MATCH (p:Person)\nRETURN p", cy.Cypher)
+	})
+
+	t.Run("errors on unknown projections", func(t *testing.T) {
+		var p tests.Person
+		err := newDriver().Exec(WithProjection("detail")).
+			Match(db.Node(db.Qual(&p, "p"))).
+			Return(&p).
+			Run(context.Background())
+		assert.ErrorIs(t, err, ErrUnknownProjection)
+	})
+}
@@ -49,15 +49,38 @@ type (
 	Scope interface {
 		// Name returns the name of previously registered identifier.
 		Name(identifier Identifier) string
+		// Param adds value as a parameter of the query, returning its name,
+		// i.e. $v1.
+		Param(value any) string
 		// Error returns the error that occurred during the query.
 		Error() error
 		// AddError adds an error to the query.
 		AddError(err error)
+		// Escape backtick-quotes a label, relationship type or property name if
+		// it cannot be written as-is into the query.
+		Escape(name string) string
 	}
 
 	Expression interface {
 		Compile(s Scope, b *strings.Builder)
 	}
+
+	// Fragment is a reusable part of a query, such as a MATCH and WHERE chain,
+	// which can be spliced into any query with [Reader.Apply].
+	//
+	// Identifiers used by a fragment are resolved against the scope of the
+	// query it is applied to, so they take on the names they were given there.
+	// Fragments should therefore accept the identifiers they share with the
+	// surrounding query as arguments, rather than referring to them by name:
+	//
+	//	func CanRead(u *User, d *Document) query.Fragment {
+	//		return func(c query.Reader) query.Querier {
+	//			return c.
+	//				Match(db.Node(u).To(db.Var(nil, db.Label("MEMBER_OF")), db.Var(nil, db.Label("Group"))).
+	//					To(db.Var(nil, db.Label("CAN_READ")), d))
+	//		}
+	//	}
+	Fragment func(c Reader) Querier
 )
 
 // Query is the interface for constructing a Cypher query.
@@ -118,6 +141,12 @@ type Reader interface {
 	//  CALL <procedure>
 	Call(procedure string) Yielder
 
+	// CallProcedure writes a CALL clause invoking procedure with its arguments,
+	// followed by a YIELD clause if procedure yields any outputs.
+	//
+	//  CALL <procedure>(<arg>, ..., <arg>) [YIELD <identifier>, ..., <identifier>]
+	CallProcedure(procedure *internal.Procedure) Querier
+
 	// Show writes a SHOW clause to the query.
 	//
 	//  SHOW <command>
@@ -134,6 +163,9 @@ type Reader interface {
 	// about the querys current state.
 	Eval(expression Expression) Querier
 
+	// Apply splices fragment into the query at the current position.
+	Apply(fragment Fragment) Querier
+
 	// Unwind writes an UNWIND clause to the query.
 	//
 	// as is the name of the variable to which the list elements will be bound.
@@ -160,6 +192,25 @@ type Querier interface {
 
 	// Where writes a WHERE clause to the query.
 	Where(opts ...internal.WhereOption) Querier
+	// Using writes planner hints to the query, which must directly follow a
+	// MATCH or OPTIONAL MATCH clause (or other hints), otherwise the query
+	// fails with an error. Under
+	// [pkg/github.com/rlch/neogo.WithNamespace], node hints name the
+	// namespace label.
+	//
+	//  USING INDEX <variable>:<label>(<property>, ...)
+	//  USING SCAN <variable>:<label>
+	//  USING JOIN ON <variable>, ..., <variable>
+	Using(hints ...internal.Hint) Querier
+	// When applies then to the query if cond is true, allowing optional
+	// clauses to be written without breaking the chain.
+	//
+	//  c.Match(db.Node(&p)).
+	//  	When(name != "", func(c Querier) Querier {
+	//  		return c.Where(db.Cond(&p.Name, "=", name))
+	//  	}).
+	//  	Return(&p)
+	When(cond bool, then func(c Querier) Querier) Querier
 }
 
 // Updater is the interface for updating data in the database.
@@ -201,6 +252,14 @@ type Updater[To any] interface {
 // Runner allows the query to be executed.
 type Runner interface {
 	Print() Runner
+	// String returns the query, without running it.
+	String() string
+	// DebugString returns the query with its parameters inlined as literals,
+	// so it can be pasted into Neo4J Browser. Parameters whose names contain
+	// one of redact, or a sensitive name such as password or token, are
+	// replaced with '<redacted>'. Queries should always be run with
+	// parameters; this is for debugging only.
+	DebugString(redact ...string) string
 
 	// Run executes the query, populating all the values bound within the query if
 	// their identifiers exist in the returning scope.
@@ -225,6 +284,17 @@ type Runner interface {
 
 	// StreamWithParams is the same as Stream, but injects the provided parameters
 	StreamWithParams(ctx context.Context, params map[string]any, sink func(r Result) error) error
+	// Explain executes the query prefixed with EXPLAIN, returning the plan
+	// without running the query.
+	Explain(ctx context.Context) (*Plan, error)
+	// ExplainWithParams is the same as Explain, but injects the provided parameters.
+	ExplainWithParams(ctx context.Context, params map[string]any) (*Plan, error)
+	// Profile executes the query prefixed with PROFILE, returning the plan with
+	// the statistics collected while running the query. Values bound within the
+	// query are populated as with Run.
+	Profile(ctx context.Context) (*Plan, error)
+	// ProfileWithParams is the same as Profile, but injects the provided parameters.
+	ProfileWithParams(ctx context.Context, params map[string]any) (*Plan, error)
 }
 
 type (
@@ -244,4 +314,24 @@ type (
 		Read() error
 	}
 	ResultSummary = neo4j.ResultSummary
+
+	// Plan is an operator in the execution plan of a query, returned by Explain
+	// and Profile.
+	Plan struct {
+		Operator    string
+		Arguments   map[string]any
+		Identifiers []string
+		// EstimatedRows is the number of rows the planner estimates the operator
+		// will produce.
+		EstimatedRows float64
+		// The following are only populated by Profile.
+		DbHits          int64
+		Rows            int64
+		PageCacheHits   int64
+		PageCacheMisses int64
+		// Time is the time spent in the operator, in nanoseconds.
+		Time int64
+
+		Children []*Plan
+	}
 )
@@ -1,14 +1,17 @@
 package neogo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 	"github.com/spf13/cast"
 
 	"github.com/rlch/neogo/internal"
@@ -45,6 +48,113 @@ type registry struct {
 	abstractNodes []any
 	nodes         []any
 	relationships []any
+	// label/type -> old property key -> new property key
+	propertyAliases   map[string]map[string]string
+	onDeprecatedField func(DeprecatedField)
+	timeZone          *time.Location
+	zonelessTimeZone  *time.Location
+	nativeTimes       bool
+	snapshots         *snapshots
+	paramNamer        internal.ParamNamer
+	// clock returns the time entities are stamped with, if timestamps are
+	// enabled.
+	clock func() time.Time
+	// features are the feature flags consulted when compiling queries.
+	features FeatureFlags
+	// actor returns the ID of the actor running a query, if actor fields are
+	// enabled.
+	actor func(ctx context.Context) string
+	// defaultFuncs are the generators called by default tags, by name.
+	defaultFuncs map[string]func() any
+	// lazyDriver loads the Lazy associations of the values bound by the registry.
+	lazyDriver  Driver
+	projections projections
+	// concreteLabels caches the concrete labels of the registered abstract
+	// nodes and their implementers, by type.
+	concreteLabels map[reflect.Type][]string
+	// hooks are the unmarshal hooks binding values to pointers to their
+	// types, and the processors run on the values read.
+	hooks *hookSet
+	// converters convert the values of the types they are keyed by to the
+	// values stored, and back.
+	converters map[reflect.Type]TypeConverter
+	// encrypter encrypts the fields tagged with `neogo:"encrypt"`.
+	encrypter Encrypter
+	// includeZero writes the zero fields of the structs bound in patterns.
+	includeZero bool
+	// registeredOnly fails queries binding abstract node types which aren't
+	// registered.
+	registeredOnly bool
+	// tagChecker checks the tags of the types bound by queries, if runtime tag
+	// checks are enabled.
+	tagChecker *tagChecker
+	// blobStore stores the values of the fields tagged with
+	// `neogo:"external"` longer than blobThreshold bytes.
+	blobStore     BlobStore
+	blobThreshold int
+	// namespace is the label added to the nodes of entities, which is
+	// ignored when binding them.
+	namespace string
+}
+
+// warnDeprecatedFields returns the default handler of deprecated fields,
+// warning through logger the first time each field is read and written, or
+// nil without a logger.
+func warnDeprecatedFields(logger log.Logger) func(DeprecatedField) {
+	if logger == nil {
+		return nil
+	}
+	var warned sync.Map
+	return func(f DeprecatedField) {
+		if _, ok := warned.LoadOrStore(f, struct{}{}); ok {
+			return
+		}
+		op := "read"
+		if f.Write {
+			op = "written"
+		}
+		logger.Warnf(logName, "", "deprecated field %s.%s (%s) %s", f.Type, f.Field, f.Property, op)
+	}
+}
+
+// reportDeprecatedReads reports the deprecated fields of the type bound to
+// by to, which are present in props.
+func (r *registry) reportDeprecatedReads(props map[string]any, to reflect.Type) {
+	if r.onDeprecatedField == nil || len(props) == 0 {
+		return
+	}
+	for name, f := range internal.DeprecatedFields(unwindType(to)) {
+		if _, ok := props[name]; ok {
+			r.onDeprecatedField(f)
+		}
+	}
+}
+
+// entityProps prepares the properties of a node or relationship to be bound
+// to the type to.
+func (r *registry) entityProps(labels []string, props map[string]any, to reflect.Type) (map[string]any, error) {
+	props = r.aliasProps(labels, props, to)
+	r.reportDeprecatedReads(props, to)
+	props = r.temporalProps(props, to)
+	props = internal.DecodeProtobuf(unwindType(to), props)
+	return internal.DecodeNested(unwindType(to), props)
+}
+
+func (r *registry) reportDeprecatedWrites(fields []DeprecatedField) {
+	if r.onDeprecatedField == nil {
+		return
+	}
+	for _, f := range fields {
+		r.onDeprecatedField(f)
+	}
+}
+
+// setActor sets the actor fields written by cy to the actor running it.
+func (r *registry) setActor(ctx context.Context, cy *internal.CompiledCypher) {
+	if r.actor == nil || len(cy.ActorFields) == 0 {
+		return
+	}
+	cy.SetActor(r.actor(ctx))
 }
 
 func (r *registry) registerTypes(types ...any) {
@@ -57,9 +167,16 @@ func (r *registry) registerTypes(types ...any) {
 	if r.relationships == nil {
 		r.relationships = []any{}
 	}
+	if r.concreteLabels == nil {
+		r.concreteLabels = map[reflect.Type][]string{}
+	}
 	for _, t := range types {
-		if _, ok := t.(IAbstract); ok {
+		if abs, ok := t.(IAbstract); ok {
 			r.abstractNodes = append(r.abstractNodes, t)
+			r.concreteLabels[reflect.TypeOf(t)] = internal.ExtractConcreteNodeLabels(t)
+			for _, impl := range abs.Implementers() {
+				r.concreteLabels[reflect.TypeOf(impl)] = internal.ExtractConcreteNodeLabels(impl)
+			}
 			continue
 		}
 		if v, ok := t.(INode); ok {
@@ -73,6 +190,149 @@ func (r *registry) registerTypes(types ...any) {
 	}
 }
 
+// ErrUnregisteredType is returned by queries binding abstract node types
+// which aren't registered, once types are registered with [Register] or
+// [RegisterFromPackage].
+var ErrUnregisteredType = errors.New("abstract node type is not registered")
+
+// checkType checks t, a type bound by a query: its tags, if runtime tag checks
+// are enabled, and that it is registered, if it's an abstract node type and
+// only registered abstract node types can be bound.
+func (r *registry) checkType(t reflect.Type) error {
+	if r.tagChecker != nil {
+		if err := r.tagChecker.check(t); err != nil {
+			return err
+		}
+	}
+	if !r.registeredOnly {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Interface || !t.Implements(rAbstract) {
+		return nil
+	}
+	for _, base := range r.abstractNodes {
+		if reflect.TypeOf(base).Implements(t) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnregisteredType, t)
+}
+
+// checkTypes returns an error if any of types can't be registered, i.e. isn't
+// a node, abstract node or relationship, is an abstract node without labels to
+// tell its implementers apart, or has malformed validate tags.
+func checkTypes(types ...any) error {
+	var errs []error
+	for _, t := range types {
+		switch v := t.(type) {
+		case nil:
+			errs = append(errs, errors.New("cannot register nil type"))
+		case IAbstract:
+			if len(internal.ExtractConcreteNodeLabels(v)) == 0 {
+				errs = append(errs, fmt.Errorf("abstract node %T has no labels", v))
+			}
+			for _, impl := range v.Implementers() {
+				if len(internal.ExtractConcreteNodeLabels(impl)) == 0 {
+					errs = append(errs, fmt.Errorf("implementer %T of abstract node %T has no labels", impl, v))
+				}
+				errs = append(errs, internal.CheckValidateTags(reflect.TypeOf(impl)))
+			}
+			errs = append(errs, internal.CheckValidateTags(reflect.TypeOf(v)))
+		case INode, IRelationship:
+			errs = append(errs, internal.CheckValidateTags(reflect.TypeOf(v)))
+		default:
+			errs = append(errs, fmt.Errorf("%T is not a node, abstract node or relationship", t))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// labelsOf returns the concrete labels of the node v.
+func (r *registry) labelsOf(v any) []string {
+	if labels, ok := r.concreteLabels[reflect.TypeOf(v)]; ok {
+		return labels
+	}
+	return internal.ExtractConcreteNodeLabels(v)
+}
+
+// aliasProps renames properties stored under old keys to their new keys, as
+// configured by [WithPropertyAliases]. If both keys are present, the new key
+// takes precedence. Properties read into the type to are kept under their old
+// key if to has a field tagged with the old key but none with the new key, so
+// fields tagged with either key are bound; to is nil when writing.
+func (r *registry) aliasProps(labels []string, props map[string]any, to reflect.Type) map[string]any {
+	if len(r.propertyAliases) == 0 || props == nil {
+		return props
+	}
+	var fields map[string]reflect.Type
+	if to != nil {
+		fields = internal.PropertyTypes(to)
+	}
+	var out map[string]any
+	for _, label := range labels {
+		for oldKey, newKey := range r.propertyAliases[label] {
+			v, ok := props[newKey]
+			if !ok {
+				v, ok = props[oldKey]
+			}
+			if !ok {
+				continue
+			}
+			key := newKey
+			if _, ok := fields[oldKey]; ok {
+				if _, ok := fields[newKey]; !ok {
+					key = oldKey
+				}
+			}
+			if out == nil {
+				out = make(map[string]any, len(props))
+				for k, v := range props {
+					out[k] = v
+				}
+			}
+			delete(out, oldKey)
+			delete(out, newKey)
+			out[key] = v
+		}
+	}
+	if out == nil {
+		return props
+	}
+	return out
+}
+
+// aliasParams applies [registry.aliasProps] to canonicalized parameters whose
+// original value is a node or relationship (or a slice thereof).
+func (r *registry) aliasParams(params map[string]any, canon map[string]any) {
+	if len(r.propertyAliases) == 0 {
+		return
+	}
+	for k, v := range params {
+		var labels []string
+		if labels = internal.ExtractNodeLabels(v); labels == nil {
+			if typ := internal.ExtractRelationshipType(v); typ != "" {
+				labels = []string{typ}
+			}
+		}
+		if labels == nil {
+			continue
+		}
+		switch c := canon[k].(type) {
+		case map[string]any:
+			canon[k] = r.aliasProps(labels, c, nil)
+		case []any:
+			for i, e := range c {
+				if m, ok := e.(map[string]any); ok {
+					c[i] = r.aliasProps(labels, m, nil)
+				}
+			}
+		}
+	}
+}
+
 func unwindType(ptrTo reflect.Type) reflect.Type {
 	for ptrTo.Kind() == reflect.Ptr {
 		ptrTo = ptrTo.Elem()
@@ -99,6 +359,20 @@ func bindValuer[V neo4j.RecordValue](value V, bindTo reflect.Value) (ok bool, er
 	return true, nil
 }
 
+// bindTemporal binds the temporal value to bindTo, converting it to a
+// time.Time if bindTo is not a [Valuer] of it.
+func bindTemporal[V neo4j.RecordValue](r *registry, value V, bindTo reflect.Value) (bool, error) {
+	if ok, err := bindValuer(value, bindTo); ok || err != nil {
+		return ok, err
+	}
+	if v := unwindValue(bindTo); v.CanSet() && v.Type() == timeType {
+		t, _ := r.temporalTime(value)
+		v.Set(reflect.ValueOf(t))
+		return true, nil
+	}
+	return false, nil
+}
+
 func bindCasted[C any](
 	cast func(any) (C, error),
 	value any,
@@ -114,7 +388,7 @@ func bindCasted[C any](
 
 var emptyInterface = reflect.TypeOf((*any)(nil)).Elem()
 
-func (r *registry) bindValue(from any, to reflect.Value) (err error) {
+func (r *registry) bindValue(ctx context.Context, from any, to reflect.Value) (err error) {
 	toT := to.Type()
 	if to.Kind() == reflect.Ptr && toT.Elem() == emptyInterface {
 		to.Elem().Set(reflect.ValueOf(from))
@@ -124,6 +398,24 @@ func (r *registry) bindValue(from any, to reflect.Value) (err error) {
 		return nil
 	}
 
+	if ok, err := r.bindHook(from, to); ok {
+		return err
+	}
+	if ok, err := r.bindConverted(ctx, from, to); ok {
+		return err
+	}
+
+	// Nulls, such as those yielded by an OPTIONAL MATCH without a match, set
+	// pointers to nil and reset other values to their zero value, so values
+	// bound by a previous record don't linger. Slices are handled below, where a
+	// null is treated as a single record with a null hole.
+	if from == nil && to.Kind() == reflect.Ptr && !to.IsNil() {
+		if elem := to.Elem(); elem.Kind() != reflect.Slice && elem.CanSet() {
+			elem.Set(reflect.Zero(elem.Type()))
+			return nil
+		}
+	}
+
 	var ok bool
 	if from != nil {
 		handleSingleRecordToSlice := func(fromVal any) error {
@@ -132,7 +424,7 @@ func (r *registry) bindValue(from any, to reflect.Value) (err error) {
 				sliceV = sliceV.Elem()
 			}
 			sliceV.Set(reflect.MakeSlice(sliceV.Type(), 1, 1))
-			return r.bindValue(fromVal, sliceV.Index(0).Addr())
+			return r.bindValue(ctx, fromVal, sliceV.Index(0).Addr())
 		}
 		// Valuer through Node / relationship
 		switch fromVal := from.(type) {
@@ -157,9 +449,22 @@ func (r *registry) bindValue(from any, to reflect.Value) (err error) {
 				// We enforce that abstract nodes must be interfaces. Some hacking could
 				// relax this.
 				innerT.Kind() == reflect.Interface {
-				return r.bindAbstractNode(fromVal, to)
+				return r.bindAbstractNode(ctx, fromVal, to)
+			}
+			props, err := r.entityProps(fromVal.Labels, fromVal.Props, toT)
+			if err != nil {
+				return err
+			}
+			if err := r.bindValue(ctx, props, to); err != nil {
+				return err
 			}
-			return r.bindValue(fromVal.Props, to)
+			// Virtual nodes aren't stored, so they have no element ID to record
+			// nor properties to track changes of.
+			if !isVirtual(fromVal.Id, fromVal.ElementId) {
+				setElementID(to, fromVal.ElementId)
+				r.takeSnapshot(to)
+			}
+			return nil
 		case neo4j.Relationship:
 			// Handle 1 record of an expected slice of relationships
 			if unwindType(toT).Kind() == reflect.Slice {
@@ -172,7 +477,11 @@ func (r *registry) bindValue(from any, to reflect.Value) (err error) {
 			if ok {
 				return nil
 			}
-			return r.bindValue(fromVal.Props, to)
+			props, err := r.entityProps([]string{fromVal.Type}, fromVal.Props, toT)
+			if err != nil {
+				return err
+			}
+			return r.bindValue(ctx, props, to)
 		}
 
 		// Valuer throuh any other RecordValue
@@ -191,13 +500,13 @@ func (r *registry) bindValue(from any, to reflect.Value) (err error) {
 			case neo4j.Point3D:
 				return bindValuer(fromVal, to)
 			case neo4j.Date:
-				return bindValuer(fromVal, to)
+				return bindTemporal(r, fromVal, to)
 			case neo4j.LocalTime:
-				return bindValuer(fromVal, to)
+				return bindTemporal(r, fromVal, to)
 			case neo4j.LocalDateTime:
-				return bindValuer(fromVal, to)
+				return bindTemporal(r, fromVal, to)
 			case neo4j.Time:
-				return bindValuer(fromVal, to)
+				return bindTemporal(r, fromVal, to)
 			case neo4j.Duration:
 				return bindValuer(fromVal, to)
 			case time.Time:
@@ -243,14 +552,14 @@ func (r *registry) bindValue(from any, to reflect.Value) (err error) {
 					if toI.CanAddr() {
 						toI = toI.Addr()
 					}
-					err := r.bindValue(fromI, toI)
+					err := r.bindValue(ctx, fromI, toI)
 					if err != nil {
 						return fmt.Errorf("error binding slice element %d: %w", i, err)
 					}
 				}
 			} else if fromDepth+1 == toDepth {
 				to.Set(reflect.MakeSlice(toT, 1, 1))
-				err := r.bindValue(from, to.Index(0))
+				err := r.bindValue(ctx, from, to.Index(0))
 				if err != nil {
 					return fmt.Errorf("error binding value to first index of slice: %w", err)
 				}
@@ -323,10 +632,18 @@ func (r *registry) bindValue(from any, to reflect.Value) (err error) {
 		// Handle non-slice values (including nil) by creating a slice with one element
 		if from == nil || reflect.TypeOf(from).Kind() != reflect.Slice {
 			sliceV.Set(reflect.MakeSlice(sliceV.Type(), 1, 1))
-			return r.bindValue(from, sliceV.Index(0).Addr())
+			return r.bindValue(ctx, from, sliceV.Index(0).Addr())
 		}
 	}
 
+	bindConverted := func() error { return nil }
+	props, isProps := from.(map[string]any)
+	if isProps {
+		if props, err = r.resolveExternalProps(ctx, props, to); err != nil {
+			return err
+		}
+		from, bindConverted = r.popConvertedProps(ctx, props, to)
+	}
 	// PERF: Obviously huge performance hit here. Consider alternative ways of
 	// coercing between types. Might just need to be imperative and verbose
 	bytes, err := json.Marshal(from)
@@ -337,11 +654,31 @@ func (r *registry) bindValue(from any, to reflect.Value) (err error) {
 	if err != nil {
 		return err
 	}
+	if err := bindConverted(); err != nil {
+		return err
+	}
+	if isProps {
+		if err := internal.ValidateEnums(to.Interface()); err != nil {
+			return err
+		}
+		if err := r.decryptFields(to); err != nil {
+			return err
+		}
+	}
+	r.attachLazy(to)
 	return nil
 }
 
-func (r *registry) bindAbstractNode(node neo4j.Node, to reflect.Value) error {
+func (r *registry) bindAbstractNode(ctx context.Context, node neo4j.Node, to reflect.Value) error {
 	nodeLabels := node.Labels
+	if r.namespace != "" {
+		nodeLabels = make([]string, 0, len(node.Labels))
+		for _, label := range node.Labels {
+			if label != r.namespace {
+				nodeLabels = append(nodeLabels, label)
+			}
+		}
+	}
 	isNodeLabel := make(map[string]struct{}, len(nodeLabels))
 	for _, label := range nodeLabels {
 		isNodeLabel[label] = struct{}{}
@@ -372,7 +709,7 @@ func (r *registry) bindAbstractNode(node neo4j.Node, to reflect.Value) error {
 	if abs == nil {
 	Bases:
 		for _, base := range r.abstractNodes {
-			labels := internal.ExtractConcreteNodeLabels(base)
+			labels := r.labelsOf(base)
 			if len(labels) == 0 {
 				continue
 			}
@@ -409,7 +746,7 @@ func (r *registry) bindAbstractNode(node neo4j.Node, to reflect.Value) error {
 		}
 	Impls:
 		for _, nextImpl := range abs.(IAbstract).Implementers() {
-			for _, label := range internal.ExtractConcreteNodeLabels(nextImpl) {
+			for _, label := range r.labelsOf(nextImpl) {
 				if _, ok := isNodeLabel[label]; !ok {
 					continue Impls
 				}
@@ -425,7 +762,11 @@ func (r *registry) bindAbstractNode(node neo4j.Node, to reflect.Value) error {
 		)
 	}
 	toImpl := reflect.New(reflect.TypeOf(impl).Elem())
-	err := r.bindValue(node.Props, toImpl)
+	props, err := r.entityProps(node.Labels, node.Props, toImpl.Type())
+	if err != nil {
+		return err
+	}
+	err = r.bindValue(ctx, props, toImpl)
 	if err != nil {
 		return err
 	}
@@ -444,3 +785,33 @@ func computeDepth(t reflect.Type) (depth int) {
 	}
 	return
 }
+
+// isVirtual reports whether the node or relationship with the given IDs is
+// virtual, i.e. created by apoc.create.vNode or apoc.create.vRelationship to
+// return a graph which isn't stored. Virtual entities have negative IDs.
+func isVirtual(id int64, elementID string) bool {
+	return id < 0 || strings.HasPrefix(elementID, "-")
+}
+
+// setElementID records the element ID of the node bound to v, if it
+// implements [internal.ElementIDSetter].
+func setElementID(v reflect.Value, id string) {
+	if id == "" {
+		return
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		if setter, ok := v.Interface().(internal.ElementIDSetter); ok {
+			setter.SetElementID(id)
+			return
+		}
+		v = v.Elem()
+	}
+	if v.CanAddr() {
+		if setter, ok := v.Addr().Interface().(internal.ElementIDSetter); ok {
+			setter.SetElementID(id)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package neogo
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +14,8 @@ import (
 	"github.com/spf13/cast"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal"
 	"github.com/rlch/neogo/internal/tests"
 )
 
@@ -145,119 +151,119 @@ func TestBindValue(t *testing.T) {
 	t.Run("Primitive coercion", func(t *testing.T) {
 		t.Run("bool", func(t *testing.T) {
 			bindTo := false
-			err := r.bindValue(true, reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), true, reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.True(t, bindTo)
 		})
 
 		t.Run("string", func(t *testing.T) {
 			bindTo := "no"
-			err := r.bindValue(2.3, reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), 2.3, reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, "2.3", bindTo)
 		})
 
 		t.Run("int", func(t *testing.T) {
 			bindTo := 0
-			err := r.bindValue("10", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "10", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, 10, bindTo)
 		})
 
 		t.Run("int8", func(t *testing.T) {
 			bindTo := int8(0)
-			err := r.bindValue("100", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "100", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, int8(100), bindTo)
 		})
 
 		t.Run("int16", func(t *testing.T) {
 			bindTo := int16(0)
-			err := r.bindValue("20000", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "20000", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, int16(20000), bindTo)
 		})
 
 		t.Run("int32", func(t *testing.T) {
 			bindTo := int32(0)
-			err := r.bindValue("3000000", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "3000000", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, int32(3000000), bindTo)
 		})
 
 		t.Run("int64", func(t *testing.T) {
 			bindTo := int64(0)
-			err := r.bindValue("40000000000", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "40000000000", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, int64(40000000000), bindTo)
 		})
 
 		t.Run("uint", func(t *testing.T) {
 			bindTo := uint(0)
-			err := r.bindValue("500", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "500", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, uint(500), bindTo)
 		})
 
 		t.Run("uint8", func(t *testing.T) {
 			bindTo := uint8(0)
-			err := r.bindValue("200", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "200", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, uint8(200), bindTo)
 		})
 
 		t.Run("uint16", func(t *testing.T) {
 			bindTo := uint16(0)
-			err := r.bindValue("60000", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "60000", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, uint16(60000), bindTo)
 		})
 
 		t.Run("uint32", func(t *testing.T) {
 			bindTo := uint32(0)
-			err := r.bindValue("7000000", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "7000000", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, uint32(7000000), bindTo)
 		})
 
 		t.Run("uint64", func(t *testing.T) {
 			bindTo := uint64(0)
-			err := r.bindValue("80000000000", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "80000000000", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, uint64(80000000000), bindTo)
 		})
 
 		t.Run("float32", func(t *testing.T) {
 			bindTo := float32(0)
-			err := r.bindValue("3.14", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "3.14", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, float32(3.14), bindTo)
 		})
 
 		t.Run("float64", func(t *testing.T) {
 			bindTo := float64(0)
-			err := r.bindValue("2.718", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "2.718", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, float64(2.718), bindTo)
 		})
 
 		t.Run("[]int", func(t *testing.T) {
 			bindTo := []int{}
-			err := r.bindValue([]any{1, 2, 3}, reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), []any{1, 2, 3}, reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, []int{1, 2, 3}, bindTo)
 		})
 
 		t.Run("[]string", func(t *testing.T) {
 			bindTo := []string{}
-			err := r.bindValue([]any{"a", "b", "c"}, reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), []any{"a", "b", "c"}, reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			require.Equal(t, []string{"a", "b", "c"}, bindTo)
 		})
 
 		t.Run("time.Time", func(t *testing.T) {
 			bindTo := time.Time{}
-			err := r.bindValue("2023-08-04T12:00:00Z", reflect.ValueOf(&bindTo).Elem())
+			err := r.bindValue(context.Background(), "2023-08-04T12:00:00Z", reflect.ValueOf(&bindTo).Elem())
 			require.NoError(t, err)
 			expected, _ := time.Parse(time.RFC3339, "2023-08-04T12:00:00Z")
 			require.Equal(t, expected, bindTo)
@@ -267,28 +273,28 @@ func TestBindValue(t *testing.T) {
 	t.Run("Valuer", func(t *testing.T) {
 		t.Run("bool", func(t *testing.T) {
 			bindTo := &simpleValuer[bool]{}
-			err := r.bindValue(true, reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), true, reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.True(t, bindTo.Value)
 		})
 
 		t.Run("int64", func(t *testing.T) {
 			bindTo := &simpleValuer[int64]{}
-			err := r.bindValue(int64(100), reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), int64(100), reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, int64(100), bindTo.Value)
 		})
 
 		t.Run("string", func(t *testing.T) {
 			bindTo := &simpleValuer[string]{}
-			err := r.bindValue("hello", reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), "hello", reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, "hello", bindTo.Value)
 		})
 
 		t.Run("float64", func(t *testing.T) {
 			bindTo := &simpleValuer[float64]{}
-			err := r.bindValue(3.14, reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), 3.14, reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, 3.14, bindTo.Value)
 		})
@@ -296,7 +302,7 @@ func TestBindValue(t *testing.T) {
 		t.Run("time.Time", func(t *testing.T) {
 			inputTime := time.Date(2023, time.August, 4, 12, 0, 0, 0, time.UTC)
 			bindTo := &simpleValuer[time.Time]{}
-			err := r.bindValue(inputTime, reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), inputTime, reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, inputTime, bindTo.Value)
 		})
@@ -304,7 +310,7 @@ func TestBindValue(t *testing.T) {
 		t.Run("[]byte", func(t *testing.T) {
 			input := []byte{0x68, 0x65, 0x6c, 0x6c, 0x6f}
 			bindTo := &simpleValuer[[]byte]{}
-			err := r.bindValue(input, reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), input, reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, input, bindTo.Value)
 		})
@@ -312,7 +318,7 @@ func TestBindValue(t *testing.T) {
 		t.Run("[]any", func(t *testing.T) {
 			input := []any{1, "hello", true}
 			bindTo := &simpleValuer[[]any]{}
-			err := r.bindValue(input, reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), input, reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, input, bindTo.Value)
 		})
@@ -321,7 +327,7 @@ func TestBindValue(t *testing.T) {
 			input1 := []any{1.0, "hello", true}
 			input2 := []any{2.0, "bye", false}
 			var bindTo [][]any
-			err := r.bindValue([][]any{input1, input2}, reflect.ValueOf(&bindTo))
+			err := r.bindValue(context.Background(), [][]any{input1, input2}, reflect.ValueOf(&bindTo))
 			require.NoError(t, err)
 			require.Equal(t, input1, bindTo[0])
 			require.Equal(t, input2, bindTo[1])
@@ -330,7 +336,7 @@ func TestBindValue(t *testing.T) {
 		t.Run("map[string]any", func(t *testing.T) {
 			input := map[string]any{"name": "John", "age": 30}
 			bindTo := &simpleValuer[map[string]any]{}
-			err := r.bindValue(input, reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), input, reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, input, bindTo.Value)
 		})
@@ -342,7 +348,7 @@ func TestBindValue(t *testing.T) {
 				},
 			}
 			bindTo := &nodeValuer{}
-			err := r.bindValue(input, reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), input, reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, map[string]any{
 				"name": "Richard",
@@ -356,7 +362,7 @@ func TestBindValue(t *testing.T) {
 				},
 			}
 			bindTo := &relationshipValuer{}
-			err := r.bindValue(input, reflect.ValueOf(bindTo))
+			err := r.bindValue(context.Background(), input, reflect.ValueOf(bindTo))
 			require.NoError(t, err)
 			require.Equal(t, map[string]any{
 				"weight": 0.5,
@@ -366,7 +372,7 @@ func TestBindValue(t *testing.T) {
 
 	t.Run("Node", func(t *testing.T) {
 		to := &tests.Person{}
-		err := r.bindValue(neo4j.Node{
+		err := r.bindValue(context.Background(), neo4j.Node{
 			Labels: []string{"Person"},
 			Props: map[string]any{
 				"name":    "Richard",
@@ -384,7 +390,7 @@ func TestBindValue(t *testing.T) {
 
 	t.Run("Relationship", func(t *testing.T) {
 		to := &tests.ActedIn{}
-		err := r.bindValue(neo4j.Node{
+		err := r.bindValue(context.Background(), neo4j.Node{
 			Labels: []string{"ACTED_IN"},
 			Props: map[string]any{
 				"role": "Stuntman",
@@ -398,7 +404,7 @@ func TestBindValue(t *testing.T) {
 
 	t.Run("Abstract using base type", func(t *testing.T) {
 		var to tests.Organism = &tests.BaseOrganism{}
-		err := r.bindValue(neo4j.Node{
+		err := r.bindValue(context.Background(), neo4j.Node{
 			Labels: []string{"Human", "Organism"},
 			Props: map[string]any{
 				"name": "bruh",
@@ -417,7 +423,7 @@ func TestBindValue(t *testing.T) {
 		)
 
 		var to tests.Organism
-		err := rWithAbstract.bindValue(neo4j.Node{
+		err := rWithAbstract.bindValue(context.Background(), neo4j.Node{
 			Labels: []string{"Human", "Organism"},
 			Props: map[string]any{
 				"alive": true,
@@ -440,7 +446,7 @@ func TestBindValue(t *testing.T) {
 			&tests.Dog{},
 		)
 		var to tests.Organism
-		err := rWithAbstract.bindValue(neo4j.Node{
+		err := rWithAbstract.bindValue(context.Background(), neo4j.Node{
 			Labels: []string{"Human", "Organism"},
 			Props: map[string]any{
 				"alive": true,
@@ -458,7 +464,7 @@ func TestBindValue(t *testing.T) {
 
 	t.Run("Any", func(t *testing.T) {
 		to := new(any)
-		err := r.bindValue(neo4j.Node{
+		err := r.bindValue(context.Background(), neo4j.Node{
 			Labels: []string{"ACTED_IN"},
 			Props: map[string]any{
 				"role": "Stuntman",
@@ -473,3 +479,1129 @@ func TestBindValue(t *testing.T) {
 		}, *to)
 	})
 }
+
+func TestPropertyAliases(t *testing.T) {
+	r := &registry{
+		propertyAliases: map[string]map[string]string{
+			"Person":   {"lastName": "surname"},
+			"ACTED_IN": {"character": "role"},
+		},
+	}
+	// legacyPerson is still tagged with the old key.
+	type legacyPerson struct {
+		Node `neo4j:"Person"`
+
+		Name     string `json:"name"`
+		LastName string `json:"lastName"`
+	}
+
+	t.Run("binds old key to new field", func(t *testing.T) {
+		var to tests.Person
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Person"},
+			Props: map[string]any{
+				"name":     "Jesse",
+				"lastName": "Pinkman",
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, tests.Person{Name: "Jesse", Surname: "Pinkman"}, to)
+	})
+
+	t.Run("prefers new key when both are present", func(t *testing.T) {
+		var to tests.Person
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Person"},
+			Props: map[string]any{
+				"lastName": "Pinkman",
+				"surname":  "White",
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, tests.Person{Surname: "White"}, to)
+	})
+
+	t.Run("ignores other labels", func(t *testing.T) {
+		props := map[string]any{"lastName": "Pinkman"}
+		require.Equal(t, props, r.aliasProps([]string{"Movie"}, props, nil))
+	})
+
+	t.Run("binds old key on relationships", func(t *testing.T) {
+		var to tests.ActedIn
+		err := r.bindValue(context.Background(), neo4j.Relationship{
+			Type:  "ACTED_IN",
+			Props: map[string]any{"character": "Neo"},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, tests.ActedIn{Role: "Neo"}, to)
+	})
+
+	t.Run("binds either key to old field", func(t *testing.T) {
+		for _, key := range []string{"lastName", "surname"} {
+			var to legacyPerson
+			err := r.bindValue(context.Background(), neo4j.Node{
+				Labels: []string{"Person"},
+				Props:  map[string]any{key: "Pinkman"},
+			}, reflect.ValueOf(&to))
+			require.NoError(t, err)
+			require.Equal(t, legacyPerson{LastName: "Pinkman"}, to)
+		}
+	})
+
+	t.Run("emits new key in parameters", func(t *testing.T) {
+		params := map[string]any{
+			"p":      &legacyPerson{LastName: "Pinkman"},
+			"plain":  map[string]any{"lastName": "Pinkman"},
+			"people": []legacyPerson{{LastName: "Pinkman"}},
+		}
+		canon, err := canonicalizeParams(params)
+		require.NoError(t, err)
+		r.aliasParams(params, canon)
+		require.Equal(t, "Pinkman", canon["p"].(map[string]any)["surname"])
+		require.NotContains(t, canon["p"], "lastName")
+		require.Equal(t, map[string]any{"lastName": "Pinkman"}, canon["plain"])
+		require.Equal(t, "Pinkman", canon["people"].([]any)[0].(map[string]any)["surname"])
+		require.NotContains(t, canon["people"].([]any)[0], "lastName")
+	})
+
+	t.Run("emits new key in patterns", func(t *testing.T) {
+		s := &session{registry: *r}
+		p := legacyPerson{Name: "Jesse", LastName: "Pinkman"}
+		cy, err := s.newClient(internal.NewCypherClient()).
+			Create(db.Node(db.Qual(&p, "p"))).(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (p:Person {name: $p_name, surname: $p_surname})", cy.Cypher)
+		require.Equal(t, map[string]any{"p_name": "Jesse", "p_surname": "Pinkman"}, cy.Parameters)
+	})
+
+	t.Run("reads back what is written", func(t *testing.T) {
+		s := &session{registry: *r}
+		p := legacyPerson{Name: "Jesse", LastName: "Pinkman"}
+		cy, err := s.newClient(internal.NewCypherClient()).
+			Create(db.Node(db.Qual(&p, "p"))).(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		var to legacyPerson
+		err = r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Person"},
+			Props: map[string]any{
+				"name":    cy.Parameters["p_name"],
+				"surname": cy.Parameters["p_surname"],
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, p, to)
+	})
+
+	t.Run("prefers new field in patterns when both are present", func(t *testing.T) {
+		type migratingPerson struct {
+			Node `neo4j:"Person"`
+
+			LastName string `json:"lastName"`
+			Surname  string `json:"surname"`
+		}
+		s := &session{registry: *r}
+		p := migratingPerson{LastName: "Pinkman", Surname: "White"}
+		cy, err := s.newClient(internal.NewCypherClient()).
+			Create(db.Node(db.Qual(&p, "p"))).(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (p:Person {surname: $p_surname})", cy.Cypher)
+		require.Equal(t, map[string]any{"p_surname": "White"}, cy.Parameters)
+	})
+}
+
+func TestDeprecatedFieldReads(t *testing.T) {
+	type legacyPerson struct {
+		Node     `neo4j:"Person"`
+		Name     string `json:"name"`
+		Nickname string `json:"nickname" neogo:"deprecated"`
+	}
+	var reported []DeprecatedField
+	r := &registry{
+		onDeprecatedField: func(f DeprecatedField) {
+			reported = append(reported, f)
+		},
+	}
+
+	t.Run("reports deprecated properties that are read", func(t *testing.T) {
+		reported = nil
+		var to legacyPerson
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Person"},
+			Props:  map[string]any{"name": "Jesse", "nickname": "Cap'n Cook"},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, "Cap'n Cook", to.Nickname)
+		require.Equal(t, []DeprecatedField{{
+			Type:     reflect.TypeOf(legacyPerson{}),
+			Field:    "Nickname",
+			Property: "nickname",
+		}}, reported)
+	})
+
+	t.Run("does not report absent properties", func(t *testing.T) {
+		reported = nil
+		var to legacyPerson
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Person"},
+			Props:  map[string]any{"name": "Jesse"},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Empty(t, reported)
+	})
+}
+
+// warnLogger records the warnings logged through it.
+type warnLogger struct {
+	warnings []string
+}
+
+func (l *warnLogger) Error(name, id string, err error)                {}
+func (l *warnLogger) Infof(name, id string, msg string, args ...any)  {}
+func (l *warnLogger) Debugf(name, id string, msg string, args ...any) {}
+func (l *warnLogger) Warnf(name, id string, msg string, args ...any) {
+	l.warnings = append(l.warnings, fmt.Sprintf(msg, args...))
+}
+
+func TestWarnDeprecatedFields(t *testing.T) {
+	t.Run("does nothing without a logger", func(t *testing.T) {
+		require.Nil(t, warnDeprecatedFields(nil))
+	})
+
+	t.Run("warns once per field and operation", func(t *testing.T) {
+		logger := &warnLogger{}
+		warn := warnDeprecatedFields(logger)
+		f := DeprecatedField{
+			Type:     reflect.TypeOf(tests.Person{}),
+			Field:    "Name",
+			Property: "name",
+		}
+		warn(f)
+		warn(f)
+		f.Write = true
+		warn(f)
+		warn(f)
+		require.Equal(t, []string{
+			"deprecated field tests.Person.Name (name) read",
+			"deprecated field tests.Person.Name (name) written",
+		}, logger.warnings)
+	})
+}
+
+func TestNestedFields(t *testing.T) {
+	type (
+		address struct {
+			City    string `json:"city"`
+			Country string `json:"country"`
+		}
+		customer struct {
+			Node    `neo4j:"Customer"`
+			Name    string            `json:"name"`
+			Address address           `json:"address" neogo:"nested"`
+			Tags    map[string]string `json:"tags" neogo:"nested,json"`
+			Attrs   map[string]any    `json:"attrs" neogo:"flatten"`
+		}
+	)
+	r := &registry{}
+
+	t.Run("binds nested properties", func(t *testing.T) {
+		var to customer
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Customer"},
+			Props: map[string]any{
+				"name":            "Ada",
+				"address.city":    "London",
+				"address.country": "UK",
+				"tags":            `{"tier":"gold"}`,
+				"attrs_vip":       true,
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, customer{
+			Name:    "Ada",
+			Address: address{City: "London", Country: "UK"},
+			Tags:    map[string]string{"tier": "gold"},
+			Attrs:   map[string]any{"vip": true},
+		}, to)
+	})
+
+	t.Run("canonicalizes nested parameters", func(t *testing.T) {
+		c := customer{
+			Name:    "Ada",
+			Address: address{City: "London"},
+			Tags:    map[string]string{"tier": "gold"},
+			Attrs:   map[string]any{"vip": true},
+		}
+		params, err := canonicalizeParams(map[string]any{
+			"c":  c,
+			"cs": []customer{c},
+		})
+		require.NoError(t, err)
+		want := map[string]any{
+			"id":              "",
+			"name":            "Ada",
+			"address.city":    "London",
+			"address.country": "",
+			"tags":            `{"tier":"gold"}`,
+			"attrs_vip":       true,
+		}
+		require.Equal(t, want, params["c"])
+		require.Equal(t, []any{want}, params["cs"])
+	})
+
+	t.Run("merges nested properties from maps", func(t *testing.T) {
+		var c customer
+		since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		cy, err := (&session{}).newClient(internal.NewCypherClient()).
+			Match(db.Node(db.Qual(&c, "c"))).
+			Set(db.SetMerge(&c, map[string]any{
+				"address": address{City: "Paris"},
+				"attrs":   map[string]any{"vip": false},
+				"since":   since,
+			})).(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		require.Equal(t, "MATCH (c:Customer)\nSET c += $v1", cy.Cypher)
+		params, err := canonicalizeParams(cy.Parameters)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"address.city":    "Paris",
+			"address.country": "",
+			"attrs_vip":       false,
+			"since":           since,
+		}, params["v1"])
+	})
+}
+
+func TestJSONBlob(t *testing.T) {
+	type (
+		line struct {
+			SKU string `json:"sku"`
+			Qty int    `json:"qty"`
+		}
+		order struct {
+			Node  `neo4j:"Order"`
+			Lines []line         `json:"lines" neogo:"json_blob"`
+			Extra map[string]any `json:"extra" neogo:"json_blob"`
+		}
+	)
+	r := &registry{}
+
+	t.Run("binds JSON properties", func(t *testing.T) {
+		var to order
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Order"},
+			Props: map[string]any{
+				"lines": `[{"sku":"a","qty":2}]`,
+				"extra": `{"gift":true}`,
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, []line{{SKU: "a", Qty: 2}}, to.Lines)
+		require.Equal(t, map[string]any{"gift": true}, to.Extra)
+	})
+
+	t.Run("canonicalizes JSON properties", func(t *testing.T) {
+		params, err := canonicalizeParams(map[string]any{
+			"o": order{Lines: []line{{SKU: "a", Qty: 2}}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"id":    "",
+			"lines": `[{"qty":2,"sku":"a"}]`,
+		}, params["o"])
+	})
+
+	t.Run("injects JSON properties into patterns", func(t *testing.T) {
+		o := order{Lines: []line{{SKU: "a", Qty: 2}}}
+		cy, err := (&session{}).newClient(internal.NewCypherClient()).
+			Create(db.Node(db.Qual(&o, "o"))).(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE (o:Order {lines: $o_lines})", cy.Cypher)
+		params, err := canonicalizeParams(cy.Parameters)
+		require.NoError(t, err)
+		require.Equal(t, `[{"qty":2,"sku":"a"}]`, params["o_lines"])
+	})
+}
+
+func TestExtraProperties(t *testing.T) {
+	type product struct {
+		Node  `neo4j:"Product"`
+		Name  string         `json:"name"`
+		Extra map[string]any `json:"extra" neogo:"extra"`
+	}
+	r := &registry{}
+
+	t.Run("binds unmapped properties", func(t *testing.T) {
+		var to product
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Product"},
+			Props: map[string]any{
+				"id":     "1",
+				"name":   "Lamp",
+				"colour": "red",
+				"watts":  int64(40),
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, "Lamp", to.Name)
+		require.Equal(t, map[string]any{"colour": "red", "watts": float64(40)}, to.Extra)
+	})
+
+	t.Run("merges extra properties into props", func(t *testing.T) {
+		params, err := canonicalizeParams(map[string]any{
+			"p": product{Name: "Lamp", Extra: map[string]any{"colour": "red", "name": "ignored"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"id":     "",
+			"name":   "Lamp",
+			"colour": "red",
+		}, params["p"])
+	})
+
+	t.Run("injects extra properties into patterns", func(t *testing.T) {
+		p := product{Name: "Lamp", Extra: map[string]any{"colour": "red", "name": "ignored"}}
+		cy, err := (&session{}).newClient(internal.NewCypherClient()).
+			Merge(db.Node(db.Qual(&p, "p"))).(baseRunner).GetRunner().Compile()
+		require.NoError(t, err)
+		require.Equal(t, "MERGE (p:Product {colour: $p_extra_colour, name: $p_name})", cy.Cypher)
+		require.Equal(t, map[string]any{"p_extra_colour": "red", "p_name": "Lamp"}, cy.Parameters)
+	})
+}
+
+func TestProtobufFields(t *testing.T) {
+	type account struct {
+		Node        `neo4j:"Account"`
+		Handle      string `protobuf:"bytes,1,opt,name=handle,proto3"`
+		DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3"`
+	}
+	r := &registry{}
+
+	t.Run("binds properties named by protobuf tags", func(t *testing.T) {
+		var to account
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Account"},
+			Props: map[string]any{
+				"id":          "1",
+				"handle":      "ada",
+				"displayName": "Ada",
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, "1", to.ID)
+		require.Equal(t, "ada", to.Handle)
+		require.Equal(t, "Ada", to.DisplayName)
+	})
+
+	t.Run("canonicalizes protobuf parameters", func(t *testing.T) {
+		a := account{Handle: "ada", DisplayName: "Ada"}
+		params, err := canonicalizeParams(map[string]any{
+			"a":  a,
+			"as": []account{a},
+		})
+		require.NoError(t, err)
+		want := map[string]any{
+			"id":          "",
+			"handle":      "ada",
+			"displayName": "Ada",
+		}
+		require.Equal(t, want, params["a"])
+		require.Equal(t, []any{want}, params["as"])
+	})
+}
+
+func TestUnicodeProperties(t *testing.T) {
+	type fahrzeug struct {
+		Node  `neo4j:"Fahrzeug"`
+		Größe int               `json:"größe"`
+		Tags  map[string]string `json:"tags" neogo:"flatten"`
+	}
+	r := &registry{}
+	var to fahrzeug
+	err := r.bindValue(context.Background(), neo4j.Node{
+		Labels: []string{"Fahrzeug"},
+		Props: map[string]any{
+			"größe":   int64(3),
+			"tags_❤":  "a",
+			"tags_名前": "b",
+		},
+	}, reflect.ValueOf(&to))
+	require.NoError(t, err)
+	require.Equal(t, 3, to.Größe)
+	require.Equal(t, map[string]string{"❤": "a", "名前": "b"}, to.Tags)
+}
+
+func TestEmbeddings(t *testing.T) {
+	type document struct {
+		Node      `neo4j:"Document"`
+		Embedding []float32 `json:"embedding"`
+	}
+	r := &registry{}
+
+	t.Run("binds embeddings", func(t *testing.T) {
+		var to document
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Document"},
+			Props: map[string]any{
+				"embedding": []any{0.5, float64(float32(0.1)), -1.0},
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, []float32{0.5, 0.1, -1}, to.Embedding)
+	})
+
+	t.Run("canonicalizes embeddings", func(t *testing.T) {
+		params, err := canonicalizeParams(map[string]any{
+			"e": []float32{0.5, 0.1},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []float64{0.5, float64(float32(0.1))}, params["e"])
+	})
+}
+
+func TestTimeZones(t *testing.T) {
+	type event struct {
+		Node     `neo4j:"Event"`
+		StartsAt time.Time `json:"startsAt"`
+	}
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	local := neo4j.LocalDateTime(time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC))
+
+	t.Run("interprets zoneless values as UTC by default", func(t *testing.T) {
+		r := &registry{}
+		var to time.Time
+		require.NoError(t, r.bindValue(context.Background(), local, reflect.ValueOf(&to)))
+		require.Equal(t, time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC), to)
+	})
+
+	t.Run("interprets zoneless properties in the configured time zone", func(t *testing.T) {
+		r := &registry{zonelessTimeZone: sydney}
+		var to event
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Event"},
+			Props:  map[string]any{"startsAt": local},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.True(t, time.Date(2024, 1, 2, 9, 30, 0, 0, sydney).Equal(to.StartsAt))
+	})
+
+	t.Run("normalizes times on write", func(t *testing.T) {
+		at := time.Date(2024, 1, 2, 9, 30, 0, 0, sydney)
+		params := func() map[string]any {
+			canon, err := canonicalizeParams(map[string]any{
+				"at":    at,
+				"times": []any{at},
+				"props": map[string]any{"at": &at},
+				"n":     1,
+			})
+			require.NoError(t, err)
+			return canon
+		}
+
+		canon := params()
+		(&registry{}).normalizeTimeParams(canon)
+		require.Equal(t, "2024-01-01T22:30:00Z", canon["at"])
+		require.Equal(t, []any{"2024-01-01T22:30:00Z"}, canon["times"])
+		require.Equal(t, map[string]any{"at": "2024-01-01T22:30:00Z"}, canon["props"])
+		require.Equal(t, 1, canon["n"])
+
+		canon = params()
+		(&registry{timeZone: sydney}).normalizeTimeParams(canon)
+		require.Equal(t, "2024-01-02T09:30:00+11:00", canon["at"])
+
+		canon = params()
+		(&registry{timeZone: sydney, nativeTimes: true}).normalizeTimeParams(canon)
+		require.Equal(t, at, canon["at"])
+		require.Equal(t, sydney, canon["at"].(time.Time).Location())
+		require.Equal(t, []any{at}, canon["times"])
+	})
+}
+
+func TestTemporalTypes(t *testing.T) {
+	type shift struct {
+		Node     `neo4j:"Shift"`
+		Day      time.Time  `json:"day" neogo:"date"`
+		Opens    time.Time  `json:"opens" neogo:"localtime"`
+		Starts   *time.Time `json:"starts" neogo:"localdatetime"`
+		Closes   time.Time  `json:"closes" neogo:"time"`
+		Recorded time.Time  `json:"recorded"`
+	}
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	at := time.Date(2024, 1, 2, 9, 30, 15, 123456789, sydney)
+
+	t.Run("stores tagged fields as their temporal types", func(t *testing.T) {
+		params, err := canonicalizeParams(map[string]any{
+			"s": shift{Day: at, Opens: at, Starts: &at, Closes: at, Recorded: at},
+		})
+		require.NoError(t, err)
+		props := params["s"].(map[string]any)
+		require.Equal(t, neo4j.Date(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), props["day"])
+		require.Equal(t, neo4j.LocalTime(at), props["opens"])
+		require.Equal(t, neo4j.LocalDateTime(at), props["starts"])
+		require.Equal(t, neo4j.Time(at), props["closes"])
+		require.Equal(t, at, props["recorded"])
+	})
+
+	t.Run("binds temporal types to tagged fields", func(t *testing.T) {
+		r := &registry{zonelessTimeZone: sydney}
+		var to shift
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Shift"},
+			Props: map[string]any{
+				"day":      neo4j.Date(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+				"opens":    neo4j.LocalTime(time.Date(0, 1, 1, 9, 30, 15, 123456789, time.UTC)),
+				"starts":   neo4j.LocalDateTime(time.Date(2024, 1, 2, 9, 30, 15, 123456789, time.UTC)),
+				"closes":   neo4j.Time(at),
+				"recorded": at,
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.True(t, time.Date(2024, 1, 2, 0, 0, 0, 0, sydney).Equal(to.Day))
+		require.Equal(t, 123456789, to.Opens.Nanosecond())
+		require.True(t, at.Equal(*to.Starts))
+		require.True(t, at.Equal(to.Closes))
+		require.True(t, at.Equal(to.Recorded))
+	})
+
+	t.Run("binds dates to times", func(t *testing.T) {
+		r := &registry{}
+		var to time.Time
+		require.NoError(t, r.bindValue(context.Background(), neo4j.Date(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), reflect.ValueOf(&to)))
+		require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), to)
+	})
+}
+
+func TestPoints(t *testing.T) {
+	type store struct {
+		Node     `neo4j:"Store"`
+		Location neo4j.Point2D  `json:"location"`
+		Entrance *neo4j.Point3D `json:"entrance"`
+	}
+	location := neo4j.Point2D{X: 12.5, Y: 55.7, SpatialRefId: 4326}
+	entrance := neo4j.Point3D{X: 1, Y: 2, Z: 3, SpatialRefId: 9157}
+	r := &registry{}
+
+	t.Run("binds points", func(t *testing.T) {
+		var to store
+		err := r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Store"},
+			Props: map[string]any{
+				"location": location,
+				"entrance": entrance,
+			},
+		}, reflect.ValueOf(&to))
+		require.NoError(t, err)
+		require.Equal(t, location, to.Location)
+		require.Equal(t, &entrance, to.Entrance)
+	})
+
+	t.Run("canonicalizes points", func(t *testing.T) {
+		s := store{Location: location, Entrance: &entrance}
+		params, err := canonicalizeParams(map[string]any{
+			"p":  location,
+			"ps": []neo4j.Point2D{location},
+			"s":  s,
+			"ss": []store{s},
+		})
+		require.NoError(t, err)
+		want := map[string]any{
+			"id":       "",
+			"location": location,
+			"entrance": entrance,
+		}
+		require.Equal(t, location, params["p"])
+		require.Equal(t, []any{location}, params["ps"])
+		require.Equal(t, want, params["s"])
+		require.Equal(t, []any{want}, params["ss"])
+	})
+}
+
+func TestIsDirty(t *testing.T) {
+	node := neo4j.Node{
+		Labels: []string{"Person"},
+		Props: map[string]any{
+			"id":      "1",
+			"name":    "Jessie",
+			"surname": "Pinkman",
+		},
+	}
+
+	t.Run("always dirty without change tracking", func(t *testing.T) {
+		r := &registry{}
+		var p tests.Person
+		require.NoError(t, r.bindValue(context.Background(), node, reflect.ValueOf(&p)))
+		require.True(t, r.isDirty(&p))
+	})
+
+	t.Run("detects changes since read", func(t *testing.T) {
+		r := &registry{snapshots: newSnapshots(0)}
+		var p tests.Person
+		require.NoError(t, r.bindValue(context.Background(), node, reflect.ValueOf(&p)))
+		require.False(t, r.isDirty(&p))
+		require.False(t, r.isDirty(p))
+
+		p.Name = "Walter"
+		require.True(t, r.isDirty(&p))
+		p.Name = "Jessie"
+		require.False(t, r.isDirty(&p))
+
+		other := p
+		other.ID = "2"
+		require.True(t, r.isDirty(&other), "nodes which have not been read are dirty")
+	})
+
+	t.Run("snapshots slices of nodes", func(t *testing.T) {
+		r := &registry{snapshots: newSnapshots(0)}
+		var people []*tests.Person
+		s := &session{registry: *r}
+		err := s.unmarshalRecords(context.Background(), &internal.CompiledCypher{
+			Bindings: map[string]reflect.Value{"p": reflect.ValueOf(&people)},
+		}, []*neo4j.Record{
+			{Keys: []string{"p"}, Values: []any{node}},
+		})
+		require.NoError(t, err)
+		require.Len(t, people, 1)
+		require.False(t, r.isDirty(people[0]))
+	})
+
+	t.Run("forgets the least recently read nodes beyond the limit", func(t *testing.T) {
+		r := &registry{snapshots: newSnapshots(2)}
+		people := make([]tests.Person, 3)
+		for i := range people {
+			n := neo4j.Node{Labels: node.Labels, Props: map[string]any{"id": strconv.Itoa(i)}}
+			require.NoError(t, r.bindValue(context.Background(), n, reflect.ValueOf(&people[i])))
+		}
+		require.True(t, r.isDirty(&people[0]))
+		require.False(t, r.isDirty(&people[1]))
+		require.False(t, r.isDirty(&people[2]))
+		require.Len(t, r.snapshots.nodes, 2)
+	})
+
+	t.Run("releases snapshots", func(t *testing.T) {
+		r := &registry{snapshots: newSnapshots(0)}
+		var p, q tests.Person
+		require.NoError(t, r.bindValue(context.Background(), node, reflect.ValueOf(&p)))
+		r.releaseSnapshots([]*tests.Person{&p})
+		require.True(t, r.isDirty(&p))
+		require.Empty(t, r.snapshots.nodes)
+
+		require.NoError(t, r.bindValue(context.Background(), node, reflect.ValueOf(&q)))
+		r.releaseSnapshots(&q)
+		require.True(t, r.isDirty(&q))
+	})
+
+	t.Run("keys snapshots by tenant", func(t *testing.T) {
+		type invoice struct {
+			TenantNode `neo4j:"Invoice"`
+			Total      int `json:"total"`
+		}
+		r := &registry{snapshots: newSnapshots(0)}
+		invoices := make([]invoice, 2)
+		for i, tenant := range []string{"a", "b"} {
+			require.NoError(t, r.bindValue(context.Background(), neo4j.Node{
+				Labels: []string{"Invoice"},
+				Props:  map[string]any{"id": "1", "tenantId": tenant, "total": i},
+			}, reflect.ValueOf(&invoices[i])))
+		}
+		require.False(t, r.isDirty(&invoices[0]))
+		require.False(t, r.isDirty(&invoices[1]))
+	})
+}
+
+func TestBindElementID(t *testing.T) {
+	r := &registry{}
+	node := neo4j.Node{
+		ElementId: "4:c0a8:1",
+		Labels:    []string{"Person"},
+		Props:     map[string]any{"id": "p1", "name": "Ada"},
+	}
+
+	var p tests.Person
+	require.NoError(t, r.bindValue(context.Background(), node, reflect.ValueOf(&p)))
+	require.Equal(t, "p1", p.ID)
+	require.Equal(t, "4:c0a8:1", p.ElementID)
+
+	var people []*tests.Person
+	require.NoError(t, r.bindValue(context.Background(), []any{node}, reflect.ValueOf(&people)))
+	require.Len(t, people, 1)
+	require.Equal(t, "4:c0a8:1", people[0].ElementID)
+
+	canon, err := canonicalizeParams(map[string]any{"p": p})
+	require.NoError(t, err)
+	require.NotContains(t, canon["p"], "-", "element IDs are not written as properties")
+	cy, err := (&session{}).newClient(internal.NewCypherClient()).
+		Create(db.Node(db.Qual(&p, "p"))).(baseRunner).GetRunner().Compile()
+	require.NoError(t, err)
+	require.Equal(t, "CREATE (p:Person {id: $p_id, name: $p_name})", cy.Cypher)
+}
+
+func TestBindNull(t *testing.T) {
+	r := &registry{}
+	node := neo4j.Node{
+		Labels: []string{"Person"},
+		Props:  map[string]any{"id": "1", "name": "Jessie"},
+	}
+
+	t.Run("sets struct pointers to nil", func(t *testing.T) {
+		p := &tests.Person{Name: "Walter"}
+		require.NoError(t, r.bindValue(context.Background(), nil, reflect.ValueOf(&p)))
+		require.Nil(t, p)
+	})
+
+	t.Run("resets structs to their zero value", func(t *testing.T) {
+		p := tests.Person{Name: "Walter"}
+		require.NoError(t, r.bindValue(context.Background(), nil, reflect.ValueOf(&p)))
+		require.Equal(t, tests.Person{}, p)
+	})
+
+	t.Run("resets primitives to their zero value", func(t *testing.T) {
+		n := 42
+		require.NoError(t, r.bindValue(context.Background(), nil, reflect.ValueOf(&n)))
+		require.Equal(t, 0, n)
+	})
+
+	t.Run("keeps null holes in slices", func(t *testing.T) {
+		var people []*tests.Person
+		require.NoError(t, r.bindValue(context.Background(), []any{node, nil, node}, reflect.ValueOf(&people)))
+		require.Len(t, people, 3)
+		require.Equal(t, "Jessie", people[0].Name)
+		require.Nil(t, people[1])
+		require.Equal(t, "Jessie", people[2].Name)
+	})
+
+	t.Run("binds an optional match without a match", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{"p": node, "m": nil})
+		var (
+			p   tests.Person
+			mov = &tests.Movie{Title: "Stale"}
+		)
+		require.NoError(t, m.Exec().
+			Match(db.Node(db.Qual(&p, "p"))).
+			OptionalMatch(db.Node("p").To(db.Var(nil, db.Label("ACTED_IN")), db.Qual(&mov, "m"))).
+			Return(&p, &mov).
+			Run(context.Background()))
+		require.Equal(t, "Jessie", p.Name)
+		require.Nil(t, mov)
+	})
+}
+
+func TestTypedUnmarshalHooks(t *testing.T) {
+	type cents int64
+	cfg := &Config{}
+	WithTypedUnmarshalHook(func(from any, v *cents) error {
+		s, ok := from.(string)
+		if !ok {
+			return errors.New("expected a string")
+		}
+		f, err := cast.ToFloat64E(s)
+		*v = cents(f * 100)
+		return err
+	})(cfg)
+	r := &registry{hooks: newHookSet(cfg.UnmarshalHooks, nil)}
+
+	t.Run("binds values of the hooked type", func(t *testing.T) {
+		var c cents
+		require.NoError(t, r.bindValue(context.Background(), "12.34", reflect.ValueOf(&c)))
+		require.Equal(t, cents(1234), c)
+	})
+
+	t.Run("allocates nil pointers", func(t *testing.T) {
+		var c *cents
+		require.NoError(t, r.bindValue(context.Background(), "1", reflect.ValueOf(&c)))
+		require.Equal(t, cents(100), *c)
+	})
+
+	t.Run("binds the elements of slices", func(t *testing.T) {
+		var cs []cents
+		require.NoError(t, r.bindValue(context.Background(), []any{"1", "2.5"}, reflect.ValueOf(&cs)))
+		require.Equal(t, []cents{100, 250}, cs)
+	})
+
+	t.Run("returns errors of hooks", func(t *testing.T) {
+		var c cents
+		require.EqualError(t, r.bindValue(context.Background(), int64(1), reflect.ValueOf(&c)), "expected a string")
+	})
+
+	t.Run("ignores other types", func(t *testing.T) {
+		var n int64
+		require.NoError(t, r.bindValue(context.Background(), int64(7), reflect.ValueOf(&n)))
+		require.Equal(t, int64(7), n)
+	})
+}
+
+func TestBindVirtualEntities(t *testing.T) {
+	r := &registry{snapshots: newSnapshots(0)}
+	node := neo4j.Node{
+		Id:        -1,
+		ElementId: "-1",
+		Labels:    []string{"Person"},
+		Props:     map[string]any{"name": "Ada"},
+	}
+	rel := neo4j.Relationship{
+		Id:             -2,
+		ElementId:      "-2",
+		StartElementId: "-1",
+		EndElementId:   "-3",
+		Type:           "ACTED_IN",
+		Props:          map[string]any{"role": "Neo"},
+	}
+
+	var p tests.Person
+	require.NoError(t, r.bindValue(context.Background(), node, reflect.ValueOf(&p)))
+	require.Equal(t, "Ada", p.Name)
+	require.Empty(t, p.ElementID)
+	require.Empty(t, r.snapshots.nodes)
+
+	var actedIn tests.ActedIn
+	require.NoError(t, r.bindValue(context.Background(), rel, reflect.ValueOf(&actedIn)))
+	require.Equal(t, "Neo", actedIn.Role)
+
+	row := newRow(0, &neo4j.Record{Keys: []string{"p", "r"}, Values: []any{node, rel}})
+	require.Empty(t, row.ElementIDs)
+}
+
+func TestQueryUnmarshalHooks(t *testing.T) {
+	type (
+		locale   string
+		currency string
+	)
+	upper := func(from any, v *locale) error {
+		*v = locale(strings.ToUpper(from.(string)))
+		return nil
+	}
+	prefix := func(from any, v *locale) error {
+		*v = locale("en-" + from.(string))
+		return nil
+	}
+	upperCurrency := func(from any, v *currency) error {
+		*v = currency(strings.ToUpper(from.(string)))
+		return nil
+	}
+	run := func(configurers ...func(*execConfig)) (locale, currency) {
+		m := NewMock()
+		cfg := &Config{}
+		WithTypedUnmarshalHook(upper)(cfg)
+		WithTypedUnmarshalHook(upperCurrency)(cfg)
+		m.(*mockDriverImpl).hooks = newHookSet(cfg.UnmarshalHooks, nil)
+		m.Bind(map[string]any{"l": "gb", "c": "gbp"})
+		var (
+			l locale
+			c currency
+		)
+		require.NoError(t, m.Exec(configurers...).
+			Return(
+				db.Qual(&l, "u.locale", db.Name("l")),
+				db.Qual(&c, "u.currency", db.Name("c")),
+			).
+			Run(context.Background()))
+		return l, c
+	}
+
+	l, c := run()
+	require.Equal(t, locale("GB"), l)
+	require.Equal(t, currency("GBP"), c)
+	l, c = run(WithoutHooks())
+	require.Equal(t, locale("gb"), l)
+	require.Equal(t, currency("gbp"), c)
+	// The hook of the query replaces the driver's hook of its type only.
+	l, c = run(WithExtraUnmarshalHook(prefix))
+	require.Equal(t, locale("en-gb"), l)
+	require.Equal(t, currency("GBP"), c)
+	l, c = run(WithoutHooks(), WithExtraUnmarshalHook(prefix))
+	require.Equal(t, locale("en-gb"), l)
+	require.Equal(t, currency("gbp"), c)
+}
+
+func TestUnmarshalHookPriorities(t *testing.T) {
+	type locale string
+	set := func(name string) func(from any, v *locale) error {
+		return func(from any, v *locale) error {
+			*v = locale(name)
+			return nil
+		}
+	}
+	suffix := func(s string) func(from any, v *locale) error {
+		return func(from any, v *locale) error {
+			*v += locale(s)
+			return nil
+		}
+	}
+	cfg := &Config{}
+	WithUnmarshalHooks(
+		NewUnmarshalHook("region", 10, suffix("-GB")),
+		NewUnmarshalHook("base", 0, set("en")),
+	)(cfg)
+	WithUnmarshalHooks(NewUnmarshalHook("variant", 10, suffix("-oxendict")))(cfg)
+	WithTypedUnmarshalHook(suffix("!"))(cfg)
+	read := func(d Driver) locale {
+		d.(*mockDriverImpl).Bind(map[string]any{"l": "gb"})
+		var l locale
+		require.NoError(t, d.Exec().
+			Return(db.Qual(&l, "u.locale", db.Name("l"))).
+			Run(context.Background()))
+		return l
+	}
+
+	t.Run("runs hooks of a type in order of priority", func(t *testing.T) {
+		m := NewMock()
+		m.(*mockDriverImpl).hooks = newHookSet(cfg.UnmarshalHooks, nil)
+		require.Equal(t, locale("en!-GB-oxendict"), read(m))
+	})
+
+	t.Run("unregisters hooks of drivers in use", func(t *testing.T) {
+		m := NewMock()
+		m.(*mockDriverImpl).hooks = newHookSet(cfg.UnmarshalHooks, processors{
+			reflect.TypeOf(tests.Person{}): {TrimStrings()},
+		})
+		require.True(t, UnregisterHook(DriverWrapper{m}, "region"))
+		require.Equal(t, locale("en!-oxendict"), read(m))
+		require.False(t, UnregisterHook(m, "region"))
+
+		require.True(t, UnregisterHook(m, "trim"))
+		require.False(t, m.(*mockDriverImpl).hooks.hasProcessors())
+	})
+
+	t.Run("names failed hooks", func(t *testing.T) {
+		r := &registry{hooks: newHookSet([]UnmarshalHook{
+			NewUnmarshalHook("strict", 0, func(from any, v *locale) error {
+				return errors.New("unknown locale")
+			}),
+		}, nil)}
+		var l locale
+		require.EqualError(t, r.bindValue(context.Background(), "gb", reflect.ValueOf(&l)),
+			"unmarshal hook strict failed: unknown locale")
+	})
+}
+
+func TestRegisterTypes(t *testing.T) {
+	require.NoError(t, checkTypes(&tests.BasePet{}, &tests.Person{}, &tests.ActedIn{}))
+	require.EqualError(t, checkTypes(nil, 42, "Person"),
+		"cannot register nil type\n"+
+			"int is not a node, abstract node or relationship\n"+
+			"string is not a node, abstract node or relationship")
+
+	cfg := &Config{}
+	Register[tests.Person]()(cfg)
+	RegisterFromPackage(&tests.BasePet{}, &tests.ActedIn{})(cfg)
+	require.Equal(t, []any{&tests.Person{}, &tests.BasePet{}, &tests.ActedIn{}}, cfg.Types)
+
+	r := &registry{}
+	r.registerTypes(cfg.Types...)
+	require.Equal(t, []string{"Organism", "Pet"}, r.labelsOf(&tests.BasePet{}))
+	require.Contains(t, r.concreteLabels, reflect.TypeOf(&tests.Dog{}))
+
+	_, err := New("neo4j://localhost:7687", neo4j.NoAuth(), WithTypes("Person"))
+	require.EqualError(t, err, "failed to register types: string is not a node, abstract node or relationship")
+
+	type misspeltPerson struct {
+		Node `neo4j:"Person"`
+
+		Name string `json:"name" validate:"requird"`
+	}
+	require.EqualError(t, checkTypes(&misspeltPerson{}),
+		"invalid validate tag of misspeltPerson.Name: unknown validation rule: requird")
+}
+
+func TestRegisteredAbstractOnly(t *testing.T) {
+	newDriver := func(registeredOnly bool) mockDriver {
+		m := NewMock()
+		m.(*mockDriverImpl).registerTypes(&tests.BaseOrganism{})
+		m.(*mockDriverImpl).registeredOnly = registeredOnly
+		return m
+	}
+	compile := func(m mockDriver, identifier any) error {
+		_, err := m.Exec().
+			Match(db.Node(db.Qual(identifier, "n"))).
+			Return(identifier).(baseRunner).GetRunner().Compile()
+		return err
+	}
+
+	t.Run("fails queries binding unregistered abstract types", func(t *testing.T) {
+		var pets []tests.Pet
+		require.ErrorIs(t, compile(newDriver(true), &pets), ErrUnregisteredType)
+	})
+
+	t.Run("binds registered abstract types", func(t *testing.T) {
+		var o tests.Organism
+		require.NoError(t, compile(newDriver(true), &o))
+	})
+
+	t.Run("is only enabled by Register", func(t *testing.T) {
+		var pet tests.Pet
+		require.NoError(t, compile(newDriver(false), &pet))
+
+		cfg := &Config{}
+		WithTypes(&tests.BasePet{})(cfg)
+		require.False(t, cfg.RegisteredAbstractOnly)
+		Register[tests.BasePet]()(cfg)
+		require.True(t, cfg.RegisteredAbstractOnly)
+	})
+}
+
+type convertedLevel int
+
+const (
+	levelLow convertedLevel = iota
+	levelHigh
+)
+
+type convertedTask struct {
+	Node `neo4j:"Task"`
+
+	Name   string           `json:"name"`
+	Level  convertedLevel   `json:"level"`
+	Levels []convertedLevel `json:"levels"`
+}
+
+func TestTypeConverters(t *testing.T) {
+	names := []string{"low", "high"}
+	cfg := &Config{}
+	WithTypeConverter(
+		func(l convertedLevel) (string, error) { return names[l], nil },
+		func(s string) (convertedLevel, error) {
+			for i, name := range names {
+				if name == s {
+					return convertedLevel(i), nil
+				}
+			}
+			return 0, fmt.Errorf("unknown level %q", s)
+		},
+	)(cfg)
+	r := &registry{converters: cfg.TypeConverters}
+
+	t.Run("converts parameters", func(t *testing.T) {
+		task := convertedTask{Name: "Deploy", Level: levelHigh}
+		params, err := r.convertParams(context.Background(), map[string]any{
+			"level":  levelHigh,
+			"levels": []convertedLevel{levelLow, levelHigh},
+			"task":   &task,
+			"name":   "Deploy",
+		}, false)
+		require.NoError(t, err)
+		require.Equal(t, "high", params["level"])
+		require.Equal(t, []any{"low", "high"}, params["levels"])
+		require.Equal(t, "Deploy", params["name"])
+		props := params["task"].(map[string]any)
+		require.Equal(t, "high", props["level"])
+		require.Equal(t, "Deploy", props["name"])
+	})
+
+	t.Run("binds converted values", func(t *testing.T) {
+		var l convertedLevel
+		require.NoError(t, r.bindValue(context.Background(), "high", reflect.ValueOf(&l)))
+		require.Equal(t, levelHigh, l)
+
+		var task convertedTask
+		require.NoError(t, r.bindValue(context.Background(), neo4j.Node{
+			Labels: []string{"Task"},
+			Props:  map[string]any{"name": "Deploy", "level": "high", "levels": []any{"high", "low"}},
+		}, reflect.ValueOf(&task)))
+		require.Equal(t, "Deploy", task.Name)
+		require.Equal(t, levelHigh, task.Level)
+		require.Equal(t, []convertedLevel{levelHigh, levelLow}, task.Levels)
+
+		require.ErrorContains(t, r.bindValue(context.Background(), "medium", reflect.ValueOf(&l)), `unknown level "medium"`)
+	})
+}
@@ -0,0 +1,53 @@
+package neogo
+
+import "github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+// Row describes a single record returned by a query, passed to the callback
+// registered with [WithRowMetadata].
+type Row struct {
+	// Index is the position of the record within the result, starting at 0.
+	Index int
+	// Record is the raw record returned by the driver.
+	Record *neo4j.Record
+	// Keys are the names of the values returned in the record.
+	Keys []string
+	// ElementIDs maps each key whose value is a node or relationship to its
+	// element ID. Virtual nodes and relationships, which aren't stored, are
+	// omitted.
+	ElementIDs map[string]string
+}
+
+func newRow(index int, record *neo4j.Record) Row {
+	row := Row{
+		Index:      index,
+		Record:     record,
+		Keys:       record.Keys,
+		ElementIDs: map[string]string{},
+	}
+	for i, key := range record.Keys {
+		switch v := record.Values[i].(type) {
+		case neo4j.Node:
+			if !isVirtual(v.Id, v.ElementId) {
+				row.ElementIDs[key] = v.ElementId
+			}
+		case neo4j.Relationship:
+			if !isVirtual(v.Id, v.ElementId) {
+				row.ElementIDs[key] = v.ElementId
+			}
+		}
+	}
+	return row
+}
+
+// onRow calls the row metadata callback, if any, for each of records.
+func (s *session) onRow(offset int, records ...*neo4j.Record) error {
+	if s.execConfig.onRow == nil {
+		return nil
+	}
+	for i, record := range records {
+		if err := s.execConfig.onRow(newRow(offset+i, record)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rlch/neogo/db"
+)
+
+// ErrSchemaRequirements is returned by [RequireSchema] when the
+// database does not meet one or more requirements.
+var ErrSchemaRequirements = errors.New("schema requirements not met")
+
+// indexPollInterval is the interval at which WaitForIndexes polls the state of
+// the indexes.
+var indexPollInterval = 250 * time.Millisecond
+
+// SchemaRequirement is a requirement on the schema of the database, checked by
+// [RequireSchema].
+type SchemaRequirement interface {
+	// String describes the requirement.
+	String() string
+
+	check(ctx context.Context, s *schemaState) error
+}
+
+// RequireConstraint requires the constraint with the given name to exist.
+func RequireConstraint(name string) SchemaRequirement {
+	return constraintRequirement(name)
+}
+
+// RequireIndexOnline requires the index with the given name to exist and be
+// ONLINE, as opposed to still POPULATING or FAILED.
+func RequireIndexOnline(name string) SchemaRequirement {
+	return indexRequirement(name)
+}
+
+// RequireAPOC requires the APOC library to be installed, with a version of at
+// least minVersion, such as "5.12". An empty minVersion accepts any version.
+func RequireAPOC(minVersion string) SchemaRequirement {
+	return apocRequirement(minVersion)
+}
+
+// RequireProcedure requires the procedure with the given name, such as a
+// custom procedure deployed as a plugin, to be installed.
+func RequireProcedure(name string) SchemaRequirement {
+	return procedureRequirement(name)
+}
+
+// RequireFunction requires the user-defined function with the given name to
+// be installed.
+func RequireFunction(name string) SchemaRequirement {
+	return functionRequirement(name)
+}
+
+type (
+	constraintRequirement string
+	indexRequirement      string
+	apocRequirement       string
+	procedureRequirement  string
+	functionRequirement   string
+)
+
+func (r constraintRequirement) String() string {
+	return fmt.Sprintf("constraint %s exists", string(r))
+}
+
+func (r constraintRequirement) check(ctx context.Context, s *schemaState) error {
+	constraints, err := s.constraints(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := constraints[string(r)]; !ok {
+		return errors.New("constraint not found")
+	}
+	return nil
+}
+
+func (r indexRequirement) String() string {
+	return fmt.Sprintf("index %s is online", string(r))
+}
+
+func (r indexRequirement) check(ctx context.Context, s *schemaState) error {
+	indexes, err := s.indexes(ctx)
+	if err != nil {
+		return err
+	}
+	state, ok := indexes[string(r)]
+	if !ok {
+		return errors.New("index not found")
+	}
+	if state != "ONLINE" {
+		return fmt.Errorf("index is %s", state)
+	}
+	return nil
+}
+
+func (r apocRequirement) String() string {
+	if r == "" {
+		return "APOC is installed"
+	}
+	return fmt.Sprintf("APOC >= %s is installed", string(r))
+}
+
+func (r apocRequirement) check(ctx context.Context, s *schemaState) error {
+	version, err := s.apocVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("APOC is not available: %w", err)
+	}
+	if r != "" && compareVersions(version, string(r)) < 0 {
+		return fmt.Errorf("APOC %s is installed", version)
+	}
+	return nil
+}
+
+func (r procedureRequirement) String() string {
+	return fmt.Sprintf("procedure %s is installed", string(r))
+}
+
+func (r procedureRequirement) check(ctx context.Context, s *schemaState) error {
+	procedures, err := s.procedures(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := procedures[string(r)]; !ok {
+		return errors.New("procedure not found")
+	}
+	return nil
+}
+
+func (r functionRequirement) String() string {
+	return fmt.Sprintf("function %s is installed", string(r))
+}
+
+func (r functionRequirement) check(ctx context.Context, s *schemaState) error {
+	functions, err := s.functions(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := functions[string(r)]; !ok {
+		return errors.New("function not found")
+	}
+	return nil
+}
+
+// schemaState lazily loads the parts of the schema needed by the requirements
+// being checked, loading each at most once.
+type schemaState struct {
+	d Driver
+
+	constraintNames map[string]struct{}
+	indexStates     map[string]string
+	procedureNames  map[string]struct{}
+	functionNames   map[string]struct{}
+	apoc            *string
+}
+
+func (s *schemaState) constraints(ctx context.Context) (map[string]struct{}, error) {
+	return s.names(ctx, "CONSTRAINTS", &s.constraintNames)
+}
+
+func (s *schemaState) procedures(ctx context.Context) (map[string]struct{}, error) {
+	return s.names(ctx, "PROCEDURES", &s.procedureNames)
+}
+
+func (s *schemaState) functions(ctx context.Context) (map[string]struct{}, error) {
+	return s.names(ctx, "FUNCTIONS", &s.functionNames)
+}
+
+// names loads the names listed by SHOW <command> into cache, if not already
+// loaded.
+func (s *schemaState) names(ctx context.Context, command string, cache *map[string]struct{}) (map[string]struct{}, error) {
+	if *cache != nil {
+		return *cache, nil
+	}
+	var names []string
+	err := s.d.Exec().
+		Show(command).
+		Yield("name").
+		Return(db.Qual(&names, "collect(name)", db.Name("names"))).
+		Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list %s: %w", strings.ToLower(command), err)
+	}
+	*cache = make(map[string]struct{}, len(names))
+	for _, name := range names {
+		(*cache)[name] = struct{}{}
+	}
+	return *cache, nil
+}
+
+func (s *schemaState) indexes(ctx context.Context) (map[string]string, error) {
+	if s.indexStates != nil {
+		return s.indexStates, nil
+	}
+	var names, states []string
+	err := s.d.Exec().
+		Show("INDEXES").
+		Yield("name", "state").
+		Return(
+			db.Qual(&names, "collect(name)", db.Name("names")),
+			db.Qual(&states, "collect(state)", db.Name("states")),
+		).
+		Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list indexes: %w", err)
+	}
+	if len(names) != len(states) {
+		return nil, fmt.Errorf("cannot list indexes: got %d names and %d states", len(names), len(states))
+	}
+	s.indexStates = make(map[string]string, len(names))
+	for i, name := range names {
+		s.indexStates[name] = states[i]
+	}
+	return s.indexStates, nil
+}
+
+func (s *schemaState) apocVersion(ctx context.Context) (string, error) {
+	if s.apoc != nil {
+		return *s.apoc, nil
+	}
+	var version string
+	err := s.d.Exec().
+		Return(db.Qual(&version, "apoc.version()", db.Name("version"))).
+		Run(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.apoc = &version
+	return version, nil
+}
+
+// RequireSchema checks that the database of d meets each of the requirements,
+// returning an error wrapping [ErrSchemaRequirements] which reports every
+// unmet requirement otherwise. It is intended to be called at startup, so
+// services fail fast against misconfigured databases.
+//
+//	err := neogo.RequireSchema(ctx, d,
+//		neogo.RequireConstraint("person_id"),
+//		neogo.RequireIndexOnline("person_name"),
+//		neogo.RequireAPOC("5.12"),
+//		neogo.RequireProcedure("acme.recommend"),
+//	)
+func RequireSchema(ctx context.Context, d Driver, requirements ...SchemaRequirement) error {
+	impl := driverOf(d)
+	if impl == nil {
+		return ErrUnsupportedDriver
+	}
+	ctx, cancel := impl.schemaContext(ctx)
+	defer cancel()
+	s := &schemaState{d: d}
+	var unmet []string
+	for _, r := range requirements {
+		if err := r.check(ctx, s); err != nil {
+			unmet = append(unmet, fmt.Sprintf("  - %s: %v", r, err))
+		}
+	}
+	if len(unmet) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w:\n%s", ErrSchemaRequirements, strings.Join(unmet, "\n"))
+}
+
+// WaitForIndexes polls the indexes of the database of d until none are
+// populating, so queries run after creating indexes can make use of them. It
+// returns an error if any index fails to populate, or if the indexes are still
+// populating after timeout. A timeout of 0 waits until ctx is done, bounded by
+// the default schema timeout of [WithDefaultContextTimeout] if ctx has no
+// deadline.
+func WaitForIndexes(ctx context.Context, d Driver, timeout time.Duration) error {
+	impl := driverOf(d)
+	if impl == nil {
+		return ErrUnsupportedDriver
+	}
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = impl.schemaContext(ctx)
+	}
+	defer cancel()
+	ticker := time.NewTicker(indexPollInterval)
+	defer ticker.Stop()
+	for {
+		indexes, err := (&schemaState{d: d}).indexes(ctx)
+		if err != nil {
+			return err
+		}
+		var populating, failed []string
+		for name, state := range indexes {
+			switch state {
+			case "ONLINE":
+			case "FAILED":
+				failed = append(failed, name)
+			default:
+				populating = append(populating, name)
+			}
+		}
+		if len(failed) > 0 {
+			sort.Strings(failed)
+			return fmt.Errorf("indexes failed to populate: %s", strings.Join(failed, ", "))
+		}
+		if len(populating) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			sort.Strings(populating)
+			return fmt.Errorf("indexes still populating: %s: %w", strings.Join(populating, ", "), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// compareVersions compares dotted versions such as 5.12.0 numerically,
+// returning -1, 0 or 1. Missing segments are treated as 0, and non-numeric
+// suffixes such as -SNAPSHOT are ignored.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		av, bv := versionSegment(as, i), versionSegment(bs, i)
+		if av < bv {
+			return -1
+		} else if av > bv {
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionSegment(segments []string, i int) int {
+	if i >= len(segments) {
+		return 0
+	}
+	segment := segments[i]
+	if end := strings.IndexFunc(segment, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+		segment = segment[:end]
+	}
+	n, _ := strconv.Atoi(segment)
+	return n
+}
@@ -0,0 +1,115 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireSchema(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes when requirements are met", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{"names": []any{"person_id", "movie_id"}})
+		m.Bind(map[string]any{
+			"names":  []any{"person_name"},
+			"states": []any{"ONLINE"},
+		})
+		m.Bind(map[string]any{"version": "5.12.0"})
+
+		require.NoError(t, RequireSchema(ctx, m,
+			RequireConstraint("person_id"),
+			RequireConstraint("movie_id"),
+			RequireIndexOnline("person_name"),
+			RequireAPOC("5.12"),
+		))
+	})
+
+	t.Run("reports every unmet requirement", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{"names": []any{"person_id"}})
+		m.Bind(map[string]any{
+			"names":  []any{"person_name"},
+			"states": []any{"POPULATING"},
+		})
+		m.Bind(map[string]any{"version": "4.4.0.10"})
+
+		err := RequireSchema(ctx, m,
+			RequireConstraint("person_id"),
+			RequireConstraint("movie_id"),
+			RequireIndexOnline("person_name"),
+			RequireIndexOnline("movie_title"),
+			RequireAPOC("5.12"),
+		)
+		require.ErrorIs(t, err, ErrSchemaRequirements)
+		assert.Equal(t, `schema requirements not met:
+  - constraint movie_id exists: constraint not found
+  - index person_name is online: index is POPULATING
+  - index movie_title is online: index not found
+  - APOC >= 5.12 is installed: APOC 4.4.0.10 is installed`, err.Error())
+	})
+
+	t.Run("checks procedures and functions", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{"names": []any{"acme.recommend"}})
+		m.Bind(map[string]any{"names": []any{"acme.score"}})
+
+		err := RequireSchema(ctx, m,
+			RequireProcedure("acme.recommend"),
+			RequireProcedure("acme.reindex"),
+			RequireFunction("acme.score"),
+		)
+		require.ErrorIs(t, err, ErrSchemaRequirements)
+		assert.Equal(t, `schema requirements not met:
+  - procedure acme.reindex is installed: procedure not found`, err.Error())
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("5.12", "5.12.0"))
+	assert.Equal(t, -1, compareVersions("5.9.0", "5.12"))
+	assert.Equal(t, 1, compareVersions("5.13.0-SNAPSHOT", "5.12"))
+	assert.Equal(t, 1, compareVersions("4.4.0.10", "4.4"))
+}
+
+func TestWaitForIndexes(t *testing.T) {
+	ctx := context.Background()
+	defer func(interval time.Duration) { indexPollInterval = interval }(indexPollInterval)
+	indexPollInterval = time.Millisecond
+
+	t.Run("waits until indexes are online", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{
+			"names":  []any{"person_name", "movie_title"},
+			"states": []any{"ONLINE", "POPULATING"},
+		})
+		m.Bind(map[string]any{
+			"names":  []any{"person_name", "movie_title"},
+			"states": []any{"ONLINE", "ONLINE"},
+		})
+		require.NoError(t, WaitForIndexes(ctx, m, time.Second))
+	})
+
+	t.Run("errors when an index fails", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{
+			"names":  []any{"person_name"},
+			"states": []any{"FAILED"},
+		})
+		assert.EqualError(t, WaitForIndexes(ctx, m, time.Second), "indexes failed to populate: person_name")
+	})
+
+	t.Run("errors on timeout", func(t *testing.T) {
+		m := NewMock()
+		m.Bind(map[string]any{
+			"names":  []any{"person_name"},
+			"states": []any{"POPULATING"},
+		})
+		err := WaitForIndexes(ctx, m, time.Nanosecond)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
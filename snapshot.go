@@ -0,0 +1,178 @@
+package neogo
+
+import (
+	"container/list"
+	"hash/fnv"
+	"reflect"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// defaultSnapshotLimit is the number of nodes snapshotted by
+// [WithChangeTracking] by default.
+const defaultSnapshotLimit = 10_000
+
+type (
+	// snapshots holds a hash per property of the nodes read from the database,
+	// so changes made to them since can be detected. At most limit nodes are
+	// held, forgetting those read least recently first.
+	snapshots struct {
+		mu    sync.Mutex
+		limit int
+		nodes map[snapshotKey]*list.Element
+		// order holds the snapshots, most recently read first.
+		order *list.List
+	}
+	snapshotKey struct {
+		t        reflect.Type
+		tenantID string
+		id       string
+	}
+	snapshot struct {
+		key    snapshotKey
+		hashes map[string]uint64
+	}
+)
+
+func newSnapshots(limit int) *snapshots {
+	if limit <= 0 {
+		limit = defaultSnapshotLimit
+	}
+	return &snapshots{
+		limit: limit,
+		nodes: map[snapshotKey]*list.Element{},
+		order: list.New(),
+	}
+}
+
+func (s *snapshots) put(key snapshotKey, hashes map[string]uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.nodes[key]; ok {
+		e.Value.(*snapshot).hashes = hashes
+		s.order.MoveToFront(e)
+		return
+	}
+	s.nodes[key] = s.order.PushFront(&snapshot{key: key, hashes: hashes})
+	for s.order.Len() > s.limit {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.nodes, oldest.Value.(*snapshot).key)
+	}
+}
+
+func (s *snapshots) get(key snapshotKey) (map[string]uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.nodes[key]
+	if !ok {
+		return nil, false
+	}
+	return e.Value.(*snapshot).hashes, true
+}
+
+func (s *snapshots) release(key snapshotKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.nodes[key]; ok {
+		s.order.Remove(e)
+		delete(s.nodes, key)
+	}
+}
+
+// snapshotKeyOf returns the key of the node v, if it is a node with an ID.
+func snapshotKeyOf(v reflect.Value) (snapshotKey, reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return snapshotKey{}, v, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return snapshotKey{}, v, false
+	}
+	node, ok := v.Interface().(INode)
+	if !ok || node.GetID() == "" {
+		return snapshotKey{}, v, false
+	}
+	key := snapshotKey{t: v.Type(), id: node.GetID()}
+	if tenant, ok := node.(interface{ GetTenantID() string }); ok {
+		key.tenantID = tenant.GetTenantID()
+	}
+	return key, v, true
+}
+
+// snapshotNode returns the key and property hashes of the node v, if it is a
+// node with an ID.
+func snapshotNode(v reflect.Value) (snapshotKey, map[string]uint64, bool) {
+	key, v, ok := snapshotKeyOf(v)
+	if !ok {
+		return snapshotKey{}, nil, false
+	}
+	canon, err := canonicalizeParams(map[string]any{"n": v.Interface()})
+	if err != nil {
+		return snapshotKey{}, nil, false
+	}
+	props, _ := canon["n"].(map[string]any)
+	hashes := make(map[string]uint64, len(props))
+	for name, prop := range props {
+		bytes, err := json.Marshal(prop)
+		if err != nil {
+			return snapshotKey{}, nil, false
+		}
+		h := fnv.New64a()
+		_, _ = h.Write(bytes)
+		hashes[name] = h.Sum64()
+	}
+	return key, hashes, true
+}
+
+// takeSnapshot records the properties of the node bound to v, if change
+// tracking is enabled.
+func (r *registry) takeSnapshot(v reflect.Value) {
+	if r.snapshots == nil {
+		return
+	}
+	key, hashes, ok := snapshotNode(v)
+	if !ok {
+		return
+	}
+	r.snapshots.put(key, hashes)
+}
+
+func (r *registry) isDirty(entity any) bool {
+	if r.snapshots == nil {
+		return true
+	}
+	key, hashes, ok := snapshotNode(reflect.ValueOf(entity))
+	if !ok {
+		return true
+	}
+	snapshot, ok := r.snapshots.get(key)
+	if !ok || len(snapshot) != len(hashes) {
+		return true
+	}
+	for name, hash := range hashes {
+		if snapshot[name] != hash {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *registry) releaseSnapshots(entities ...any) {
+	if r.snapshots == nil {
+		return
+	}
+	for _, entity := range entities {
+		key, v, ok := snapshotKeyOf(reflect.ValueOf(entity))
+		if ok {
+			r.snapshots.release(key)
+		} else if v.Kind() == reflect.Slice {
+			for i := 0; i < v.Len(); i++ {
+				r.releaseSnapshots(v.Index(i).Interface())
+			}
+		}
+	}
+}
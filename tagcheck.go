@@ -0,0 +1,92 @@
+package neogo
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/rlch/neogo/internal"
+)
+
+type (
+	// TagConflict describes a field whose tags disagree on the name of its
+	// property, such as a db tag naming a different property than its json
+	// tag. See [WithTagCheck].
+	TagConflict = internal.TagConflict
+
+	// TagConflicts are the fields of a type whose tags disagree.
+	//
+	//  var conflicts neogo.TagConflicts
+	//  if errors.As(err, &conflicts) {
+	//  	for _, c := range conflicts {
+	//  		fmt.Println(c.Type, c.Field, c.Reason)
+	//  	}
+	//  }
+	TagConflicts = internal.TagConflicts
+)
+
+// WithTagCheck is an option for [New] that checks the tags of the registered
+// types, failing New with [TagConflicts] if any fields have tags which disagree
+// on the names of their properties. Properties are only named by json tags, so
+// structs shared with database/sql mappers drift silently when their db tags
+// are renamed:
+//
+//	type Person struct {
+//		neogo.Node `neo4j:"Person"`
+//
+//		Name string `json:"name" db:"full_name"` // conflict
+//	}
+//
+// Fields whose properties differ only in case also conflict, as they are
+// written by their exact names but read case-insensitively.
+//
+// If runtime is set, the types bound by each query are also checked the first
+// time they are used, failing the queries binding types with conflicts.
+func WithTagCheck(runtime bool) Configurer {
+	return func(c *Config) {
+		c.TagCheck = true
+		c.RuntimeTagCheck = runtime
+	}
+}
+
+// checkTags checks the tags of the registered types, along with the
+// implementers of abstract nodes.
+func checkTags(types ...any) error {
+	var conflicts TagConflicts
+	for _, t := range types {
+		conflicts = append(conflicts, internal.CheckTags(reflect.TypeOf(t))...)
+		if abs, ok := t.(IAbstract); ok {
+			for _, impl := range abs.Implementers() {
+				conflicts = append(conflicts, internal.CheckTags(reflect.TypeOf(impl))...)
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return conflicts
+}
+
+// tagChecker checks the tags of the types bound by queries, caching the
+// conflicts of each type.
+type tagChecker struct {
+	checked sync.Map // reflect.Type -> error
+}
+
+func (tc *tagChecker) check(t reflect.Type) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if checked, ok := tc.checked.Load(t); ok {
+		err, _ := checked.(error)
+		return err
+	}
+	var err error
+	if conflicts := internal.CheckTags(t); len(conflicts) > 0 {
+		err = conflicts
+	}
+	tc.checked.Store(t, err)
+	return err
+}
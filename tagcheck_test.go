@@ -0,0 +1,45 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+type sharedPerson struct {
+	Node `neo4j:"SharedPerson"`
+
+	Name string `json:"name" db:"full_name"`
+}
+
+func TestTagCheck(t *testing.T) {
+	t.Run("checks registered types", func(t *testing.T) {
+		require.NoError(t, checkTags(&tests.Person{}))
+		err := checkTags(&tests.Person{}, &sharedPerson{})
+		var conflicts TagConflicts
+		require.True(t, errors.As(err, &conflicts))
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, "Name", conflicts[0].Field)
+	})
+
+	t.Run("checks types bound by queries", func(t *testing.T) {
+		m := NewMock()
+		m.(*mockDriverImpl).tagChecker = &tagChecker{}
+		m.Bind(map[string]any{})
+		ctx := context.Background()
+		p := tests.Person{Name: "Ada"}
+		require.NoError(t, m.Exec().Create(db.Node(&p)).Run(ctx))
+
+		shared := sharedPerson{Name: "Ada"}
+		err := m.Exec().Create(db.Node(&shared)).Run(ctx)
+		var conflicts TagConflicts
+		require.True(t, errors.As(err, &conflicts))
+		assert.Equal(t, "Name", conflicts[0].Field)
+	})
+}
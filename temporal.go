@@ -0,0 +1,110 @@
+package neogo
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/rlch/neogo/internal"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (r *registry) writeLocation() *time.Location {
+	if r.timeZone == nil {
+		return time.UTC
+	}
+	return r.timeZone
+}
+
+func (r *registry) zonelessLocation() *time.Location {
+	if r.zonelessTimeZone == nil {
+		return time.UTC
+	}
+	return r.zonelessTimeZone
+}
+
+// temporalTime converts v, a temporal value other than a DateTime, to a
+// time.Time. Zoneless values are interpreted in the time zone configured by
+// [WithZonelessTimeZone]; times keep their offset.
+func (r *registry) temporalTime(v any) (time.Time, bool) {
+	var t time.Time
+	loc := r.zonelessLocation()
+	switch v := v.(type) {
+	case neo4j.LocalDateTime:
+		t = time.Time(v)
+	case neo4j.Date:
+		t = time.Time(v)
+	case neo4j.LocalTime:
+		t = time.Time(v)
+	case neo4j.Time:
+		return time.Time(v), true
+	default:
+		return time.Time{}, false
+	}
+	return time.Date(
+		t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		loc,
+	), true
+}
+
+// normalizeTimeParams converts the times within the canonicalized parameters
+// to the time zone configured by [WithTimeZone]. Unless [WithNativeTimes] is
+// set, they are then written as RFC 3339 strings.
+func (r *registry) normalizeTimeParams(canon map[string]any) {
+	for k, v := range canon {
+		canon[k] = r.normalizeTime(v)
+	}
+}
+
+func (r *registry) normalizeTime(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		t := v.In(r.writeLocation())
+		if r.nativeTimes {
+			return t
+		}
+		return t.Format(time.RFC3339Nano)
+	case []any:
+		for i, e := range v {
+			v[i] = r.normalizeTime(e)
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = r.normalizeTime(e)
+		}
+	}
+	return v
+}
+
+// temporalProps converts the temporal properties bound to time.Time fields of
+// the type to, so they can be unmarshalled. Fields may be stored as any of the
+// temporal types; see [internal.TemporalValue].
+func (r *registry) temporalProps(props map[string]any, to reflect.Type) map[string]any {
+	var out map[string]any
+	for name, t := range internal.PropertyTypes(unwindType(to)) {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t != timeType {
+			continue
+		}
+		converted, ok := r.temporalTime(props[name])
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(props))
+			for k, v := range props {
+				out[k] = v
+			}
+		}
+		out[name] = converted
+	}
+	if out == nil {
+		return props
+	}
+	return out
+}
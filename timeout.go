@@ -0,0 +1,93 @@
+package neogo
+
+import (
+	"context"
+	"time"
+)
+
+// ContextTimeouts are the default timeouts of the operations run with a
+// context without a deadline. A timeout of 0 leaves its operations unbounded.
+type ContextTimeouts struct {
+	// Read bounds read queries and transactions.
+	Read time.Duration
+	// Write bounds write queries and transactions.
+	Write time.Duration
+	// Schema bounds [RequireSchema] and [WaitForIndexes].
+	Schema time.Duration
+}
+
+// WithDefaultContextTimeout is an option for [New] that bounds the operations
+// run with a context without a deadline, so a forgotten deadline doesn't
+// leave unbounded work running on the server:
+//
+//	neogo.WithDefaultContextTimeout(neogo.ContextTimeouts{
+//		Read:   10 * time.Second,
+//		Write:  30 * time.Second,
+//		Schema: 5 * time.Minute,
+//	})
+//
+// Contexts with a deadline are left as they are. The timeout of a single query
+// is overridden with [WithTimeout].
+func WithDefaultContextTimeout(timeouts ContextTimeouts) Configurer {
+	return func(c *Config) {
+		c.DefaultContextTimeouts = timeouts
+	}
+}
+
+// WithTimeout configures Exec() to bound the query by timeout, in place of the
+// default of [WithDefaultContextTimeout]. Unlike the default, it also shortens
+// the deadline of a context which has one. A negative timeout leaves the query
+// unbounded by the default.
+func WithTimeout(timeout time.Duration) func(ec *execConfig) {
+	return func(ec *execConfig) {
+		ec.timeout = timeout
+	}
+}
+
+// withTimeout bounds ctx by timeout if it has no deadline.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withDeadline bounds ctx by deadline, unless it is zero.
+func withDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, deadline)
+}
+
+// txContext bounds ctx by the default timeout of the transactions with the
+// access mode write reports.
+func (d *driver) txContext(ctx context.Context, write bool) (context.Context, context.CancelFunc) {
+	if d == nil {
+		return ctx, func() {}
+	}
+	if write {
+		return withTimeout(ctx, d.timeouts.Write)
+	}
+	return withTimeout(ctx, d.timeouts.Read)
+}
+
+// schemaContext bounds ctx by the default timeout of schema operations.
+func (d *driver) schemaContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, d.timeouts.Schema)
+}
+
+// queryContext bounds ctx by the timeout of the query run by c, which is write
+// if write is set.
+func (c *runnerImpl) queryContext(ctx context.Context, write bool) (context.Context, context.CancelFunc) {
+	switch timeout := c.execConfig.timeout; {
+	case timeout > 0:
+		return context.WithTimeout(ctx, timeout)
+	case timeout < 0:
+		return ctx, func() {}
+	}
+	return c.txContext(ctx, write)
+}
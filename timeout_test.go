@@ -0,0 +1,104 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+	"github.com/rlch/neogo/query"
+)
+
+func TestDefaultContextTimeout(t *testing.T) {
+	newDriver := func() (mockDriver, func() []time.Duration) {
+		m := NewMock()
+		impl := m.(*mockDriverImpl)
+		impl.timeouts = ContextTimeouts{
+			Read:  time.Minute,
+			Write: time.Hour,
+		}
+		// remaining returns the time left to the statements run, when they
+		// were run.
+		remaining := func() []time.Duration {
+			var out []time.Duration
+			for _, ctx := range impl.Runs {
+				deadline, ok := ctx.Deadline()
+				if !ok {
+					out = append(out, 0)
+					continue
+				}
+				out = append(out, time.Until(deadline).Round(time.Minute))
+			}
+			return out
+		}
+		return m, remaining
+	}
+
+	t.Run("bounds queries by access mode", func(t *testing.T) {
+		d, remaining := newDriver()
+		d.Bind(map[string]any{"p": &tests.Person{}})
+		d.Bind(map[string]any{})
+		ctx := context.Background()
+		var p tests.Person
+		require.NoError(t, d.Exec().Match(db.Node(db.Qual(&p, "p"))).Return(&p).Run(ctx))
+		require.NoError(t, d.Exec().Create(db.Node(&p)).Run(ctx))
+		assert.Equal(t, []time.Duration{time.Minute, time.Hour}, remaining())
+	})
+
+	t.Run("keeps deadlines of contexts", func(t *testing.T) {
+		d, remaining := newDriver()
+		d.Bind(map[string]any{})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		var p tests.Person
+		require.NoError(t, d.Exec().Create(db.Node(&p)).Run(ctx))
+		assert.Equal(t, []time.Duration{5 * time.Minute}, remaining())
+	})
+
+	t.Run("overrides the default per query", func(t *testing.T) {
+		d, remaining := newDriver()
+		d.Bind(map[string]any{})
+		d.Bind(map[string]any{})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		var p tests.Person
+		require.NoError(t, d.Exec(WithTimeout(2*time.Minute)).Create(db.Node(&p)).Run(ctx))
+		require.NoError(t, d.Exec(WithTimeout(-1)).Create(db.Node(&p)).Run(context.Background()))
+		assert.Equal(t, []time.Duration{2 * time.Minute, 0}, remaining())
+	})
+
+	t.Run("bounds transactions", func(t *testing.T) {
+		d, remaining := newDriver()
+		d.Bind(map[string]any{"p": &tests.Person{}})
+		ctx := context.Background()
+		sess := d.ReadSession(ctx)
+		defer sess.Close(ctx)
+		err := sess.ReadTransaction(ctx, func(begin func() Query) error {
+			var p tests.Person
+			return begin().Match(db.Node(db.Qual(&p, "p"))).Return(&p).Run(ctx)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Minute}, remaining())
+	})
+
+	t.Run("bounds streamed queries", func(t *testing.T) {
+		d, remaining := newDriver()
+		d.Bind(map[string]any{"p": &tests.Person{}})
+		ctx := context.Background()
+		var p tests.Person
+		err := d.Exec().Match(db.Node(db.Qual(&p, "p"))).Return(&p).Stream(ctx, func(r query.Result) error {
+			for r.Next(ctx) {
+				if err := r.Read(); err != nil {
+					return err
+				}
+			}
+			return r.Err()
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Minute}, remaining())
+	})
+}
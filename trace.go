@@ -0,0 +1,41 @@
+package neogo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TraceContext returns the IDs of the trace and span active in ctx, or empty
+// strings if there are none. With OpenTelemetry, it can be implemented as:
+//
+//	func(ctx context.Context) (string, string) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsValid() {
+//			return "", ""
+//		}
+//		return sc.TraceID().String(), sc.SpanID().String()
+//	}
+type TraceContext func(ctx context.Context) (traceID, spanID string)
+
+// traceIDs returns the trace and span IDs of ctx, if tracing is configured.
+func (d *driver) traceIDs(ctx context.Context) (traceID, spanID string, ok bool) {
+	if d == nil || d.traceContext == nil {
+		return "", "", false
+	}
+	traceID, spanID = d.traceContext(ctx)
+	return traceID, spanID, traceID != ""
+}
+
+// traceCypher prefixes cypher with a comment identifying the trace and span
+// active in ctx, so the query can be correlated in the server's query log.
+func (d *driver) traceCypher(ctx context.Context, cypher string) string {
+	traceID, spanID, ok := d.traceIDs(ctx)
+	if !ok {
+		return cypher
+	}
+	// Comments are terminated by */, so IDs must not be able to close them.
+	traceID = strings.ReplaceAll(traceID, "*/", "")
+	spanID = strings.ReplaceAll(spanID, "*/", "")
+	return fmt.Sprintf("/* traceId=%s spanId=%s */ %s", traceID, spanID, cypher)
+}
@@ -0,0 +1,214 @@
+package neogo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// UnmarshalHook binds the values read by queries into values of a type,
+// rather than neogo's default unmarshalling. Hooks are created with
+// [NewUnmarshalHook] and registered with [WithUnmarshalHooks].
+type UnmarshalHook struct {
+	// Name identifies the hook in errors, and to [UnregisterHook].
+	Name string
+	// Priority orders the hooks of a type: those with lower priorities run
+	// first, and those with equal priorities in the order they were
+	// registered. Each hook is given the value bound by the previous ones, so
+	// later hooks can adjust the values bound by earlier hooks.
+	Priority int
+
+	t         reflect.Type
+	unmarshal func(from any, v any) error
+}
+
+// NewUnmarshalHook returns a hook named name binding the values read by
+// queries into values of type T. from is the value read from the database,
+// such as a [neo4j.Node] or a string. Hooks of other types are never called,
+// so they add no overhead when binding unrelated values.
+func NewUnmarshalHook[T any](name string, priority int, hook func(from any, v *T) error) UnmarshalHook {
+	return UnmarshalHook{
+		Name:     name,
+		Priority: priority,
+		t:        reflect.TypeOf((*T)(nil)).Elem(),
+		unmarshal: func(from any, v any) error {
+			return hook(from, v.(*T))
+		},
+	}
+}
+
+// hookSet holds the unmarshal hooks of each type and the processors of a
+// driver, which can be unregistered while it is in use with [UnregisterHook].
+type hookSet struct {
+	mu         sync.RWMutex
+	unmarshal  map[reflect.Type][]UnmarshalHook
+	processors processors
+}
+
+// newHookSet returns the hook set of the given unmarshal hooks, ordered by
+// priority, and processors.
+func newHookSet(hooks []UnmarshalHook, processors processors) *hookSet {
+	s := &hookSet{processors: processors}
+	for _, h := range hooks {
+		if s.unmarshal == nil {
+			s.unmarshal = make(map[reflect.Type][]UnmarshalHook)
+		}
+		s.unmarshal[h.t] = append(s.unmarshal[h.t], h)
+	}
+	for _, hooks := range s.unmarshal {
+		sortUnmarshalHooks(hooks)
+	}
+	return s
+}
+
+// sortUnmarshalHooks orders hooks by priority, keeping the order in which
+// hooks of equal priority were registered.
+func sortUnmarshalHooks(hooks []UnmarshalHook) {
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].Priority < hooks[j].Priority
+	})
+}
+
+// unmarshalHooks returns the unmarshal hooks of t, in the order they run.
+func (s *hookSet) unmarshalHooks(t reflect.Type) []UnmarshalHook {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.unmarshal[t]
+}
+
+// processorsOf returns the processors of t, in the order they run.
+func (s *hookSet) processorsOf(t reflect.Type) []Processor {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.processors[t]
+}
+
+// hasProcessors reports whether any type has processors.
+func (s *hookSet) hasProcessors() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.processors) > 0
+}
+
+// withQueryHooks returns the hook set of a query, whose unmarshal hooks
+// replace those of s of the same types. If withoutHooks is set, the unmarshal
+// hooks of s are left out.
+func (s *hookSet) withQueryHooks(hooks []UnmarshalHook, withoutHooks bool) *hookSet {
+	out := &hookSet{unmarshal: make(map[reflect.Type][]UnmarshalHook)}
+	if s != nil {
+		s.mu.RLock()
+		out.processors = s.processors
+		if !withoutHooks {
+			for t, hooks := range s.unmarshal {
+				out.unmarshal[t] = hooks
+			}
+		}
+		s.mu.RUnlock()
+	}
+	replaced := map[reflect.Type]bool{}
+	for _, h := range hooks {
+		if !replaced[h.t] {
+			replaced[h.t] = true
+			out.unmarshal[h.t] = nil
+		}
+		out.unmarshal[h.t] = append(out.unmarshal[h.t], h)
+	}
+	for t := range replaced {
+		sortUnmarshalHooks(out.unmarshal[t])
+	}
+	return out
+}
+
+// unregister removes the unmarshal hooks and processors named name, reporting
+// whether there were any. The slices read by running queries are not
+// modified.
+func (s *hookSet) unregister(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found bool
+	unmarshal := make(map[reflect.Type][]UnmarshalHook, len(s.unmarshal))
+	for t, hooks := range s.unmarshal {
+		var kept []UnmarshalHook
+		for _, h := range hooks {
+			if h.Name == name {
+				found = true
+			} else {
+				kept = append(kept, h)
+			}
+		}
+		if len(kept) > 0 {
+			unmarshal[t] = kept
+		}
+	}
+	processors := make(processors, len(s.processors))
+	for t, procs := range s.processors {
+		var kept []Processor
+		for _, p := range procs {
+			if p.Name == name {
+				found = true
+			} else {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) > 0 {
+			processors[t] = kept
+		}
+	}
+	s.unmarshal, s.processors = unmarshal, processors
+	return found
+}
+
+// UnregisterHook removes the unmarshal hooks and processors named name from
+// d, reporting whether there were any, i.e. to replace a hook registered by a
+// library layered on neogo. Queries already running keep the hooks they
+// started with.
+func UnregisterHook(d Driver, name string) bool {
+	impl := driverOf(d)
+	if impl == nil {
+		return false
+	}
+	return impl.hooks.unregister(name)
+}
+
+// bindHook binds from to to with the unmarshal hooks registered for the type
+// to points to, if any, allocating nil pointers along the way.
+func (r *registry) bindHook(from any, to reflect.Value) (ok bool, err error) {
+	if from == nil {
+		return false, nil
+	}
+	hooks := r.hooks.unmarshalHooks(unwindType(to.Type()))
+	if len(hooks) == 0 {
+		return false, nil
+	}
+	for to.Kind() == reflect.Ptr {
+		if to.IsNil() {
+			if !to.CanSet() {
+				return false, nil
+			}
+			to.Set(reflect.New(to.Type().Elem()))
+		}
+		to = to.Elem()
+	}
+	if !to.CanAddr() {
+		return false, nil
+	}
+	for _, h := range hooks {
+		if err := h.unmarshal(from, to.Addr().Interface()); err != nil {
+			if h.Name == "" {
+				return true, err
+			}
+			return true, fmt.Errorf("unmarshal hook %s failed: %w", h.Name, err)
+		}
+	}
+	return true, nil
+}
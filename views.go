@@ -0,0 +1,127 @@
+package neogo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rlch/neogo/query"
+)
+
+// ErrUnknownView is returned when refreshing a view which was not registered
+// with [WithViews].
+var ErrUnknownView = errors.New("unknown view")
+
+type (
+	// View is a named derived subgraph, such as summary nodes holding per-day
+	// aggregates, which is materialized by a refresh query rather than being
+	// recomputed on every read.
+	View struct {
+		// Name identifies the view when refreshing it.
+		Name string
+		// Query builds the query refreshing a chunk of the view.
+		Query func(c Query, chunk ViewChunk) query.Runner
+		// ChunkSize is the number of rows each chunk should refresh. When set,
+		// Query is run repeatedly, each chunk in its own transaction, until a
+		// chunk makes no updates. Otherwise, Query is run once.
+		ChunkSize int
+		// Every is the interval at which the view is refreshed by
+		// [Views.Start]. Views without an interval are only refreshed on demand.
+		Every time.Duration
+	}
+
+	// ViewChunk describes the chunk of a [View] being refreshed.
+	ViewChunk struct {
+		// Index is the index of the chunk, starting at 0.
+		Index int
+		// Skip is the number of rows refreshed by previous chunks.
+		Skip int
+		// Limit is the number of rows to refresh, or 0 if the view is not
+		// chunked.
+		Limit int
+	}
+
+	// Views refreshes the views registered with [WithViews].
+	Views interface {
+		// Refresh runs the refresh query of the view with the given name.
+		Refresh(ctx context.Context, name string) error
+		// LastRefreshed returns when the view with the given name was last
+		// successfully refreshed, or the zero time if it hasn't been.
+		LastRefreshed(name string) time.Time
+		// Start refreshes each view with an interval in the background, until ctx
+		// is done. Errors are passed to onError, if non-nil.
+		Start(ctx context.Context, onError func(name string, err error))
+	}
+)
+
+type viewsImpl struct {
+	d         *driver
+	views     map[string]View
+	mu        sync.Mutex
+	refreshed map[string]time.Time
+}
+
+var _ Views = (*viewsImpl)(nil)
+
+func newViews(d *driver, views []View) *viewsImpl {
+	v := &viewsImpl{
+		d:         d,
+		views:     make(map[string]View, len(views)),
+		refreshed: map[string]time.Time{},
+	}
+	for _, view := range views {
+		v.views[view.Name] = view
+	}
+	return v
+}
+
+func (v *viewsImpl) Refresh(ctx context.Context, name string) error {
+	view, ok := v.views[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownView, name)
+	}
+	for chunk := (ViewChunk{Limit: view.ChunkSize}); ; chunk.Index++ {
+		chunk.Skip = chunk.Index * view.ChunkSize
+		summary, err := view.Query(v.d.Exec(), chunk).RunSummary(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot refresh view %s (chunk %d): %w", name, chunk.Index, err)
+		}
+		if view.ChunkSize == 0 || !summary.Counters().ContainsUpdates() {
+			break
+		}
+	}
+	v.mu.Lock()
+	v.refreshed[name] = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *viewsImpl) LastRefreshed(name string) time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.refreshed[name]
+}
+
+func (v *viewsImpl) Start(ctx context.Context, onError func(name string, err error)) {
+	for name, view := range v.views {
+		if view.Every <= 0 {
+			continue
+		}
+		go func(name string, every time.Duration) {
+			ticker := time.NewTicker(every)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := v.Refresh(ctx, name); err != nil && onError != nil {
+						onError(name, err)
+					}
+				}
+			}
+		}(name, view.Every)
+	}
+}
@@ -0,0 +1,59 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/query"
+)
+
+func TestViews(t *testing.T) {
+	ctx := context.Background()
+
+	newMockViews := func(views ...View) (*mockDriverImpl, Views) {
+		m := NewMock().(*mockDriverImpl)
+		m.driver.views = newViews(m.driver, views)
+		return m, ViewsOf(m)
+	}
+
+	t.Run("refreshes views", func(t *testing.T) {
+		var chunks []ViewChunk
+		m, views := newMockViews(View{
+			Name: "dailySales",
+			Query: func(c Query, chunk ViewChunk) query.Runner {
+				chunks = append(chunks, chunk)
+				return c.Cypher("MERGE (d:DailySales {day: date()})")
+			},
+		})
+		m.Bind(nil)
+
+		require.True(t, views.LastRefreshed("dailySales").IsZero())
+		require.NoError(t, views.Refresh(ctx, "dailySales"))
+		assert.Equal(t, []ViewChunk{{}}, chunks)
+		assert.False(t, views.LastRefreshed("dailySales").IsZero())
+	})
+
+	t.Run("stops chunking when a chunk makes no updates", func(t *testing.T) {
+		var chunks []ViewChunk
+		m, views := newMockViews(View{
+			Name:      "dailySales",
+			ChunkSize: 100,
+			Query: func(c Query, chunk ViewChunk) query.Runner {
+				chunks = append(chunks, chunk)
+				return c.Cypher("MATCH (o:Order) WHERE o.day IS NULL WITH o LIMIT 100 SET o.day = date(o.createdAt)")
+			},
+		})
+		m.Bind(nil)
+
+		require.NoError(t, views.Refresh(ctx, "dailySales"))
+		assert.Equal(t, []ViewChunk{{Limit: 100}}, chunks)
+	})
+
+	t.Run("errors on unknown views", func(t *testing.T) {
+		_, views := newMockViews()
+		assert.ErrorIs(t, views.Refresh(ctx, "missing"), ErrUnknownView)
+	})
+}
@@ -0,0 +1,119 @@
+package neogo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+// ErrRunawayQuery is returned by queries cancelled by the watchdog configured
+// with [WithQueryWatchdog].
+var ErrRunawayQuery = errors.New("query exceeded the time limit of the watchdog")
+
+// RunawayQuery describes a query cancelled by the watchdog configured with
+// [WithQueryWatchdog].
+type RunawayQuery struct {
+	// ID identifies the query in the neogoQueryId metadata of its transaction.
+	ID string
+	// Cypher is the text of the query.
+	Cypher string
+	// Elapsed is the time the query ran for before being cancelled.
+	Elapsed time.Duration
+	// TerminateErr is the error terminating the transaction of the query on
+	// the server, if any.
+	TerminateErr error
+}
+
+// terminateCypher terminates the transactions of a query run by neogo.
+const terminateCypher = `SHOW TRANSACTIONS YIELD transactionId, metaData
+WHERE metaData.neogoQueryId = $id
+TERMINATE TRANSACTIONS transactionId`
+
+// terminateTimeout bounds the time spent terminating a runaway query.
+const terminateTimeout = 10 * time.Second
+
+type watchdog struct {
+	max       time.Duration
+	onRunaway func(RunawayQuery)
+	// terminate terminates the transactions of the query with the given ID on
+	// the server.
+	terminate func(ctx context.Context, id string) error
+	// nonce prefixes the IDs of queries, so that they are unique across the
+	// drivers, and processes, sharing a server.
+	nonce  string
+	lastID atomic.Uint64
+}
+
+// newWatchdogNonce returns a random prefix for the IDs of watched queries.
+func newWatchdogNonce() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b) + "-"
+}
+
+// watchedQueryKey is the context key of the ID of a watched query.
+type watchedQueryKey struct{}
+
+// warnRunawayQueries returns the default handler of runaway queries, warning
+// through logger, or nil without a logger.
+func warnRunawayQueries(logger log.Logger) func(RunawayQuery) {
+	if logger == nil {
+		return nil
+	}
+	return func(q RunawayQuery) {
+		logger.Warnf(logName, q.ID, "query cancelled after %s: %s", q.Elapsed, q.Cypher)
+	}
+}
+
+// watch tracks the query cypher until done is called with its error, which is
+// returned wrapping [ErrRunawayQuery] if it ran for longer than the watchdog
+// allows. The query must be run with ctx, which is cancelled if it does.
+func (d *driver) watch(ctx context.Context, cypher string) (_ context.Context, done func(error) error) {
+	if d == nil || d.watchdog == nil {
+		return ctx, func(err error) error { return err }
+	}
+	w := d.watchdog
+	id := w.nonce + strconv.FormatUint(w.lastID.Add(1), 10)
+	ctx, cancel := context.WithCancelCause(ctx)
+	ctx = context.WithValue(ctx, watchedQueryKey{}, id)
+	started := time.Now()
+	timer := time.AfterFunc(w.max, func() {
+		cancel(ErrRunawayQuery)
+		q := RunawayQuery{ID: id, Cypher: cypher, Elapsed: time.Since(started)}
+		if w.terminate != nil {
+			terminateCtx, cancel := context.WithTimeout(context.Background(), terminateTimeout)
+			defer cancel()
+			q.TerminateErr = w.terminate(terminateCtx, id)
+		}
+		if w.onRunaway != nil {
+			w.onRunaway(q)
+		}
+	})
+	return ctx, func(err error) error {
+		timer.Stop()
+		runaway := errors.Is(context.Cause(ctx), ErrRunawayQuery)
+		cancel(nil)
+		if err != nil && runaway && !errors.Is(err, ErrRunawayQuery) {
+			return fmt.Errorf("%w: %w", ErrRunawayQuery, err)
+		}
+		return err
+	}
+}
+
+// terminateQuery terminates the transactions of the query with the given ID
+// on the server.
+func (d *driver) terminateQuery(ctx context.Context, id string) error {
+	_, err := neo4j.ExecuteQuery(
+		ctx, d.db, terminateCypher, map[string]any{"id": id},
+		neo4j.EagerResultTransformer,
+	)
+	return err
+}
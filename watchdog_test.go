@@ -0,0 +1,115 @@
+package neogo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlch/neogo/db"
+	"github.com/rlch/neogo/internal/tests"
+)
+
+func TestQueryWatchdog(t *testing.T) {
+	newDriver := func(block bool) (mockDriver, chan RunawayQuery, *[]string) {
+		m := NewMock()
+		runaway := make(chan RunawayQuery, 1)
+		var terminated []string
+		m.(*mockDriverImpl).watchdog = &watchdog{
+			max: 20 * time.Millisecond,
+			onRunaway: func(q RunawayQuery) {
+				runaway <- q
+			},
+			terminate: func(_ context.Context, id string) error {
+				terminated = append(terminated, id)
+				return nil
+			},
+		}
+		// Parameters are transformed with the context of the query, which is
+		// enough to simulate a query running until it is cancelled.
+		m.(*mockDriverImpl).paramTransformers = []ParamTransformer{
+			ParamTransformerFunc(func(ctx context.Context, params map[string]any) (map[string]any, error) {
+				if !block {
+					return params, nil
+				}
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}),
+		}
+		return m, runaway, &terminated
+	}
+
+	t.Run("cancels and reports queries exceeding the limit", func(t *testing.T) {
+		d, runaway, terminated := newDriver(true)
+		var n tests.Person
+		err := d.Exec().
+			Match(db.Node(db.Qual(&n, "n"))).
+			Return(&n).
+			Run(context.Background())
+		require.ErrorIs(t, err, ErrRunawayQuery)
+		require.ErrorIs(t, err, context.Canceled)
+
+		q := <-runaway
+		assert.Equal(t, "1", q.ID)
+		assert.Equal(t, "MATCH (n:Person)\nRETURN n", q.Cypher)
+		assert.GreaterOrEqual(t, q.Elapsed, 20*time.Millisecond)
+		assert.NoError(t, q.TerminateErr)
+		assert.Equal(t, []string{"1"}, *terminated)
+	})
+
+	t.Run("leaves queries within the limit", func(t *testing.T) {
+		d, runaway, _ := newDriver(false)
+		d.Bind(map[string]any{"n": tests.Person{Name: "Spongebob"}})
+		var n tests.Person
+		err := d.Exec().
+			Match(db.Node(db.Qual(&n, "n"))).
+			Return(&n).
+			Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "Spongebob", n.Name)
+
+		time.Sleep(40 * time.Millisecond)
+		assert.Empty(t, runaway)
+	})
+
+	t.Run("attaches the query ID to the transaction metadata", func(t *testing.T) {
+		d := &driver{watchdog: &watchdog{max: time.Minute}}
+		ctx, done := d.watch(context.Background(), "RETURN 1")
+		defer done(nil)
+		assert.Equal(t,
+			map[string]any{"app": "neogo", "neogoQueryId": "1"},
+			d.txMetadata(ctx, map[string]any{"app": "neogo"}),
+		)
+		assert.Nil(t, d.txMetadata(context.Background(), nil))
+	})
+
+	t.Run("prefixes query IDs with a nonce", func(t *testing.T) {
+		ids := make([]string, 2)
+		for i := range ids {
+			d := &driver{watchdog: &watchdog{max: time.Minute, nonce: newWatchdogNonce()}}
+			ctx, done := d.watch(context.Background(), "RETURN 1")
+			ids[i] = ctx.Value(watchedQueryKey{}).(string)
+			done(nil)
+			assert.Regexp(t, `^[0-9a-f]{16}-1$`, ids[i])
+		}
+		assert.NotEqual(t, ids[0], ids[1])
+	})
+}
+
+func TestWarnRunawayQueries(t *testing.T) {
+	t.Run("does nothing without a logger", func(t *testing.T) {
+		require.Nil(t, warnRunawayQueries(nil))
+	})
+
+	t.Run("warns through the logger", func(t *testing.T) {
+		logger := &warnLogger{}
+		warnRunawayQueries(logger)(RunawayQuery{
+			ID:      "q-1",
+			Cypher:  "MATCH (n) RETURN n",
+			Elapsed: time.Second,
+		})
+		require.Equal(t, []string{"query cancelled after 1s: MATCH (n) RETURN n"}, logger.warnings)
+	})
+}